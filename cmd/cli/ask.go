@@ -0,0 +1,249 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/ipfs-force-community/venus-tool/repo/config"
+	"github.com/ipfs-force-community/venus-tool/service"
+	"github.com/urfave/cli/v2"
+)
+
+var minerAskTemplateCmd = &cli.Command{
+	Name:  "template",
+	Usage: "manage ask templates shared by miners",
+	Subcommands: []*cli.Command{
+		askTemplateListCmd,
+		askTemplateSetCmd,
+		askTemplateRemoveCmd,
+		askTemplateApplyCmd,
+		askDriftCmd,
+	},
+}
+
+var askTemplateListCmd = &cli.Command{
+	Name:  "list",
+	Usage: "list ask templates",
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		templates, err := api.AskTemplateList(cctx.Context)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "Name\tPrice\tVerifiedPrice\tMinPieceSize\tMaxPieceSize\tDuration\tRetrievalPrice\tUnsealPrice\tPaymentInterval\tPaymentIntervalIncrease\tMiners\n")
+		for _, t := range templates {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				t.Name,
+				orNone(t.Price),
+				orNone(t.VerifiedPrice),
+				orNone(t.MinPieceSize),
+				orNone(t.MaxPieceSize),
+				orNone(t.Duration),
+				orNone(t.RetrievalPrice),
+				orNone(t.UnsealPrice),
+				orNone(t.PaymentInterval),
+				orNone(t.PaymentIntervalIncrease),
+				orNone(strings.Join(t.Miners, ",")),
+			)
+		}
+		return w.Flush()
+	},
+}
+
+var askTemplateSetCmd = &cli.Command{
+	Name:      "set",
+	Usage:     "set an ask template, the old one with the same name will be replaced",
+	ArgsUsage: "<name>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "price",
+			Usage: "storage price per GiB per epoch, eg: 0.0000001FIL, storage ask is not managed if not set",
+		},
+		&cli.StringFlag{
+			Name:        "verified-price",
+			Usage:       "storage price per GiB per epoch of verified deals",
+			DefaultText: "same as price",
+		},
+		&cli.StringFlag{
+			Name:        "min-piece-size",
+			Usage:       "minimum piece size (w/bit-padding), eg. KiB, MiB, GiB",
+			DefaultText: "256B",
+		},
+		&cli.StringFlag{
+			Name:        "max-piece-size",
+			Usage:       "maximum piece size (w/bit-padding), eg. KiB, MiB, GiB",
+			DefaultText: "miner sector size",
+		},
+		&cli.StringFlag{
+			Name:        "duration",
+			Usage:       "how long the storage ask is valid, eg: 720h",
+			DefaultText: "720h",
+		},
+		&cli.StringFlag{
+			Name:  "retrieval-price",
+			Usage: "retrieval price per GiB, retrieval ask is not managed if not set",
+		},
+		&cli.StringFlag{
+			Name:        "unseal-price",
+			Usage:       "price to unseal for retrieval",
+			DefaultText: "0",
+		},
+		&cli.StringFlag{
+			Name:        "payment-interval",
+			Usage:       "payment interval (in bytes) for retrieval",
+			DefaultText: "1MiB",
+		},
+		&cli.StringFlag{
+			Name:        "payment-interval-increase",
+			Usage:       "payment interval increase (in bytes) for retrieval",
+			DefaultText: "1MiB",
+		},
+		&cli.StringSliceFlag{
+			Name:  "miner",
+			Usage: "miners assigned to the template, they are checked for drift",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return fmt.Errorf("'set' expects one argument, the template name")
+		}
+
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		t := &config.AskTemplate{
+			Name:                    cctx.Args().First(),
+			Price:                   cctx.String("price"),
+			VerifiedPrice:           cctx.String("verified-price"),
+			MinPieceSize:            cctx.String("min-piece-size"),
+			MaxPieceSize:            cctx.String("max-piece-size"),
+			Duration:                cctx.String("duration"),
+			RetrievalPrice:          cctx.String("retrieval-price"),
+			UnsealPrice:             cctx.String("unseal-price"),
+			PaymentInterval:         cctx.String("payment-interval"),
+			PaymentIntervalIncrease: cctx.String("payment-interval-increase"),
+			Miners:                  cctx.StringSlice("miner"),
+		}
+		if err := api.AskTemplateSet(cctx.Context, t); err != nil {
+			return err
+		}
+		fmt.Printf("ask template %s is set\n", t.Name)
+		return nil
+	},
+}
+
+var askTemplateRemoveCmd = &cli.Command{
+	Name:      "remove",
+	Usage:     "remove an ask template",
+	ArgsUsage: "<name>",
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return fmt.Errorf("'remove' expects one argument, the template name")
+		}
+
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		name := cctx.Args().First()
+		if err := api.AskTemplateRemove(cctx.Context, &service.AskTemplateRemoveReq{Name: name}); err != nil {
+			return err
+		}
+		fmt.Printf("ask template %s is removed\n", name)
+		return nil
+	},
+}
+
+var askTemplateApplyCmd = &cli.Command{
+	Name:      "apply",
+	Usage:     "apply an ask template to miners, preview the difference unless --really-do-it is set",
+	ArgsUsage: "<name> [miner address]...",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "really-do-it",
+			Usage: "actually set the asks of miners",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() < 1 {
+			return fmt.Errorf("must pass the template name, and miners to apply to, all miners if not set")
+		}
+
+		req := &service.AskTemplateApplyReq{
+			Name:   cctx.Args().First(),
+			DryRun: !cctx.Bool("really-do-it"),
+		}
+		for _, m := range cctx.Args().Tail() {
+			mAddr, err := address.NewFromString(m)
+			if err != nil {
+				return fmt.Errorf("failed to parse miner address: %w", err)
+			}
+			req.Miner = append(req.Miner, mAddr)
+		}
+
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		diffs, err := api.AskTemplateApply(cctx.Context, req)
+		if err != nil {
+			return err
+		}
+		if err := outputAskDiffs(diffs); err != nil {
+			return err
+		}
+		if req.DryRun {
+			fmt.Println("\nPass --really-do-it to actually execute this action")
+		}
+		return nil
+	},
+}
+
+var askDriftCmd = &cli.Command{
+	Name:  "drift",
+	Usage: "list miners whose asks deviate from their assigned templates",
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		diffs, err := api.AskDrift(cctx.Context)
+		if err != nil {
+			return err
+		}
+		if len(diffs) == 0 {
+			fmt.Println("no drift found")
+			return nil
+		}
+		return outputAskDiffs(diffs)
+	},
+}
+
+func outputAskDiffs(diffs []service.AskDiff) error {
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Miner\tTemplate\tField\tCurrent\tTemplateValue\tApplied\tError\n")
+	for _, d := range diffs {
+		if len(d.Fields) == 0 {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t-\t-\t-\t%t\t%s\n", d.Miner, d.Template, d.Applied, orNone(d.Error))
+			continue
+		}
+		for _, f := range d.Fields {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%t\t%s\n", d.Miner, d.Template, f.Field, f.Current, f.Template, d.Applied, orNone(d.Error))
+		}
+	}
+	return w.Flush()
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/docker/go-units"
 	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-fil-markets/retrievalmarket"
 	"github.com/filecoin-project/go-fil-markets/storagemarket"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/crypto"
@@ -28,11 +29,15 @@ import (
 )
 
 var StringToStorageState = map[string]storagemarket.StorageDealStatus{}
+var StringToRetrievalState = map[string]retrievalmarket.DealStatus{}
 
 func init() {
 	for state, stateStr := range storagemarket.DealStates {
 		StringToStorageState[stateStr] = state
 	}
+	for state, stateStr := range retrievalmarket.DealStatuses {
+		StringToRetrievalState[stateStr] = state
+	}
 }
 
 var FlagServer = &cli.StringFlag{
@@ -41,6 +46,12 @@ var FlagServer = &cli.StringFlag{
 	Value: "http://127.0.0.1:8090",
 }
 
+var FlagToken = &cli.StringFlag{
+	Name:    "token",
+	Usage:   "Specify the token of sophon-auth to identify the user, required by the operations recorded in the audit log and to request and handle policy approvals",
+	EnvVars: []string{"VENUS_TOOL_TOKEN"},
+}
+
 func getAPI(ctx *cli.Context) (service.IService, error) {
 	ret := &service.IServiceStruct{}
 
@@ -52,6 +63,9 @@ func getAPI(ctx *cli.Context) (service.IService, error) {
 	}
 
 	cli.SetVersion("/api/v0")
+	if token := ctx.String(FlagToken.Name); token != "" {
+		cli.SetAuthToken(token)
+	}
 
 	route.Provide(cli, &ret.Internal)
 	return ret, nil
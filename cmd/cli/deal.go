@@ -1,14 +1,21 @@
 package cli
 
 import (
+	"encoding/csv"
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
+	"github.com/docker/go-units"
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-fil-markets/retrievalmarket"
 	"github.com/filecoin-project/go-fil-markets/storagemarket"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/venus/venus-shared/types"
 	"github.com/filecoin-project/venus/venus-shared/types/market"
 	"github.com/ipfs-force-community/venus-tool/service"
 	"github.com/ipfs/go-cid"
@@ -27,6 +34,12 @@ var DealCmd = &cli.Command{
 	Subcommands: []*cli.Command{
 		dealListCmd,
 		dealUpdateCmd,
+		dealStatsCmd,
+		dealBulkUpdateCmd,
+		dealReconcileCmd,
+		dealImportCmd,
+		dealUnassignedCmd,
+		dealAssignCmd,
 	},
 }
 
@@ -39,6 +52,80 @@ var dealListCmd = &cli.Command{
 			Name:    "verbose",
 			Aliases: []string{"v"},
 		},
+		&cli.StringFlag{
+			Name:  "state",
+			Usage: "filter deals by state, eg: StorageDealActive, DealStatusCompleted",
+		},
+		&cli.StringFlag{
+			Name:  "receiver",
+			Usage: "filter retrieval deals by the peer id of receiver",
+		},
+		&cli.StringFlag{
+			Name:  "payload",
+			Usage: "filter retrieval deals by payload cid",
+		},
+		&cli.StringFlag{
+			Name:  "output",
+			Usage: "output format of retrieval deals: table | json | csv",
+			Value: "table",
+		},
+		&cli.StringFlag{
+			Name:  "piece",
+			Usage: "filter storage deals by piece cid",
+		},
+		&cli.StringFlag{
+			Name:  "client",
+			Usage: "filter storage deals by client address",
+		},
+		&cli.BoolFlag{
+			Name:  "verified",
+			Usage: "filter storage deals by verified flag, eg: --verified=false",
+		},
+		&cli.BoolFlag{
+			Name:  "include-inactive",
+			Usage: "include failing, slashed, expired and error storage deals",
+		},
+		&cli.TimestampFlag{
+			Name:     "created-after",
+			Usage:    "filter deals created after the time, eg: 2006-01-02T15:04:05",
+			Layout:   "2006-01-02T15:04:05",
+			Timezone: time.Local,
+		},
+		&cli.TimestampFlag{
+			Name:     "created-before",
+			Usage:    "filter deals created before the time, eg: 2006-01-02T15:04:05",
+			Layout:   "2006-01-02T15:04:05",
+			Timezone: time.Local,
+		},
+		&cli.Int64Flag{
+			Name:  "start-epoch-from",
+			Usage: "filter storage deals whose start epoch is not less than it",
+		},
+		&cli.Int64Flag{
+			Name:  "start-epoch-to",
+			Usage: "filter storage deals whose start epoch is not greater than it",
+		},
+		&cli.Int64Flag{
+			Name:  "end-epoch-from",
+			Usage: "filter storage deals whose end epoch is not less than it",
+		},
+		&cli.Int64Flag{
+			Name:  "end-epoch-to",
+			Usage: "filter storage deals whose end epoch is not greater than it",
+		},
+		&cli.BoolFlag{
+			Name:  "asc",
+			Usage: "order storage deals by creation time ascending",
+		},
+		&cli.IntFlag{
+			Name:  "offset",
+			Usage: "pagination offset of deals",
+		},
+		&cli.IntFlag{
+			Name:  "limit",
+			Usage: "pagination size of deals, 0 means no limit",
+			Value: 100,
+		},
 	},
 	Action: func(cctx *cli.Context) error {
 		ctx := cctx.Context
@@ -63,47 +150,313 @@ var dealListCmd = &cli.Command{
 		}
 
 		if cctx.Bool("retrieval") {
-			deals, err := api.RetrievalDealList(ctx)
+			req, err := parseRetrievalDealListReq(cctx, mAddr)
+			if err != nil {
+				return err
+			}
+			ret, err := api.RetrievalDealList(ctx, req)
 			if err != nil {
 				return err
 			}
-			w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
 
-			_, _ = fmt.Fprintf(w, "Receiver\tDealID\tPayload\tState\tPricePerByte\tBytesSent\tPaied\tInterval\tMessage\n")
+			switch cctx.String("output") {
+			case "table":
+				if err := outputRetrievalDeals(ret.Deals, verbose); err != nil {
+					return err
+				}
+				fmt.Printf("\n%d of %d deals shown\n", len(ret.Deals), ret.Total)
+				return nil
+			case "json":
+				return printJSON(ret)
+			case "csv":
+				return outputRetrievalDealsCSV(ret.Deals)
+			default:
+				return fmt.Errorf("unknown output format: %s", cctx.String("output"))
+			}
+		}
 
-			for _, deal := range deals {
-				payloadCid := deal.PayloadCID.String()
+		req := &service.StorageDealListReq{
+			PieceCID:        cctx.String("piece"),
+			IncludeInactive: cctx.Bool("include-inactive"),
+			StartEpochFrom:  abi.ChainEpoch(cctx.Int64("start-epoch-from")),
+			StartEpochTo:    abi.ChainEpoch(cctx.Int64("start-epoch-to")),
+			EndEpochFrom:    abi.ChainEpoch(cctx.Int64("end-epoch-from")),
+			EndEpochTo:      abi.ChainEpoch(cctx.Int64("end-epoch-to")),
+			Asc:             cctx.Bool("asc"),
+			Offset:          cctx.Int("offset"),
+			Limit:           cctx.Int("limit"),
+		}
+		if mAddr != address.Undef {
+			req.Miner = []address.Address{mAddr}
+		}
+		if cctx.IsSet("state") {
+			state, ok := StringToStorageState[cctx.String("state")]
+			if !ok {
+				return fmt.Errorf("unknown deal state: %s", cctx.String("state"))
+			}
+			req.State = &state
+		}
+		if cctx.IsSet("client") {
+			req.Client, err = address.NewFromString(cctx.String("client"))
+			if err != nil {
+				return fmt.Errorf("failed to parse client address: %w", err)
+			}
+		}
+		if cctx.IsSet("verified") {
+			verified := cctx.Bool("verified")
+			req.Verified = &verified
+		}
+		if cctx.IsSet("created-after") {
+			req.CreatedAfter = *cctx.Timestamp("created-after")
+		}
+		if cctx.IsSet("created-before") {
+			req.CreatedBefore = *cctx.Timestamp("created-before")
+		}
 
-				if !verbose {
-					payloadCid = "..." + payloadCid[len(payloadCid)-8:]
-				}
+		ret, err := api.StorageDealList(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		if err := outputStorageDeals(os.Stdout, ret.Deals, verbose); err != nil {
+			return err
+		}
+		fmt.Printf("\n%d of %d deals shown\n", len(ret.Deals), ret.Total)
+		return nil
+	},
+}
+
+func parseRetrievalDealListReq(cctx *cli.Context, mAddr address.Address) (*service.RetrievalDealListReq, error) {
+	req := &service.RetrievalDealListReq{
+		Receiver:   cctx.String("receiver"),
+		PayloadCID: cctx.String("payload"),
+		Offset:     cctx.Int("offset"),
+		Limit:      cctx.Int("limit"),
+	}
+	if mAddr != address.Undef {
+		req.Miner = []address.Address{mAddr}
+	}
+	if cctx.IsSet("state") {
+		state, ok := StringToRetrievalState[cctx.String("state")]
+		if !ok {
+			return nil, fmt.Errorf("unknown retrieval deal state: %s", cctx.String("state"))
+		}
+		req.Status = &state
+	}
+	if cctx.IsSet("created-after") {
+		req.CreatedAfter = *cctx.Timestamp("created-after")
+	}
+	if cctx.IsSet("created-before") {
+		req.CreatedBefore = *cctx.Timestamp("created-before")
+	}
+	return req, nil
+}
+
+func outputRetrievalDeals(deals []service.RetrievalDeal, verbose bool) error {
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+
+	_, _ = fmt.Fprintf(w, "Miner\tReceiver\tDealID\tPayload\tState\tPricePerByte\tBytesSent\tPaied\tInterval\tMessage\n")
+
+	for _, deal := range deals {
+		payloadCid := deal.PayloadCID.String()
+
+		if !verbose {
+			payloadCid = "..." + payloadCid[len(payloadCid)-8:]
+		}
+
+		_, _ = fmt.Fprintf(w,
+			"%s\t%s\t%d\t%s\t%s\t%s\t%d\t%d\t%d\t%s\n",
+			deal.Miner,
+			deal.Receiver.String(),
+			deal.ID,
+			payloadCid,
+			retrievalmarket.DealStatuses[deal.Status],
+			deal.PricePerByte.String(),
+			deal.TotalSent,
+			deal.FundsReceived,
+			deal.CurrentInterval,
+			deal.Message,
+		)
+	}
+
+	return w.Flush()
+}
+
+func outputRetrievalDealsCSV(deals []service.RetrievalDeal) error {
+	w := csv.NewWriter(os.Stdout)
+	err := w.Write([]string{"Miner", "Receiver", "DealID", "Payload", "State", "PricePerByte", "BytesSent", "Paied", "Interval", "CreatedAt", "Message"})
+	if err != nil {
+		return err
+	}
+	for _, deal := range deals {
+		err := w.Write([]string{
+			deal.Miner.String(),
+			deal.Receiver.String(),
+			strconv.FormatUint(uint64(deal.ID), 10),
+			deal.PayloadCID.String(),
+			retrievalmarket.DealStatuses[deal.Status],
+			deal.PricePerByte.String(),
+			strconv.FormatUint(deal.TotalSent, 10),
+			deal.FundsReceived.String(),
+			strconv.FormatUint(deal.CurrentInterval, 10),
+			time.Unix(int64(deal.CreatedAt), 0).Format(time.RFC3339),
+			deal.Message,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
 
-				_, _ = fmt.Fprintf(w,
-					"%s\t%d\t%s\t%s\t%s\t%d\t%d\t%d\t%s\n",
-					deal.Receiver.String(),
-					deal.ID,
-					payloadCid,
-					retrievalmarket.DealStatuses[deal.Status],
-					deal.PricePerByte.String(),
-					deal.TotalSent,
-					deal.FundsReceived,
-					deal.CurrentInterval,
-					deal.Message,
-				)
+var dealStatsCmd = &cli.Command{
+	Name:      "stats",
+	Usage:     "Show statistics of storage deals grouped by deal state and piece status, or retrieval deals grouped by miner",
+	ArgsUsage: "[miner address]",
+	Flags: []cli.Flag{
+		&cli.IntFlag{
+			Name:  "days",
+			Usage: "number of days of the trend of storage deals",
+			Value: 7,
+		},
+		&cli.TimestampFlag{
+			Name:     "created-after",
+			Usage:    "only stat retrieval deals created after the time, eg: 2006-01-02T15:04:05",
+			Layout:   "2006-01-02T15:04:05",
+			Timezone: time.Local,
+		},
+		&cli.TimestampFlag{
+			Name:     "created-before",
+			Usage:    "only stat retrieval deals created before the time, eg: 2006-01-02T15:04:05",
+			Layout:   "2006-01-02T15:04:05",
+			Timezone: time.Local,
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		req := &service.DealStatsReq{Days: cctx.Int("days")}
+		switch cctx.NArg() {
+		case 0:
+		case 1:
+			mAddr, err := address.NewFromString(cctx.Args().First())
+			if err != nil {
+				return err
 			}
+			req.Miner = []address.Address{mAddr}
+		default:
+			return fmt.Errorf("too many arguments")
+		}
 
-			return w.Flush()
+		if cctx.Bool("retrieval") {
+			return retrievalDealStats(cctx, api, req.Miner)
 		}
 
-		deals, err := api.StorageDealList(ctx, service.Address{Address: mAddr})
+		stats, err := api.DealStats(cctx.Context, req)
 		if err != nil {
 			return err
 		}
 
-		return outputStorageDeals(os.Stdout, deals, verbose)
+		fmt.Printf("Total: %d deals, %s\n\n", stats.Total, types.SizeStr(types.NewInt(uint64(stats.PieceSize))))
+
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "State\tCount\tPieceSize\t<1d\t1-3d\t3-7d\t>7d\tOldest\tSince\n")
+		for _, st := range stats.States {
+			_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%s\n",
+				st.StateName,
+				st.Count,
+				types.SizeStr(types.NewInt(uint64(st.PieceSize))),
+				formatDealAge(st.Age, st.Oldest),
+			)
+		}
+		_, _ = fmt.Fprintf(w, "\t\t\t\t\t\t\t\t\n")
+		_, _ = fmt.Fprintf(w, "PieceStatus\tCount\tPieceSize\t<1d\t1-3d\t3-7d\t>7d\tOldest\tSince\n")
+		for _, ps := range stats.PieceStatuses {
+			_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%s\n",
+				ps.PieceStatus,
+				ps.Count,
+				types.SizeStr(types.NewInt(uint64(ps.PieceSize))),
+				formatDealAge(ps.Age, ps.Oldest),
+			)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		fmt.Println()
+		w = tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "Date\tAccepted\tSealed\tFailed\n")
+		for _, d := range stats.Trend {
+			_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%d\n", d.Date, d.Accepted, d.Sealed, d.Failed)
+		}
+		return w.Flush()
 	},
 }
 
+func retrievalDealStats(cctx *cli.Context, api service.IService, miners []address.Address) error {
+	req := &service.RetrievalDealListReq{Miner: miners}
+	if cctx.IsSet("created-after") {
+		req.CreatedAfter = *cctx.Timestamp("created-after")
+	}
+	if cctx.IsSet("created-before") {
+		req.CreatedBefore = *cctx.Timestamp("created-before")
+	}
+
+	stats, err := api.RetrievalDealStats(cctx.Context, req)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Miner\tDeals\tCompleted\tFailed\tBytesSent\tFundsReceived\n")
+	for _, st := range stats {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%s\t%s\n",
+			st.Miner,
+			st.Deals,
+			st.Completed,
+			st.Failed,
+			units.BytesSize(float64(st.BytesSent)),
+			types.FIL(st.FundsReceived),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, st := range stats {
+		if len(st.FailureReasons) == 0 {
+			continue
+		}
+		fmt.Printf("\nFailure reasons of %s:\n", st.Miner)
+		reasons := make([]string, 0, len(st.FailureReasons))
+		for reason := range st.FailureReasons {
+			reasons = append(reasons, reason)
+		}
+		sort.Slice(reasons, func(i, j int) bool {
+			return st.FailureReasons[reasons[i]] > st.FailureReasons[reasons[j]]
+		})
+		for _, reason := range reasons {
+			fmt.Printf("  %d\t%s\n", st.FailureReasons[reason], reason)
+		}
+	}
+	return nil
+}
+
+func formatDealAge(age service.DealAgeStats, oldest service.DealBrief) string {
+	return fmt.Sprintf("%d\t%d\t%d\t%d\t%s\t%s",
+		age.LessThan1Day,
+		age.OneToThreeDays,
+		age.ThreeToSevenDays,
+		age.MoreThan7Days,
+		oldest.ProposalCid,
+		oldest.UpdatedAt.Format(time.RFC3339),
+	)
+}
+
 var dealUpdateCmd = &cli.Command{
 	Name:  "update",
 	Usage: "Update deal",
@@ -178,6 +531,384 @@ var dealUpdateCmd = &cli.Command{
 	},
 }
 
+var dealBulkUpdateCmd = &cli.Command{
+	Name:  "bulk-update",
+	Usage: "Update state of storage deals selected by filter, preview the transitions unless --really-do-it is set",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "miner",
+			Usage: "miners of the deals, all miners if not set",
+		},
+		&cli.StringFlag{
+			Name:  "state",
+			Usage: "select deals in the state",
+		},
+		&cli.StringFlag{
+			Name:  "piece-state",
+			Usage: "select deals with the piece state",
+		},
+		&cli.DurationFlag{
+			Name:  "stuck-for",
+			Usage: "select deals not updated for the duration, eg: 72h",
+		},
+		&cli.StringFlag{
+			Name:  "to-state",
+			Usage: "the state to update to, empty means no change",
+		},
+		&cli.StringFlag{
+			Name:  "to-piece-state",
+			Usage: "Undefine | Assigned | Packing | Proving, empty means no change",
+		},
+		&cli.BoolFlag{
+			Name:  "really-do-it",
+			Usage: "Actually update the deals, token is required to identify the operator",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		req := &service.StorageDealBulkUpdateReq{
+			DryRun:      !cctx.Bool("really-do-it"),
+			PieceStatus: market.PieceStatus(cctx.String("to-piece-state")),
+			// deals to recover are usually failing or in error
+			Filter: service.StorageDealListReq{IncludeInactive: true},
+		}
+		for _, m := range cctx.StringSlice("miner") {
+			mAddr, err := address.NewFromString(m)
+			if err != nil {
+				return fmt.Errorf("failed to parse miner address: %w", err)
+			}
+			req.Filter.Miner = append(req.Filter.Miner, mAddr)
+		}
+		if cctx.IsSet("state") {
+			state, ok := StringToStorageState[cctx.String("state")]
+			if !ok {
+				return fmt.Errorf("unknown deal state: %s", cctx.String("state"))
+			}
+			req.Filter.State = &state
+		}
+		if cctx.IsSet("piece-state") {
+			req.Filter.PieceStatus = market.PieceStatus(cctx.String("piece-state"))
+		}
+		if cctx.IsSet("to-state") {
+			state, ok := StringToStorageState[cctx.String("to-state")]
+			if !ok {
+				return fmt.Errorf("unknown deal state: %s", cctx.String("to-state"))
+			}
+			req.State = state
+		}
+		if cctx.IsSet("stuck-for") {
+			req.UpdatedBefore = time.Now().Add(-cctx.Duration("stuck-for"))
+		}
+		if !req.DryRun && cctx.String(FlagToken.Name) == "" {
+			return fmt.Errorf("token is required to identify the operator")
+		}
+
+		ret, err := api.StorageDealBulkUpdate(cctx.Context, req)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "ProposalCid\tMiner\tState\tPieceState\tApplied\tError\n")
+		for _, r := range ret.Results {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s -> %s\t%s -> %s\t%t\t%s\n",
+				r.ProposalCid,
+				r.Miner,
+				storagemarket.DealStates[r.FromState],
+				storagemarket.DealStates[r.ToState],
+				r.FromPieceStatus,
+				r.ToPieceStatus,
+				r.Applied,
+				orNone(r.Error),
+			)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		if ret.DryRun {
+			fmt.Printf("\n%d deals matched, %d illegal transitions, pass --really-do-it to actually execute this action\n", ret.Matched, ret.Failed)
+			return nil
+		}
+		fmt.Printf("\n%d deals matched, %d updated, %d failed\n", ret.Matched, ret.Applied, ret.Failed)
+		return nil
+	},
+}
+
+var dealReconcileCmd = &cli.Command{
+	Name:  "reconcile",
+	Usage: "Compare deals on chain with droplet and report mismatches, fix the state of deals in droplet if --really-do-it is set",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "miner",
+			Usage: "miners to check, all miners if not set",
+		},
+		&cli.BoolFlag{
+			Name:  "really-do-it",
+			Usage: "Actually update the state of deals in droplet, token is required to identify the operator",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		req := &service.DealReconcileReq{
+			Apply: cctx.Bool("really-do-it"),
+		}
+		for _, m := range cctx.StringSlice("miner") {
+			mAddr, err := address.NewFromString(m)
+			if err != nil {
+				return fmt.Errorf("failed to parse miner address: %w", err)
+			}
+			req.Miner = append(req.Miner, mAddr)
+		}
+		if req.Apply && cctx.String(FlagToken.Name) == "" {
+			return fmt.Errorf("token is required to identify the operator")
+		}
+
+		ret, err := api.DealReconcile(cctx.Context, req)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "DealID\tMiner\tKind\tLocalState\tSector(local/chain)\tStart\tActivated\tSlashed\tAction\tApplied\tError\n")
+		for _, mm := range ret.Mismatches {
+			localState, action := "none", "none"
+			if mm.ProposalCid != nil {
+				localState = storagemarket.DealStates[mm.LocalState]
+			}
+			if mm.Action != storagemarket.StorageDealUnknown {
+				action = storagemarket.DealStates[mm.Action]
+			}
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d/%d\t%d\t%d\t%d\t%s\t%t\t%s\n",
+				mm.DealID,
+				mm.Miner,
+				mm.Kind,
+				localState,
+				mm.LocalSector,
+				mm.ChainSector,
+				mm.StartEpoch,
+				mm.SectorStartEpoch,
+				mm.SlashEpoch,
+				action,
+				mm.Applied,
+				orNone(mm.Error),
+			)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		if ret.DryRun {
+			fmt.Printf("\n%d deals checked at height %d, %d mismatches, pass --really-do-it to actually fix the state of deals\n", ret.Checked, ret.Height, len(ret.Mismatches))
+			return nil
+		}
+		fmt.Printf("\n%d deals checked at height %d, %d mismatches, %d updated, %d failed\n", ret.Checked, ret.Height, len(ret.Mismatches), ret.Applied, ret.Failed)
+		return nil
+	},
+}
+
+var dealImportCmd = &cli.Command{
+	Name:      "import",
+	Usage:     "Import data of offline deals",
+	ArgsUsage: "[manifest file]",
+	Description: `The manifest file contains one deal per line, the proposal cid and the path of the car file
+separated by comma or space, empty lines and lines starting with '#' are ignored.
+The car files should be accessible by both venus-tool and droplet with the same path.`,
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "proposal-cid",
+			Usage: "proposal cid of the deal to import, used without manifest",
+		},
+		&cli.StringFlag{
+			Name:  "file",
+			Usage: "path of the car file, used without manifest",
+		},
+		&cli.BoolFlag{
+			Name:  "skip-commp",
+			Usage: "skip checking the commP of the car file, the data is not verified at all",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		req := &service.DealImportReq{SkipCommP: cctx.Bool("skip-commp")}
+		switch cctx.NArg() {
+		case 0:
+			if !cctx.IsSet("proposal-cid") || !cctx.IsSet("file") {
+				return fmt.Errorf("must set manifest file or both 'proposal-cid' and 'file'")
+			}
+			proposalCid, err := cid.Decode(cctx.String("proposal-cid"))
+			if err != nil {
+				return fmt.Errorf("failed to parse proposal cid: %w", err)
+			}
+			req.Refs = append(req.Refs, service.DealImportRef{ProposalCid: proposalCid, File: cctx.String("file")})
+		case 1:
+			refs, err := parseDealImportManifest(cctx.Args().First())
+			if err != nil {
+				return err
+			}
+			req.Refs = refs
+		default:
+			return fmt.Errorf("too many arguments")
+		}
+
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		results, err := api.DealImport(cctx.Context, req)
+		if err != nil {
+			return err
+		}
+
+		failed := 0
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "ProposalCid\tFile\tResult\n")
+		for _, r := range results {
+			result := "imported"
+			if r.Error != "" {
+				result = r.Error
+				failed++
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", r.ProposalCid, r.File, result)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		fmt.Printf("\n%d deals imported, %d failed\n", len(results)-failed, failed)
+		return nil
+	},
+}
+
+func parseDealImportManifest(path string) ([]service.DealImportRef, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var refs []service.DealImportRef
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.FieldsFunc(line, func(r rune) bool {
+			return r == ',' || r == ' ' || r == '\t'
+		})
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expects proposal cid and file path", i+1)
+		}
+		proposalCid, err := cid.Decode(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: failed to parse proposal cid: %w", i+1, err)
+		}
+		refs = append(refs, service.DealImportRef{ProposalCid: proposalCid, File: fields[1]})
+	}
+	if len(refs) == 0 {
+		return nil, fmt.Errorf("no deal found in manifest")
+	}
+	return refs, nil
+}
+
+var dealUnassignedCmd = &cli.Command{
+	Name:      "unassigned",
+	Usage:     "List pieces of deals not assigned to any sector",
+	ArgsUsage: "[miner address]",
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		req := &service.DealUnassignedPiecesReq{}
+		switch cctx.NArg() {
+		case 0:
+		case 1:
+			mAddr, err := address.NewFromString(cctx.Args().First())
+			if err != nil {
+				return err
+			}
+			req.Miner = []address.Address{mAddr}
+		default:
+			return fmt.Errorf("too many arguments")
+		}
+
+		deals, err := api.DealUnassignedPieces(cctx.Context, req)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "Miner\tDealID\tPieceCID\tPieceSize\tStartEpoch\tCreated\n")
+		for _, deal := range deals {
+			proposal := deal.ClientDealProposal.Proposal
+			_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%d\t%s\n",
+				proposal.Provider,
+				deal.DealID,
+				proposal.PieceCID,
+				types.SizeStr(types.NewInt(uint64(proposal.PieceSize))),
+				proposal.StartEpoch,
+				deal.CreationTime.Time().Format(time.RFC3339),
+			)
+		}
+		return w.Flush()
+	},
+}
+
+var dealAssignCmd = &cli.Command{
+	Name:      "assign",
+	Usage:     "Assign the piece of a deal to a sector",
+	ArgsUsage: "<miner address> <deal id> <sector number>",
+	Flags: []cli.Flag{
+		&cli.Uint64Flag{
+			Name:  "offset",
+			Usage: "padded offset of the piece in the sector",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 3 {
+			return fmt.Errorf("'assign' expects three arguments, the miner, deal id and sector number")
+		}
+
+		mAddr, err := address.NewFromString(cctx.Args().Get(0))
+		if err != nil {
+			return fmt.Errorf("failed to parse miner address: %w", err)
+		}
+		dealID, err := strconv.ParseUint(cctx.Args().Get(1), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse deal id: %w", err)
+		}
+		sector, err := strconv.ParseUint(cctx.Args().Get(2), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse sector number: %w", err)
+		}
+
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		err = api.DealAssignPiece(cctx.Context, &service.DealAssignPieceReq{
+			Miner:  mAddr,
+			DealID: abi.DealID(dealID),
+			Sector: abi.SectorNumber(sector),
+			Offset: abi.PaddedPieceSize(cctx.Uint64("offset")),
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("deal %d is assigned to sector %d\n", dealID, sector)
+		return nil
+	},
+}
+
 var dealStateUsage = func() string {
 	const c, spliter = 5, " | "
 	size := len(StringToStorageState)
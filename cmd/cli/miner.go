@@ -35,6 +35,8 @@ var MinerCmd = &cli.Command{
 		minerSetBeneficiaryCmd,
 		minerWithdrawToBeneficiaryCmd,
 		minerWithdrawFromMarketCmd,
+		minerAddMarketBalanceCmd,
+		minerCollateralPlanCmd,
 	},
 }
 
@@ -44,6 +46,7 @@ var minerAskCmd = &cli.Command{
 	Subcommands: []*cli.Command{
 		minerGetAskCmd,
 		minerSetAskCmd,
+		minerAskTemplateCmd,
 	},
 	Flags: []cli.Flag{
 		&cli.BoolFlag{
@@ -870,3 +873,102 @@ var minerWithdrawFromMarketCmd = &cli.Command{
 		return nil
 	},
 }
+
+var minerAddMarketBalanceCmd = &cli.Command{
+	Name:      "add-market-balance",
+	Usage:     "add balance to the market escrow of miner",
+	ArgsUsage: "<minerAddress> <amount>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "from",
+			Usage: "the wallet to pay, default is the worker of miner",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		ctx := cctx.Context
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		if cctx.NArg() != 2 {
+			return fmt.Errorf("must pass miner address and amount as arguments")
+		}
+
+		mAddr, err := address.NewFromString(cctx.Args().First())
+		if err != nil {
+			return err
+		}
+
+		amount, err := types.ParseFIL(cctx.Args().Get(1))
+		if err != nil {
+			return err
+		}
+
+		req := &service.MarketAddBalanceReq{
+			Miner:  mAddr,
+			Amount: abi.TokenAmount(amount),
+		}
+
+		if cctx.IsSet("from") {
+			req.From, err = address.NewFromString(cctx.String("from"))
+			if err != nil {
+				return err
+			}
+		}
+
+		fmt.Println("This will take some time (maybe 5 epoch), to ensure message is chained...")
+		added, err := api.MarketAddBalance(ctx, req)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Balance of %s has been added \n", types.FIL(added))
+
+		return nil
+	},
+}
+
+var minerCollateralPlanCmd = &cli.Command{
+	Name:      "collateral-plan",
+	Usage:     "estimate the provider collateral needed by pending deals and recommend a top-up amount",
+	ArgsUsage: "<minerAddress>",
+	Action: func(cctx *cli.Context) error {
+		ctx := cctx.Context
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		if cctx.NArg() != 1 {
+			return fmt.Errorf("must pass miner address as argument")
+		}
+
+		mAddr, err := address.NewFromString(cctx.Args().First())
+		if err != nil {
+			return err
+		}
+
+		plan, err := api.MarketCollateralPlan(ctx, service.Address{Address: mAddr})
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "Escrow:\t%s\n", types.FIL(plan.Escrow))
+		_, _ = fmt.Fprintf(w, "Locked:\t%s\n", types.FIL(plan.Locked))
+		_, _ = fmt.Fprintf(w, "Reserved:\t%s\n", types.FIL(plan.Reserved))
+		_, _ = fmt.Fprintf(w, "Available:\t%s\n", types.FIL(plan.Available))
+		_, _ = fmt.Fprintf(w, "Pending Deals:\t%d\n", plan.PendingDeals)
+		_, _ = fmt.Fprintf(w, "Pending Collateral:\t%s\n", types.FIL(plan.PendingCollateral))
+		_, _ = fmt.Fprintf(w, "Recommended Top-up:\t%s\n", types.FIL(plan.TopUp))
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		if plan.Warning != "" {
+			fmt.Printf("\nWARNING: %s, run 'venus-tool miner add-market-balance %s %s' to top up\n", plan.Warning, mAddr, types.FIL(plan.TopUp).Short())
+		}
+		return nil
+	},
+}
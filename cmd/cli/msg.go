@@ -1,20 +1,28 @@
 package cli
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
+	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/filecoin-project/venus/venus-shared/actors/builtin"
 	"github.com/ipfs-force-community/venus-tool/service"
+	"github.com/ipfs-force-community/venus-tool/utils"
 	"github.com/urfave/cli/v2"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/exitcode"
 	"github.com/filecoin-project/venus/venus-shared/types"
 	"github.com/filecoin-project/venus/venus-shared/types/messager"
 	msgTypes "github.com/filecoin-project/venus/venus-shared/types/messager"
+	"github.com/ipfs/go-cid"
 )
 
 var (
@@ -41,49 +49,223 @@ var MsgCmd = &cli.Command{
 		msgSendCmd,
 		msgListCmd,
 		msgReplaceCmd,
+		msgBuildCmd,
+		msgSubmitSignedCmd,
+		msgParamsSchemaCmd,
 	},
 }
 
-var msgSendCmd = &cli.Command{
-	Name:      "send",
-	Usage:     "Send a message",
-	ArgsUsage: "<targetAddress> <amount>",
-	Flags: []cli.Flag{
-		flagFrom,
-		&cli.Uint64Flag{
-			Name:  "method",
-			Usage: "specify method to invoke",
-			Value: uint64(builtin.MethodSend),
-		},
-		&cli.StringFlag{
-			Name:  "params-json",
-			Usage: "specify invocation parameters in json",
-		},
-		&cli.StringFlag{
-			Name:  "params-hex",
-			Usage: "specify invocation parameters in hex",
-		},
-		&cli.StringFlag{
-			Name:  "max-fee",
-			Usage: "indicate the max fee can be used to send message in AttoFIL",
-			Value: "0",
-		},
-		&cli.Float64Flag{
-			Name:  "gas-over-premium",
-			Usage: "the ratio of gas premium base on estimated gas premium",
-			Value: 0,
-		},
+var msgSendFlags = []cli.Flag{
+	flagFrom,
+	&cli.Uint64Flag{
+		Name:  "method",
+		Usage: "specify method to invoke",
+		Value: uint64(builtin.MethodSend),
+	},
+	&cli.StringFlag{
+		Name:  "method-name",
+		Usage: "specify method to invoke by name, eg: ChangeWorkerAddress. params will be prompted if neither 'params-json' nor 'params-hex' is set",
+	},
+	&cli.StringFlag{
+		Name:  "params-json",
+		Usage: "specify invocation parameters in json",
+	},
+	&cli.StringFlag{
+		Name:  "params-hex",
+		Usage: "specify invocation parameters in hex",
+	},
+	&cli.StringFlag{
+		Name:  "max-fee",
+		Usage: "indicate the max fee can be used to send message in AttoFIL",
+		Value: "0",
+	},
+	&cli.Float64Flag{
+		Name:  "gas-over-premium",
+		Usage: "the ratio of gas premium base on estimated gas premium",
+		Value: 0,
+	},
 
-		&cli.Float64Flag{
-			Name:  "gas-over-estimation",
-			Usage: "the ratio of gas limit base on estimated gas used",
-			Value: 0,
-		},
-		flagVerbose,
+	&cli.Float64Flag{
+		Name:  "gas-over-estimation",
+		Usage: "the ratio of gas limit base on estimated gas used",
+		Value: 0,
 	},
+}
+
+// parseMsgSendReq parses the args <targetAddress> <amount> and the flags in msgSendFlags
+func parseMsgSendReq(cctx *cli.Context) (*service.MsgSendReq, error) {
+	if cctx.Args().Len() != 2 {
+		return nil, fmt.Errorf("'%s' expects two arguments, target and amount", cctx.Command.Name)
+	}
+
+	var err error
+	var req service.MsgSendReq
+	req.To, err = address.NewFromString(cctx.Args().Get(0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse target address: %w", err)
+	}
+
+	val, err := types.ParseFIL(cctx.Args().Get(1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse amount: %w", err)
+	}
+	req.Value = abi.TokenAmount(val)
+
+	addr, err := address.NewFromString(cctx.String("from"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse from address: %w", err)
+	}
+	req.From = addr
+
+	req.Method = abi.MethodNum(cctx.Uint64("method"))
+	req.MethodName = cctx.String("method-name")
+
+	gfc, err := types.BigFromString(cctx.String("max-fee"))
+	if err != nil {
+		return nil, err
+	}
+	req.MaxFee = gfc
+
+	req.GasOverPremium = cctx.Float64("gas-over-premium")
+
+	req.GasOverEstimation = cctx.Float64("gas-over-estimation")
+
+	if cctx.IsSet("params-json") && cctx.IsSet("params-hex") {
+		return nil, fmt.Errorf("can only specify one of 'params-json' and 'params-hex'")
+	}
+	if cctx.IsSet("params-json") {
+		req.Params = &service.EncodedParams{
+			Data:    cctx.String("params-json"),
+			EncType: service.EncJson,
+		}
+	}
+	if cctx.IsSet("params-hex") {
+		req.Params = &service.EncodedParams{
+			Data:    cctx.String("params-hex"),
+			EncType: service.EncHex,
+		}
+	}
+
+	return &req, nil
+}
+
+// completeParamsBySchema validates the json params against the schema of the method specified by name,
+// or prompts the user to fill the params field by field if no params was given
+func completeParamsBySchema(cctx *cli.Context, api service.IService, req *service.MsgSendReq) error {
+	if req.MethodName == "" || (req.Params != nil && req.Params.EncType != service.EncJson) {
+		return nil
+	}
+
+	ret, err := api.MsgParamsSchema(cctx.Context, &service.MsgParamsSchemaReq{
+		To:         req.To,
+		MethodName: req.MethodName,
+	})
+	if err != nil {
+		return err
+	}
+	req.Method = ret.Method
+	if ret.Schema == nil {
+		return nil
+	}
+
+	if req.Params != nil {
+		if err := ret.Schema.Validate([]byte(req.Params.Data)); err != nil {
+			return fmt.Errorf("params of %s mismatch: %w\ntemplate: %s", ret.MethodName, err, ret.Template)
+		}
+		return nil
+	}
+
+	if fi, err := os.Stdin.Stat(); err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return fmt.Errorf("params of %s is required, template: %s", ret.MethodName, ret.Template)
+	}
+	data, err := promptParams(ret.MethodName, ret.Schema)
+	if err != nil {
+		return err
+	}
+	req.Params = &service.EncodedParams{
+		Data:    string(data),
+		EncType: service.EncJson,
+	}
+	return nil
+}
+
+func promptParams(methodName string, schema *utils.JSONSchema) ([]byte, error) {
+	if schema.Type != "object" || len(schema.Order) == 0 {
+		fmt.Printf("params of %s (json): ", methodName)
+		return promptValue(schema, true)
+	}
+
+	required := make(map[string]bool, len(schema.Required))
+	for _, name := range schema.Required {
+		required[name] = true
+	}
+
+	fmt.Printf("fill params of %s, input json for object and array fields, leave optional fields empty to skip\n", methodName)
+	params := make(map[string]json.RawMessage, len(schema.Order))
+	for _, name := range schema.Order {
+		prop := schema.Properties[name]
+		desc := prop.Type
+		if prop.Format != "" {
+			desc += ", " + prop.Format
+		}
+		if !required[name] {
+			desc += ", optional"
+		}
+		for {
+			fmt.Printf("%s (%s): ", name, desc)
+			val, err := promptValue(prop, required[name])
+			if err != nil {
+				if err == io.EOF {
+					return nil, err
+				}
+				fmt.Println(err)
+				continue
+			}
+			if val != nil {
+				params[name] = val
+			}
+			break
+		}
+	}
+	return json.Marshal(params)
+}
+
+var stdinReader = bufio.NewReader(os.Stdin)
+
+// promptValue reads a line from stdin and validates it against the schema, string values need no quotes
+func promptValue(schema *utils.JSONSchema, required bool) (json.RawMessage, error) {
+	line, err := stdinReader.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return nil, err
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		if required {
+			return nil, fmt.Errorf("value is required")
+		}
+		return nil, nil
+	}
+
+	val := []byte(line)
+	if schema.Type == "string" && !strings.HasPrefix(line, `"`) {
+		val, err = json.Marshal(line)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := schema.Validate(val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+var msgParamsSchemaCmd = &cli.Command{
+	Name:      "params-schema",
+	Usage:     "Show the json schema and template of params of the method",
+	ArgsUsage: "<targetAddress> <method number | method name>",
 	Action: func(cctx *cli.Context) error {
-		if cctx.Args().Len() != 2 {
-			return fmt.Errorf("'send' expects two arguments, target and amount")
+		if cctx.NArg() != 2 {
+			return fmt.Errorf("'%s' expects two arguments, target and method", cctx.Command.Name)
 		}
 
 		api, err := getAPI(cctx)
@@ -91,49 +273,45 @@ var msgSendCmd = &cli.Command{
 			return err
 		}
 
-		var req service.MsgSendReq
+		req := &service.MsgParamsSchemaReq{}
 		req.To, err = address.NewFromString(cctx.Args().Get(0))
 		if err != nil {
 			return fmt.Errorf("failed to parse target address: %w", err)
 		}
-
-		val, err := types.ParseFIL(cctx.Args().Get(1))
-		if err != nil {
-			return fmt.Errorf("failed to parse amount: %w", err)
+		if method, err := strconv.ParseUint(cctx.Args().Get(1), 10, 64); err == nil {
+			req.Method = abi.MethodNum(method)
+		} else {
+			req.MethodName = cctx.Args().Get(1)
 		}
-		req.Value = abi.TokenAmount(val)
 
-		addr, err := address.NewFromString(cctx.String("from"))
+		ret, err := api.MsgParamsSchema(cctx.Context, req)
 		if err != nil {
-			return fmt.Errorf("failed to parse from address: %w", err)
+			return err
 		}
-		req.From = addr
-
-		req.Method = abi.MethodNum(cctx.Uint64("method"))
+		return printJSON(ret)
+	},
+}
 
-		gfc, err := types.BigFromString(cctx.String("max-fee"))
+var msgSendCmd = &cli.Command{
+	Name:      "send",
+	Usage:     "Send a message",
+	ArgsUsage: "<targetAddress> <amount>",
+	Flags:     append(msgSendFlags, flagVerbose),
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
 		if err != nil {
 			return err
 		}
-		req.MaxFee = gfc
 
-		req.GasOverPremium = cctx.Float64("gas-over-premium")
-
-		req.GasOverEstimation = cctx.Float64("gas-over-estimation")
-
-		if cctx.IsSet("params-json") {
-			req.Params.Data =cctx.String("params-json")
-			req.Params.EncType = service.EncJson
+		req, err := parseMsgSendReq(cctx)
+		if err != nil {
+			return err
 		}
-		if cctx.IsSet("params-hex") {
-			if len(req.Params.Data) != 0 {
-				return fmt.Errorf("can only specify one of 'params-json' and 'params-hex'")
-			}
-			req.Params.Data = cctx.String("params-hex")
-			req.Params.EncType = service.EncHex
+		if err := completeParamsBySchema(cctx, api, req); err != nil {
+			return err
 		}
 
-		id, err := api.MsgSend(cctx.Context, &req)
+		id, err := api.MsgSend(cctx.Context, req)
 		if err != nil {
 			return err
 		}
@@ -155,6 +333,92 @@ var msgSendCmd = &cli.Command{
 	},
 }
 
+var msgBuildCmd = &cli.Command{
+	Name:      "build",
+	Usage:     "Build an unsigned message with nonce and gas filled, to be signed offline",
+	ArgsUsage: "<targetAddress> <amount>",
+	Flags: append(msgSendFlags, &cli.StringFlag{
+		Name:  "output",
+		Usage: "output format: json | hex | cid",
+		Value: "json",
+	}),
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		req, err := parseMsgSendReq(cctx)
+		if err != nil {
+			return err
+		}
+		if err := completeParamsBySchema(cctx, api, req); err != nil {
+			return err
+		}
+
+		ret, err := api.MsgBuild(cctx.Context, req)
+		if err != nil {
+			return err
+		}
+
+		switch cctx.String("output") {
+		case "json":
+			return printJSON(ret)
+		case "hex":
+			fmt.Println(ret.Hex)
+		case "cid":
+			fmt.Println(ret.Cid)
+		default:
+			return fmt.Errorf("unknown output format: %s", cctx.String("output"))
+		}
+		return nil
+	},
+}
+
+var msgSubmitSignedCmd = &cli.Command{
+	Name:      "submit-signed",
+	Usage:     "Verify and push a message signed offline, it is not tracked by messager, use its cid as id to query it or list it by 'msg list --signed'",
+	ArgsUsage: "<signed message in hex encoded cbor | path of signed message in json>",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "the argument is a path of signed message in json",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return fmt.Errorf("must pass the signed message as the only argument")
+		}
+
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		req := &service.MsgSubmitSignedReq{}
+		if cctx.Bool("json") {
+			data, err := os.ReadFile(cctx.Args().First())
+			if err != nil {
+				return err
+			}
+			req.SignedMessage = &types.SignedMessage{}
+			if err := json.Unmarshal(data, req.SignedMessage); err != nil {
+				return fmt.Errorf("failed to parse signed message: %w", err)
+			}
+		} else {
+			req.Hex = cctx.Args().First()
+		}
+
+		c, err := api.MsgSubmitSigned(cctx.Context, req)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("push signed message (cid: %s ) success, query it with the cid as id\n", c)
+		return nil
+	},
+}
+
 var msgListCmd = &cli.Command{
 	Name:  "list",
 	Usage: "list messages",
@@ -181,6 +445,10 @@ var msgListCmd = &cli.Command{
 			Name:  "failed",
 			Usage: "show failed messages",
 		},
+		&cli.BoolFlag{
+			Name:  "signed",
+			Usage: "show messages signed offline and submitted by 'msg submit-signed', which are not tracked by messager",
+		},
 		&cli.StringFlag{
 			Name:    "time",
 			Usage:   "exceeding residence time of blocked msg. Is valid only when [--blocked] flag is set. eg. 3s,3m,3h (default 3h)",
@@ -214,9 +482,37 @@ state:
   5:  ReplacedMsg
   6:  NoWalletMsg
 
-if [--failed] or [--blocked] is set, [--state] will be ignored 
+if [--failed], [--blocked] or [--signed] is set, [--state] will be ignored 
 `,
 		},
+		&cli.StringFlag{
+			Name:  "cid",
+			Usage: "Specify the signed or unsigned cid of message, look up on chain if not found in messager",
+		},
+		&cli.BoolFlag{
+			Name:  "on-chain",
+			Usage: "list messages executed on chain instead of messages in messager, [--from-epoch] and one of [--to] and [--from] are required",
+		},
+		&cli.StringFlag{
+			Name:  "to",
+			Usage: "filter by the receiver address",
+		},
+		&cli.StringFlag{
+			Name:  "method-name",
+			Usage: "filter by method name, eg: PreCommitSectorBatch",
+		},
+		&cli.Int64Flag{
+			Name:  "exit-code",
+			Usage: "filter by exit code of the receipt",
+		},
+		&cli.Int64Flag{
+			Name:  "from-epoch",
+			Usage: "filter by the height which message executed at, inclusive",
+		},
+		&cli.Int64Flag{
+			Name:  "to-epoch",
+			Usage: "filter by the height which message executed at, inclusive",
+		},
 		flagVerbose,
 	},
 	Action: func(cctx *cli.Context) error {
@@ -230,6 +526,30 @@ if [--failed] or [--blocked] is set, [--state] will be ignored
 			params := service.MsgQueryReq{}
 			nilParams := service.MsgQueryReq{}
 
+			if cctx.IsSet("to") {
+				to, err := address.NewFromString(cctx.String("to"))
+				if err != nil {
+					return nilParams, fmt.Errorf("failed to parse to address: %w", err)
+				}
+				params.To = to
+			}
+			params.MethodName = cctx.String("method-name")
+			if cctx.IsSet("exit-code") {
+				code := exitcode.ExitCode(cctx.Int64("exit-code"))
+				params.ExitCode = &code
+			}
+			params.FromEpoch = abi.ChainEpoch(cctx.Int64("from-epoch"))
+			params.ToEpoch = abi.ChainEpoch(cctx.Int64("to-epoch"))
+
+			if cctx.IsSet("cid") {
+				c, err := cid.Decode(cctx.String("cid"))
+				if err != nil {
+					return nilParams, fmt.Errorf("failed to parse cid: %w", err)
+				}
+				params.Cid = &c
+				return params, nil
+			}
+
 			if cctx.IsSet("id") {
 				params.ID = cctx.String("id")
 				return params, nil
@@ -252,6 +572,11 @@ if [--failed] or [--blocked] is set, [--state] will be ignored
 				}
 			}
 
+			if cctx.Bool("signed") {
+				params.IsSigned = true
+				return params, nil
+			}
+
 			if cctx.IsSet("nonce") {
 				params.Nonce = cctx.Uint64("nonce")
 				if len(params.From) == 0 {
@@ -274,6 +599,11 @@ if [--failed] or [--blocked] is set, [--state] will be ignored
 				return params, nil
 			}
 
+			if cctx.Bool("on-chain") {
+				params.OnChain = true
+				return params, nil
+			}
+
 			params.State = []msgTypes.MessageState{msgTypes.MessageState(cctx.Int("state"))}
 
 			if cctx.IsSet("page-index") || cctx.IsSet("page-size") {
@@ -1,9 +1,15 @@
 package cli
 
 import (
+	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
@@ -18,14 +24,23 @@ var MultiSigCmd = &cli.Command{
 	Usage: "Manage multisig wallets",
 	Subcommands: []*cli.Command{
 		multisigInfoCmd,
+		multisigVestingCmd,
+		multisigAvailableCmd,
+		multisigVestedCmd,
 		multisigProposeCmd,
 		multisigProposeListCmd,
+		multisigProposeCallCmd,
+		multisigHistoryCmd,
+		multisigInboxCmd,
+		multisigWatchCmd,
 		multisigApproveCmd,
 		multisigCancelCmd,
 		multisigCreateCmd,
 		multisigAddSignerCmd,
 		multisigRemoveSignerCmd,
 		multisigSwapSignerCmd,
+		multisigThresholdCmd,
+		multisigLockCmd,
 	},
 }
 
@@ -48,10 +63,18 @@ var multisigCreateCmd = &cli.Command{
 			Usage: "length of the period over which funds unlock",
 			Value: 0,
 		},
+		&cli.Int64Flag{
+			Name:  "start-epoch",
+			Usage: "epoch the funds start to unlock",
+		},
 		&cli.StringFlag{
 			Name:  "from",
 			Usage: "account to send the create message from (uses the first signer if omitted)",
 		},
+		&cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "preview the constructor params without creating the multisig",
+		},
 	},
 	Action: func(cctx *cli.Context) error {
 		api, err := getAPI(cctx)
@@ -100,16 +123,23 @@ var multisigCreateCmd = &cli.Command{
 			Signers:            signers,
 			ApprovalsThreshold: required,
 			Value:              types.BigInt(value),
+			StartEpoch:         abi.ChainEpoch(cctx.Int64("start-epoch")),
 			LockedDuration:     duration,
 			From:               from,
+			DryRun:             cctx.Bool("dry-run"),
 		}
 
-		newAddr, err := api.MsigCreate(cctx.Context, req)
+		ret, err := api.MsigCreate(cctx.Context, req)
 		if err != nil {
 			return err
 		}
 
-		fmt.Printf("Created new multisig wallet at address %s \n", newAddr)
+		if ret.DryRun {
+			fmt.Printf("Create message from %s with value %s, constructor params:\n", ret.Message.From, types.FIL(ret.Message.Value))
+			return printJSON(ret.Params)
+		}
+
+		fmt.Printf("Created new multisig wallet at address %s (%s)\n", ret.RobustAddress, ret.IDAddress)
 		return nil
 	},
 }
@@ -200,6 +230,12 @@ var multisigProposeListCmd = &cli.Command{
 	Name:      "propose-list",
 	Usage:     "List pending multisig transactions",
 	ArgsUsage: "<multisig address>",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "output in json format",
+		},
+	},
 	Action: func(cctx *cli.Context) error {
 		api, err := getAPI(cctx)
 		if err != nil {
@@ -220,52 +256,186 @@ var multisigProposeListCmd = &cli.Command{
 			return err
 		}
 
-		type ProposeOutput struct {
-			TxID       int64
-			Value      types.FIL
-			MethodName string
-			MethodId   abi.MethodNum
-			Params     json.Marshaler
-			RawParams  []byte
-			Approved   []address.Address
+		if cctx.Bool("json") {
+			return printJSON(ret)
 		}
 
-		var out []ProposeOutput
-		for _, p := range ret {
-			var b []byte
-			var err error
-			if len(p.Params) > 0 {
-				b, err = api.MsgDecodeParam2Json(cctx.Context, &service.MsgDecodeParamReq{
-					To:     p.To,
-					Method: p.Method,
-					Params: p.Params,
-				})
+		if len(ret) == 0 {
+			fmt.Println("no pending transactions")
+			return nil
+		}
+		for i, txn := range ret {
+			if i > 0 {
+				fmt.Println()
+			}
+			printMsigTransaction(txn)
+		}
+		return nil
+	},
+}
+
+func printMsigTransaction(txn *service.MsigTransaction) {
+	method := txn.MethodName
+	if method == "" {
+		method = fmt.Sprintf("method %d", txn.Method)
+	}
+	if txn.ActorType != "" {
+		method = fmt.Sprintf("%s.%s", txn.ActorType, method)
+	}
+	approved := make([]string, 0, len(txn.Approved))
+	for _, a := range txn.Approved {
+		approved = append(approved, a.String())
+	}
+
+	fmt.Printf("Transaction %d: send %s to %s, calling %s\n", txn.ID, types.FIL(txn.Value), txn.To, method)
+	fmt.Printf("  Proposer:  %s\n", txn.Proposer)
+	fmt.Printf("  Approvals: %d/%d (%s)\n", len(txn.Approved), txn.Threshold, strings.Join(approved, ", "))
+	fmt.Printf("  Hash:      %x\n", txn.Hash)
+	switch {
+	case txn.DecodeError != "":
+		fmt.Printf("  Params:    %x\n", txn.Params)
+		fmt.Printf("  Warning:   cannot decode the transaction: %s\n", txn.DecodeError)
+	case len(txn.ParamsInJson) > 0:
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, txn.ParamsInJson, "    ", "  "); err != nil {
+			buf.Reset()
+			buf.Write(txn.ParamsInJson)
+		}
+		fmt.Printf("  Params:\n    %s\n", buf.String())
+	}
+}
+
+var multisigProposeCallCmd = &cli.Command{
+	Name:  "propose-call",
+	Usage: "Propose a miner operation which requires the multisig as the sender, eg: the owner of the miner",
+	Subcommands: []*cli.Command{
+		msigProposeCallCmd("set-owner", "Propose the new owner of miner", "<new owner>", 1,
+			func(cctx *cli.Context, req *service.MsigProposeCallReq, miner address.Address, args []address.Address) error {
+				req.SetOwner = &service.MinerSetOwnerReq{Miner: miner, NewOwner: args[0]}
+				return nil
+			}),
+		msigProposeCallCmd("confirm-owner", "Confirm the multisig as the new owner of miner", "", 0,
+			func(cctx *cli.Context, req *service.MsigProposeCallReq, miner address.Address, args []address.Address) error {
+				req.ConfirmOwner = &service.MinerSetOwnerReq{Miner: miner, NewOwner: req.Msig}
+				return nil
+			}),
+		msigProposeCallCmd("set-worker", "Propose the new worker of miner", "<new worker>", 1,
+			func(cctx *cli.Context, req *service.MsigProposeCallReq, miner address.Address, args []address.Address) error {
+				req.SetWorker = &service.MinerSetWorkerReq{Miner: miner, NewWorker: args[0]}
+				return nil
+			}),
+		msigProposeCallCmd("confirm-worker", "Confirm the new worker of miner", "<new worker>", 1,
+			func(cctx *cli.Context, req *service.MsigProposeCallReq, miner address.Address, args []address.Address) error {
+				req.ConfirmWorker = &service.MinerSetWorkerReq{Miner: miner, NewWorker: args[0]}
+				return nil
+			}),
+		msigProposeCallCmd("set-controllers", "Replace the control addresses of miner", "<controller>...", -1,
+			func(cctx *cli.Context, req *service.MsigProposeCallReq, miner address.Address, args []address.Address) error {
+				req.SetControllers = &service.MinerSetControllersReq{Miner: miner, NewControllers: args}
+				return nil
+			}),
+		msigProposeCallCmd("set-beneficiary", "Propose the new beneficiary of miner", "<new beneficiary>", 1,
+			func(cctx *cli.Context, req *service.MsigProposeCallReq, miner address.Address, args []address.Address) error {
+				quota, err := types.ParseFIL(cctx.String("quota"))
+				if err != nil {
+					return fmt.Errorf("parse quota failed: %w", err)
+				}
+				req.SetBeneficiary = &service.MinerSetBeneficiaryReq{Miner: miner}
+				req.SetBeneficiary.NewBeneficiary = args[0]
+				req.SetBeneficiary.NewQuota = abi.TokenAmount(quota)
+				req.SetBeneficiary.NewExpiration = abi.ChainEpoch(cctx.Int64("expiration"))
+				return nil
+			},
+			&cli.StringFlag{
+				Name:  "quota",
+				Usage: "quota of the new beneficiary in FIL",
+				Value: "0",
+			},
+			&cli.Int64Flag{
+				Name:  "expiration",
+				Usage: "expiration epoch of the new beneficiary",
+			},
+		),
+		msigProposeCallCmd("confirm-beneficiary", "Confirm the pending beneficiary change of miner", "<new beneficiary>", 1,
+			func(cctx *cli.Context, req *service.MsigProposeCallReq, miner address.Address, args []address.Address) error {
+				req.ConfirmBeneficiary = &service.MinerConfirmBeneficiaryReq{
+					Miner:          miner,
+					NewBeneficiary: args[0],
+					ByNominee:      cctx.Bool("by-nominee"),
+				}
+				return nil
+			},
+			&cli.BoolFlag{
+				Name:  "by-nominee",
+				Usage: "confirm as the nominee, otherwise as the current beneficiary",
+			},
+		),
+		msigProposeCallCmd("withdraw", "Withdraw the available balance of miner to the beneficiary", "", 0,
+			func(cctx *cli.Context, req *service.MsigProposeCallReq, miner address.Address, args []address.Address) error {
+				amount, err := types.ParseFIL(cctx.String("amount"))
+				if err != nil {
+					return fmt.Errorf("parse amount failed: %w", err)
+				}
+				req.WithdrawBalance = &service.MinerWithdrawBalanceReq{Miner: miner, Amount: abi.TokenAmount(amount)}
+				return nil
+			},
+			&cli.StringFlag{
+				Name:  "amount",
+				Usage: "amount to withdraw in FIL, all available balance if not set",
+				Value: "0",
+			},
+		),
+	},
+}
+
+// msigProposeCallCmd builds the command to propose a miner operation through the multisig,
+// the addresses after the miner are passed to fill as args, nArgs < 0 means at least one
+func msigProposeCallCmd(
+	name, usage, argsUsage string,
+	nArgs int,
+	fill func(cctx *cli.Context, req *service.MsigProposeCallReq, miner address.Address, args []address.Address) error,
+	flags ...cli.Flag,
+) *cli.Command {
+	return &cli.Command{
+		Name:      name,
+		Usage:     usage,
+		ArgsUsage: strings.TrimSpace("<multisig address> <proposer address> <miner address> " + argsUsage),
+		Flags:     flags,
+		Action: func(cctx *cli.Context) error {
+			api, err := getAPI(cctx)
+			if err != nil {
+				return err
+			}
+
+			if (nArgs >= 0 && cctx.NArg() != 3+nArgs) || (nArgs < 0 && cctx.NArg() < 4) {
+				return fmt.Errorf("must specify %s", cctx.Command.ArgsUsage)
+			}
+
+			addrs := make([]address.Address, 0, cctx.NArg())
+			for _, arg := range cctx.Args().Slice() {
+				addr, err := address.NewFromString(arg)
 				if err != nil {
 					return err
 				}
+				addrs = append(addrs, addr)
 			}
 
-			methodName, err := api.MsgGetMethodName(cctx.Context, &service.MsgGetMethodNameReq{
-				To:     p.To,
-				Method: p.Method,
-			})
-			if err != nil {
+			req := &service.MsigProposeCallReq{
+				Msig: addrs[0],
+				From: addrs[1],
+			}
+			if err := fill(cctx, req, addrs[2], addrs[3:]); err != nil {
 				return err
 			}
 
-			out = append(out, ProposeOutput{
-				TxID:       p.ID,
-				Value:      types.FIL(p.Value),
-				RawParams:  p.Params,
-				Params:     json.RawMessage(b),
-				MethodId:   p.Method,
-				MethodName: methodName,
-				Approved:   p.Approved,
-			})
-		}
+			ret, err := api.MsigProposeCall(cctx.Context, req)
+			if err != nil {
+				return err
+			}
 
-		return printJSON(out)
-	},
+			return printJSON(ret)
+		},
+	}
 }
 
 var multisigAddSignerCmd = &cli.Command{
@@ -326,6 +496,9 @@ var multisigApproveCmd = &cli.Command{
 	Name:      "approve",
 	Usage:     "Approve a multisig transaction",
 	ArgsUsage: "<multisig address> <proposer address> <txid>",
+	Description: `The pending transaction can be checked against the hash listed by propose-list with --hash,
+or against the expected proposal with --to, --value, --method and --params.`,
+	Flags: msigTxnCheckFlags,
 	Action: func(cctx *cli.Context) error {
 		api, err := getAPI(cctx)
 		if err != nil {
@@ -356,6 +529,9 @@ var multisigApproveCmd = &cli.Command{
 			Proposer: from,
 			TxID:     txid,
 		}
+		if err := parseMsigTxnCheck(cctx, req); err != nil {
+			return err
+		}
 
 		ret, err := api.MsigApprove(cctx.Context, req)
 		if err != nil {
@@ -370,6 +546,9 @@ var multisigCancelCmd = &cli.Command{
 	Name:      "cancel",
 	Usage:     "Cancel a multisig transaction",
 	ArgsUsage: "<multisig address> <proposer address> <txid>",
+	Description: `The pending transaction can be checked against the hash listed by propose-list with --hash,
+or against the expected proposal with --to, --value, --method and --params.`,
+	Flags: msigTxnCheckFlags,
 	Action: func(cctx *cli.Context) error {
 		api, err := getAPI(cctx)
 		if err != nil {
@@ -400,6 +579,9 @@ var multisigCancelCmd = &cli.Command{
 			Proposer: from,
 			TxID:     txid,
 		}
+		if err := parseMsigTxnCheck(cctx, req); err != nil {
+			return err
+		}
 
 		err = api.MsigCancel(cctx.Context, req)
 		if err != nil {
@@ -411,6 +593,66 @@ var multisigCancelCmd = &cli.Command{
 	},
 }
 
+var msigTxnCheckFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:  "hash",
+		Usage: "expected proposal hash in hex",
+	},
+	&cli.StringFlag{
+		Name:  "to",
+		Usage: "expected recipient of the proposal",
+	},
+	&cli.StringFlag{
+		Name:  "value",
+		Usage: "expected value of the proposal in FIL",
+	},
+	&cli.Uint64Flag{
+		Name:  "method",
+		Usage: "expected method of the proposal",
+	},
+	&cli.StringFlag{
+		Name:  "params",
+		Usage: "expected params of the proposal in hex",
+	},
+}
+
+// parseMsigTxnCheck fills the expected hash and proposal of the transaction from flags
+func parseMsigTxnCheck(cctx *cli.Context, req *service.MultisigTransactionReq) error {
+	if cctx.IsSet("hash") {
+		hash, err := hex.DecodeString(cctx.String("hash"))
+		if err != nil {
+			return fmt.Errorf("parse hash failed: %w", err)
+		}
+		req.Hash = hash
+	}
+
+	if !cctx.IsSet("to") && !cctx.IsSet("value") && !cctx.IsSet("method") && !cctx.IsSet("params") {
+		return nil
+	}
+	if !cctx.IsSet("to") || !cctx.IsSet("value") {
+		return fmt.Errorf("must specify both --to and --value to check the proposal")
+	}
+	to, err := address.NewFromString(cctx.String("to"))
+	if err != nil {
+		return fmt.Errorf("parse to address failed: %w", err)
+	}
+	value, err := types.ParseFIL(cctx.String("value"))
+	if err != nil {
+		return fmt.Errorf("parse value failed: %w", err)
+	}
+	params, err := hex.DecodeString(cctx.String("params"))
+	if err != nil {
+		return fmt.Errorf("parse params failed: %w", err)
+	}
+	req.Expect = &service.MultisigProposal{
+		To:     to,
+		Value:  abi.TokenAmount(value),
+		Method: abi.MethodNum(cctx.Uint64("method")),
+		Params: params,
+	}
+	return nil
+}
+
 var multisigInfoCmd = &cli.Command{
 	Name:      "info",
 	Usage:     "Get info about a multisig wallet",
@@ -556,3 +798,465 @@ var multisigSwapSignerCmd = &cli.Command{
 		return printJSON(ret)
 	},
 }
+
+var multisigThresholdCmd = &cli.Command{
+	Name:      "threshold",
+	Usage:     "Propose to change the number of approvals required by a multisig wallet",
+	ArgsUsage: "<multisig address> <proposer address> <new threshold>",
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		if cctx.NArg() != 3 {
+			return fmt.Errorf("must specify multisig address, proposer address, and new threshold")
+		}
+
+		msigAddr, err := address.NewFromString(cctx.Args().Get(0))
+		if err != nil {
+			return err
+		}
+
+		from, err := address.NewFromString(cctx.Args().Get(1))
+		if err != nil {
+			return err
+		}
+
+		threshold, err := strconv.ParseUint(cctx.Args().Get(2), 10, 64)
+		if err != nil {
+			return err
+		}
+
+		req := &service.MultisigChangeThresholdReq{
+			Msig:         msigAddr,
+			Proposer:     from,
+			NewThreshold: threshold,
+		}
+
+		ret, err := api.MsigChangeThreshold(cctx.Context, req)
+		if err != nil {
+			return err
+		}
+
+		return printJSON(ret)
+	},
+}
+
+var multisigLockCmd = &cli.Command{
+	Name:      "lock",
+	Usage:     "Propose to lock a part of the balance of a multisig wallet, which unlocks linearly over the duration",
+	ArgsUsage: "<multisig address> <proposer address> <amount> <unlock duration in epochs>",
+	Flags: []cli.Flag{
+		&cli.Int64Flag{
+			Name:  "start-epoch",
+			Usage: "epoch to start unlocking, the current height if not set",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		if cctx.NArg() != 4 {
+			return fmt.Errorf("must specify multisig address, proposer address, amount, and unlock duration")
+		}
+
+		msigAddr, err := address.NewFromString(cctx.Args().Get(0))
+		if err != nil {
+			return err
+		}
+
+		from, err := address.NewFromString(cctx.Args().Get(1))
+		if err != nil {
+			return err
+		}
+
+		amount, err := types.ParseFIL(cctx.Args().Get(2))
+		if err != nil {
+			return err
+		}
+
+		duration, err := strconv.ParseInt(cctx.Args().Get(3), 10, 64)
+		if err != nil {
+			return err
+		}
+
+		start := abi.ChainEpoch(cctx.Int64("start-epoch"))
+		if !cctx.IsSet("start-epoch") {
+			head, err := api.ChainGetHead(cctx.Context)
+			if err != nil {
+				return err
+			}
+			start = head.Height()
+		}
+
+		req := &service.MultisigLockBalanceReq{
+			Msig:           msigAddr,
+			Proposer:       from,
+			StartEpoch:     start,
+			UnlockDuration: abi.ChainEpoch(duration),
+			Amount:         abi.TokenAmount(amount),
+		}
+
+		ret, err := api.MsigLockBalance(cctx.Context, req)
+		if err != nil {
+			return err
+		}
+
+		return printJSON(ret)
+	},
+}
+
+var multisigVestingCmd = &cli.Command{
+	Name:      "vesting",
+	Usage:     "Show the vesting schedule and the monthly unlock timeline of multisig wallets",
+	ArgsUsage: "<multisig address>...",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "output in json format",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		if cctx.NArg() == 0 {
+			return fmt.Errorf("must specify multisig address")
+		}
+
+		var rets []*service.MsigVestingResp
+		for _, arg := range cctx.Args().Slice() {
+			msigAddr, err := address.NewFromString(arg)
+			if err != nil {
+				return err
+			}
+			ret, err := api.MsigVesting(cctx.Context, msigAddr)
+			if err != nil {
+				return fmt.Errorf("get vesting of %s failed: %w", msigAddr, err)
+			}
+			rets = append(rets, ret)
+		}
+
+		if cctx.Bool("json") {
+			return printJSON(rets)
+		}
+
+		for i, ret := range rets {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("Multisig:        %s\n", cctx.Args().Get(i))
+			fmt.Printf("Balance:         %s\n", types.FIL(ret.Balance))
+			fmt.Printf("Available:       %s\n", types.FIL(ret.Available))
+			fmt.Printf("Locked:          %s\n", types.FIL(ret.Locked))
+			fmt.Printf("Initial Balance: %s\n", types.FIL(ret.InitialBalance))
+			fmt.Printf("Vesting:         epoch %d to %d\n", ret.StartEpoch, ret.StartEpoch+ret.UnlockDuration)
+			if len(ret.Timeline) == 0 {
+				fmt.Println("Fully vested")
+				continue
+			}
+
+			fmt.Println()
+			w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+			_, _ = fmt.Fprintf(w, "Period\tEpochs\tUnlocked\tLocked After\n")
+			for _, p := range ret.Timeline {
+				_, _ = fmt.Fprintf(w, "%s ~ %s\t%d ~ %d\t%s\t%s\n",
+					p.StartTime.Format("2006-01-02"),
+					p.EndTime.Format("2006-01-02"),
+					p.Start,
+					p.End,
+					types.FIL(p.Unlocked),
+					types.FIL(p.Locked),
+				)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+var multisigAvailableCmd = &cli.Command{
+	Name:      "available",
+	Usage:     "Show the balance of a multisig wallet that can be spent",
+	ArgsUsage: "<multisig address>",
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		if cctx.NArg() != 1 {
+			return fmt.Errorf("must specify multisig address")
+		}
+
+		msigAddr, err := address.NewFromString(cctx.Args().Get(0))
+		if err != nil {
+			return err
+		}
+
+		ret, err := api.MsigAvailable(cctx.Context, msigAddr)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(types.FIL(ret))
+		return nil
+	},
+}
+
+var multisigVestedCmd = &cli.Command{
+	Name:      "vested",
+	Usage:     "Show the amount vested in a multisig wallet between two epochs",
+	ArgsUsage: "<multisig address>",
+	Flags: []cli.Flag{
+		&cli.Int64Flag{
+			Name:     "from",
+			Usage:    "start epoch",
+			Required: true,
+		},
+		&cli.Int64Flag{
+			Name:  "to",
+			Usage: "end epoch, the current height if not set",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		if cctx.NArg() != 1 {
+			return fmt.Errorf("must specify multisig address")
+		}
+
+		msigAddr, err := address.NewFromString(cctx.Args().Get(0))
+		if err != nil {
+			return err
+		}
+
+		ret, err := api.MsigVested(cctx.Context, &service.MsigVestedReq{
+			Msig: msigAddr,
+			From: abi.ChainEpoch(cctx.Int64("from")),
+			To:   abi.ChainEpoch(cctx.Int64("to")),
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(types.FIL(ret))
+		return nil
+	},
+}
+
+var multisigHistoryCmd = &cli.Command{
+	Name:      "history",
+	Usage:     "Show the transactions of a multisig wallet rebuilt from the messages on chain",
+	ArgsUsage: "<multisig address>",
+	Flags: []cli.Flag{
+		&cli.Int64Flag{
+			Name:  "from",
+			Usage: "epoch to scan from, 30 days before the current height if not set",
+		},
+		&cli.BoolFlag{
+			Name:  "messages",
+			Usage: "also list the messages sent to the multisig",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "output in json format",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		if cctx.NArg() != 1 {
+			return fmt.Errorf("must specify multisig address")
+		}
+
+		msigAddr, err := address.NewFromString(cctx.Args().Get(0))
+		if err != nil {
+			return err
+		}
+
+		ret, err := api.MsigHistory(cctx.Context, &service.MsigHistoryReq{
+			Msig: msigAddr,
+			From: abi.ChainEpoch(cctx.Int64("from")),
+		})
+		if err != nil {
+			return err
+		}
+
+		if cctx.Bool("json") {
+			return printJSON(ret)
+		}
+
+		fmt.Printf("History of %s from epoch %d to %d\n", ret.Msig, ret.From, ret.To)
+		if len(ret.Transactions) == 0 {
+			fmt.Println("no transactions")
+		}
+		for _, txn := range ret.Transactions {
+			fmt.Println()
+			switch txn.State {
+			case service.MsigTxnExecuted:
+				fmt.Printf("%s at %d, exit code %s, return %x\n", txn.State, txn.ClosedAt, txn.ExitCode, txn.Return)
+			case service.MsigTxnCancelled:
+				fmt.Printf("%s at %d\n", txn.State, txn.ClosedAt)
+			default:
+				fmt.Println(txn.State)
+			}
+			printMsigTransaction(&txn.MsigTransaction)
+			if txn.ToID != address.Undef && txn.ToID != txn.To {
+				fmt.Printf("  To ID:     %s\n", txn.ToID)
+			}
+		}
+
+		if cctx.Bool("messages") {
+			fmt.Println()
+			w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+			_, _ = fmt.Fprintln(w, "Height\tCid\tFrom\tMethod\tTxnID\tValue\tExitCode")
+			for _, e := range ret.Events {
+				txnID := "-"
+				if e.TxnID >= 0 {
+					txnID = strconv.FormatInt(e.TxnID, 10)
+				}
+				_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n", e.Height, e.MsgCid, e.From, e.Method, txnID, types.FIL(e.Value), e.ExitCode)
+			}
+			return w.Flush()
+		}
+		return nil
+	},
+}
+
+var multisigInboxCmd = &cli.Command{
+	Name:  "inbox",
+	Usage: "List pending transactions of watched multisig wallets awaiting the approval of signers",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "signer",
+			Usage: "signers to check, all addresses of the wallet if not set",
+		},
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "output in json format",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		req := &service.MsigInboxReq{}
+		for _, s := range cctx.StringSlice("signer") {
+			signer, err := address.NewFromString(s)
+			if err != nil {
+				return err
+			}
+			req.Signer = append(req.Signer, signer)
+		}
+
+		ret, err := api.MsigInbox(cctx.Context, req)
+		if err != nil {
+			return err
+		}
+
+		if cctx.Bool("json") {
+			return printJSON(ret)
+		}
+
+		if len(ret) == 0 {
+			fmt.Println("no transactions awaiting approval")
+			return nil
+		}
+		for i, item := range ret {
+			if i > 0 {
+				fmt.Println()
+			}
+			signers := make([]string, 0, len(item.Signers))
+			for _, s := range item.Signers {
+				signers = append(signers, s.String())
+			}
+			fmt.Printf("Multisig %s, pending for %s, awaiting %s\n", item.Msig, item.Age.Truncate(time.Minute), strings.Join(signers, ", "))
+			printMsigTransaction(&item.MsigTransaction)
+		}
+		return nil
+	},
+}
+
+var multisigWatchCmd = &cli.Command{
+	Name:  "watch",
+	Usage: "Manage the multisig wallets watched by the approval inbox",
+	Subcommands: []*cli.Command{
+		{
+			Name:  "list",
+			Usage: "List watched multisig wallets",
+			Action: func(cctx *cli.Context) error {
+				api, err := getAPI(cctx)
+				if err != nil {
+					return err
+				}
+
+				ret, err := api.MsigWatchList(cctx.Context)
+				if err != nil {
+					return err
+				}
+				for _, msig := range ret {
+					fmt.Println(msig)
+				}
+				return nil
+			},
+		},
+		{
+			Name:      "add",
+			Usage:     "Watch a multisig wallet",
+			ArgsUsage: "<multisig address>",
+			Action: func(cctx *cli.Context) error {
+				api, err := getAPI(cctx)
+				if err != nil {
+					return err
+				}
+
+				if cctx.NArg() != 1 {
+					return fmt.Errorf("must specify multisig address")
+				}
+				msigAddr, err := address.NewFromString(cctx.Args().Get(0))
+				if err != nil {
+					return err
+				}
+
+				return api.MsigWatch(cctx.Context, &service.MsigWatchReq{Msig: msigAddr})
+			},
+		},
+		{
+			Name:      "remove",
+			Usage:     "Stop watching a multisig wallet",
+			ArgsUsage: "<multisig address>",
+			Action: func(cctx *cli.Context) error {
+				api, err := getAPI(cctx)
+				if err != nil {
+					return err
+				}
+
+				if cctx.NArg() != 1 {
+					return fmt.Errorf("must specify multisig address")
+				}
+				msigAddr, err := address.NewFromString(cctx.Args().Get(0))
+				if err != nil {
+					return err
+				}
+
+				return api.MsigUnwatch(cctx.Context, &service.MsigWatchReq{Msig: msigAddr})
+			},
+		},
+	},
+}
@@ -0,0 +1,248 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/ipfs-force-community/venus-tool/repo/config"
+	"github.com/ipfs-force-community/venus-tool/service"
+	"github.com/urfave/cli/v2"
+)
+
+var PolicyCmd = &cli.Command{
+	Name:  "policy",
+	Usage: "Manage spending policies of addresses and approve messages exceeding the limits",
+	Subcommands: []*cli.Command{
+		policyListCmd,
+		policySetCmd,
+		policyRemoveCmd,
+		policyApprovalListCmd,
+		policyApproveCmd,
+		policyRejectCmd,
+	},
+}
+
+var policyListCmd = &cli.Command{
+	Name:  "list",
+	Usage: "List spending policies",
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		policies, err := api.PolicyList(cctx.Context)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "Address\tDailyLimit\tWeeklyLimit\tAllowedTo\tForbiddenMethods\n")
+		for _, p := range policies {
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%v\n",
+				p.Address,
+				orNone(p.DailyLimit),
+				orNone(p.WeeklyLimit),
+				orNone(strings.Join(p.AllowedTo, ",")),
+				p.ForbiddenMethods,
+			)
+		}
+		return w.Flush()
+	},
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+var policySetCmd = &cli.Command{
+	Name:      "set",
+	Usage:     "Set the spending policy of an address, the old one will be replaced, token is required to identify the operator",
+	ArgsUsage: "<address>",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "daily-limit",
+			Usage: "max value can be sent in the last 24 hours, eg: 100FIL",
+		},
+		&cli.StringFlag{
+			Name:  "weekly-limit",
+			Usage: "max value can be sent in the last 7 days, eg: 500FIL",
+		},
+		&cli.StringSliceFlag{
+			Name:  "allowed-to",
+			Usage: "destinations allowed, any destination is allowed if not set",
+		},
+		&cli.Uint64SliceFlag{
+			Name:  "forbidden-method",
+			Usage: "method numbers can not be invoked",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return fmt.Errorf("'set' expects one argument, the address")
+		}
+
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		addr, err := address.NewFromString(cctx.Args().First())
+		if err != nil {
+			return fmt.Errorf("failed to parse address: %w", err)
+		}
+
+		policy := &config.AddressPolicy{
+			Address:          addr.String(),
+			DailyLimit:       cctx.String("daily-limit"),
+			WeeklyLimit:      cctx.String("weekly-limit"),
+			AllowedTo:        cctx.StringSlice("allowed-to"),
+			ForbiddenMethods: cctx.Uint64Slice("forbidden-method"),
+		}
+		if err := api.PolicySet(cctx.Context, policy); err != nil {
+			return err
+		}
+		fmt.Printf("policy of %s is set\n", addr)
+		return nil
+	},
+}
+
+var policyRemoveCmd = &cli.Command{
+	Name:      "remove",
+	Usage:     "Remove the spending policy of an address, token is required to identify the operator",
+	ArgsUsage: "<address>",
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return fmt.Errorf("'remove' expects one argument, the address")
+		}
+
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		addr, err := address.NewFromString(cctx.Args().First())
+		if err != nil {
+			return fmt.Errorf("failed to parse address: %w", err)
+		}
+
+		if err := api.PolicyRemove(cctx.Context, &service.Address{Address: addr}); err != nil {
+			return err
+		}
+		fmt.Printf("policy of %s is removed\n", addr)
+		return nil
+	},
+}
+
+var policyApprovalListCmd = &cli.Command{
+	Name:  "approvals",
+	Usage: "List approval requests of messages exceeding the spending limits",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "state",
+			Usage: "filter by state: pending | approved | rejected",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		approvals, err := api.PolicyApprovalList(cctx.Context, &service.PolicyApprovalListReq{
+			State: service.PolicyApprovalState(cctx.String("state")),
+		})
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "ID\tState\tFrom\tTo\tValue\tMethod\tRequester\tApprover\tMsgID\tCreatedAt\tReason\n")
+		for _, a := range approvals {
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+				a.ID,
+				a.State,
+				a.Msg.From,
+				a.Msg.To,
+				types.FIL(a.Msg.Value),
+				a.Msg.Method,
+				orNone(a.Requester),
+				orNone(a.Approver),
+				orNone(a.MsgID),
+				a.CreatedAt.Format(time.RFC3339),
+				a.Reason,
+			)
+		}
+		return w.Flush()
+	},
+}
+
+func parsePolicyApproveReq(cctx *cli.Context) (*service.PolicyApproveReq, error) {
+	if cctx.NArg() != 1 {
+		return nil, fmt.Errorf("'%s' expects one argument, the approval request id", cctx.Command.Name)
+	}
+	id, err := strconv.ParseUint(cctx.Args().First(), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse approval request id: %w", err)
+	}
+	if cctx.String(FlagToken.Name) == "" {
+		return nil, fmt.Errorf("token is required to identify the approver")
+	}
+	return &service.PolicyApproveReq{ID: id}, nil
+}
+
+var policyApproveCmd = &cli.Command{
+	Name:      "approve",
+	Usage:     "Approve and send the message of an approval request",
+	ArgsUsage: "<approvalID>",
+	Action: func(cctx *cli.Context) error {
+		req, err := parsePolicyApproveReq(cctx)
+		if err != nil {
+			return err
+		}
+
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		id, err := api.PolicyApprove(cctx.Context, req)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("approval request %d approved, send message (id: %s ) success\n", req.ID, id)
+		return nil
+	},
+}
+
+var policyRejectCmd = &cli.Command{
+	Name:      "reject",
+	Usage:     "Reject an approval request",
+	ArgsUsage: "<approvalID>",
+	Action: func(cctx *cli.Context) error {
+		req, err := parsePolicyApproveReq(cctx)
+		if err != nil {
+			return err
+		}
+
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		if err := api.PolicyReject(cctx.Context, req); err != nil {
+			return err
+		}
+		fmt.Printf("approval request %d rejected\n", req.ID)
+		return nil
+	},
+}
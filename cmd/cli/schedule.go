@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/ipfs-force-community/venus-tool/service"
+	"github.com/urfave/cli/v2"
+)
+
+var ScheduleCmd = &cli.Command{
+	Name:  "schedule",
+	Usage: "Send messages at a future epoch / time or when a condition holds",
+	Subcommands: []*cli.Command{
+		scheduleAddCmd,
+		scheduleListCmd,
+		scheduleCancelCmd,
+	},
+}
+
+var scheduleAddCmd = &cli.Command{
+	Name:  "add",
+	Usage: "Schedule a message, exactly one of 'at-epoch', 'at-time', 'balance-below' and 'balance-above' should be set",
+	Description: `Examples:
+   venus-tool schedule add --from f1xxx --at-epoch 3000000 f01234 10
+   venus-tool schedule add --from f1xxx --balance-below 5 --watch f1yyy f1yyy 20`,
+	ArgsUsage: "<targetAddress> <amount>",
+	Flags: append(msgSendFlags,
+		&cli.Int64Flag{
+			Name:  "at-epoch",
+			Usage: "send the message once the chain reaches the epoch",
+		},
+		&cli.TimestampFlag{
+			Name:     "at-time",
+			Usage:    "send the message after the time, eg: 2006-01-02T15:04:05",
+			Layout:   "2006-01-02T15:04:05",
+			Timezone: time.Local,
+		},
+		&cli.StringFlag{
+			Name:  "balance-below",
+			Usage: "send the message when the balance of the watched address drops below the amount in FIL",
+		},
+		&cli.StringFlag{
+			Name:  "balance-above",
+			Usage: "send the message when the balance of the watched address rises above the amount in FIL",
+		},
+		&cli.StringFlag{
+			Name:  "watch",
+			Usage: "the address whose balance is watched, default to the target address",
+		},
+	),
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		msg, err := parseMsgSendReq(cctx)
+		if err != nil {
+			return err
+		}
+		if err := completeParamsBySchema(cctx, api, msg); err != nil {
+			return err
+		}
+
+		trigger, err := parseScheduleTrigger(cctx, msg.To)
+		if err != nil {
+			return err
+		}
+
+		task, err := api.ScheduleAdd(cctx.Context, &service.ScheduleAddReq{
+			Msg:     *msg,
+			Trigger: *trigger,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("schedule task %d added: %s\n", task.ID, task.Trigger)
+		return nil
+	},
+}
+
+func parseScheduleTrigger(cctx *cli.Context, to address.Address) (*service.ScheduleTrigger, error) {
+	var triggers []*service.ScheduleTrigger
+
+	if cctx.IsSet("at-epoch") {
+		triggers = append(triggers, &service.ScheduleTrigger{
+			Type:  service.ScheduleAtEpoch,
+			Epoch: abi.ChainEpoch(cctx.Int64("at-epoch")),
+		})
+	}
+	if cctx.IsSet("at-time") {
+		triggers = append(triggers, &service.ScheduleTrigger{
+			Type: service.ScheduleAtTime,
+			Time: *cctx.Timestamp("at-time"),
+		})
+	}
+	for flag, typ := range map[string]service.ScheduleTriggerType{
+		"balance-below": service.ScheduleBalanceBelow,
+		"balance-above": service.ScheduleBalanceAbove,
+	} {
+		if !cctx.IsSet(flag) {
+			continue
+		}
+		bal, err := types.ParseFIL(cctx.String(flag))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", flag, err)
+		}
+		watch := to
+		if cctx.IsSet("watch") {
+			watch, err = address.NewFromString(cctx.String("watch"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse watch address: %w", err)
+			}
+		}
+		triggers = append(triggers, &service.ScheduleTrigger{
+			Type:    typ,
+			Address: watch,
+			Balance: abi.TokenAmount(bal),
+		})
+	}
+
+	if len(triggers) != 1 {
+		return nil, fmt.Errorf("exactly one of 'at-epoch', 'at-time', 'balance-below' and 'balance-above' should be set")
+	}
+	return triggers[0], triggers[0].Validate()
+}
+
+var scheduleListCmd = &cli.Command{
+	Name:  "list",
+	Usage: "List scheduled tasks",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:  "state",
+			Usage: "filter tasks by state: pending | sent | failed | canceled",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		tasks, err := api.ScheduleList(cctx.Context, &service.ScheduleListReq{
+			State: service.ScheduleTaskState(cctx.String("state")),
+		})
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "ID\tState\tTrigger\tFrom\tTo\tValue\tMethod\tMsgID\tCreatedAt\tError\n")
+		for _, task := range tasks {
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n",
+				task.ID,
+				task.State,
+				task.Trigger,
+				task.Msg.From,
+				task.Msg.To,
+				types.FIL(task.Msg.Value),
+				task.Msg.Method,
+				task.MsgID,
+				task.CreatedAt.Format(time.RFC3339),
+				task.Error,
+			)
+		}
+		return w.Flush()
+	},
+}
+
+var scheduleCancelCmd = &cli.Command{
+	Name:      "cancel",
+	Usage:     "Cancel a pending scheduled task",
+	ArgsUsage: "<taskID>",
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return fmt.Errorf("'cancel' expects one argument, the task id")
+		}
+
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		id, err := strconv.ParseUint(cctx.Args().First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("failed to parse task id: %w", err)
+		}
+
+		if err := api.ScheduleCancel(cctx.Context, &service.ScheduleTaskID{ID: id}); err != nil {
+			return err
+		}
+		fmt.Printf("schedule task %d canceled\n", id)
+		return nil
+	},
+}
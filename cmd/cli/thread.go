@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/ipfs-force-community/venus-tool/service"
+	"github.com/urfave/cli/v2"
+)
+
+var ThreadCmd = &cli.Command{
+	Name:  "thread",
+	Usage: "Manage sealing threads of damocles workers",
+	Subcommands: []*cli.Command{
+		threadListCmd,
+		threadPauseCmd,
+		threadResumeCmd,
+	},
+}
+
+var threadListCmd = &cli.Command{
+	Name:  "list",
+	Usage: "List sealing threads of all workers",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "json",
+			Usage: "output in json format",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		ret, err := api.ThreadList(cctx.Context)
+		if err != nil {
+			return err
+		}
+
+		if cctx.Bool("json") {
+			return printJSON(ret)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "Worker\tIndex\tPlan\tJobID\tJobState\tJobStage\tThreadState\n")
+		for _, t := range ret.Threads {
+			jobID, jobStage := "", ""
+			if t.JobID != nil {
+				jobID = *t.JobID
+			}
+			if t.JobStage != nil {
+				jobStage = *t.JobStage
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\n",
+				t.WorkerInfo.Name,
+				t.Index,
+				t.Plan,
+				orNone(jobID),
+				orNone(t.JobState),
+				orNone(jobStage),
+				t.ThreadState,
+			)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+
+		for _, worker := range ret.Workers {
+			if worker.Error != "" {
+				fmt.Printf("failed to list threads of %s(%s): %s\n", worker.Info.Name, worker.Info.Dest, worker.Error)
+			}
+		}
+		return nil
+	},
+}
+
+var threadSelectorFlags = []cli.Flag{
+	&cli.StringSliceFlag{
+		Name:  "worker",
+		Usage: "select threads on the workers",
+	},
+	&cli.StringSliceFlag{
+		Name:  "plan",
+		Usage: "select threads of the plans, eg: sealer, snapup",
+	},
+	&cli.StringSliceFlag{
+		Name:  "stage",
+		Usage: "select threads whose job is in the stages",
+	},
+	&cli.StringSliceFlag{
+		Name:  "state",
+		Usage: "select threads whose job is in the states",
+	},
+	&cli.BoolFlag{
+		Name:  "all",
+		Usage: "select all threads in the cluster if no other selector is set",
+	},
+	&cli.BoolFlag{
+		Name:  "really-do-it",
+		Usage: "Actually control the threads, token is required to identify the operator",
+	},
+}
+
+var threadPauseCmd = &cli.Command{
+	Name:  "pause",
+	Usage: "Pause threads selected across workers, preview the affected threads unless --really-do-it is set",
+	Flags: threadSelectorFlags,
+	Action: func(cctx *cli.Context) error {
+		return threadBulkControl(cctx, service.ThreadPause)
+	},
+}
+
+var threadResumeCmd = &cli.Command{
+	Name:  "resume",
+	Usage: "Resume paused threads selected across workers, preview the affected threads unless --really-do-it is set",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{
+			Name:  "set-state",
+			Usage: "the state to resume the threads to, eg: Aborted, keep the current state if not set",
+		},
+	}, threadSelectorFlags...),
+	Action: func(cctx *cli.Context) error {
+		return threadBulkControl(cctx, service.ThreadResume)
+	},
+}
+
+func threadBulkControl(cctx *cli.Context, action service.ThreadAction) error {
+	api, err := getAPI(cctx)
+	if err != nil {
+		return err
+	}
+
+	req := &service.ThreadBulkControlReq{
+		ThreadSelector: service.ThreadSelector{
+			Worker: cctx.StringSlice("worker"),
+			Plan:   cctx.StringSlice("plan"),
+			Stage:  cctx.StringSlice("stage"),
+			State:  cctx.StringSlice("state"),
+		},
+		Action: action,
+		All:    cctx.Bool("all"),
+		DryRun: !cctx.Bool("really-do-it"),
+	}
+	if cctx.IsSet("set-state") {
+		state := cctx.String("set-state")
+		req.SetState = &state
+	}
+	if !req.DryRun && cctx.String(FlagToken.Name) == "" {
+		return fmt.Errorf("token is required to identify the operator")
+	}
+
+	ret, err := api.ThreadBulkControl(cctx.Context, req)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	_, _ = fmt.Fprintf(w, "Worker\tIndex\tPlan\tJobID\tJobState\tJobStage\tThreadState\tApplied\tError\n")
+	for _, r := range ret.Results {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%t\t%s\n",
+			r.WorkerName,
+			r.Index,
+			r.Plan,
+			orNone(r.JobID),
+			orNone(r.JobState),
+			orNone(r.JobStage),
+			r.ThreadState,
+			r.Applied,
+			orNone(r.Error),
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, worker := range ret.FailedWorkers {
+		fmt.Printf("skipped %s(%s) whose threads cannot be listed: %s\n", worker.Info.Name, worker.Info.Dest, worker.Error)
+	}
+	if ret.DryRun {
+		fmt.Printf("\n%d threads matched, pass --really-do-it to actually %s them\n", ret.Matched, action)
+		return nil
+	}
+	fmt.Printf("\n%d threads matched, %d applied, %d failed\n", ret.Matched, ret.Applied, ret.Failed)
+	return nil
+}
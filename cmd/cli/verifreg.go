@@ -0,0 +1,265 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/docker/go-units"
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/ipfs-force-community/venus-tool/service"
+	"github.com/urfave/cli/v2"
+)
+
+var VerifregCmd = &cli.Command{
+	Name:  "verifreg",
+	Usage: "Manage FIL+ claims and allocations of miners",
+	Subcommands: []*cli.Command{
+		verifregClaimListCmd,
+		verifregAllocationListCmd,
+		verifregExtendClaimsCmd,
+		verifregRemoveExpiredClaimsCmd,
+	},
+}
+
+var verifregClaimListCmd = &cli.Command{
+	Name:      "claims",
+	Usage:     "List verified claims of a miner",
+	ArgsUsage: "<miner address>",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{
+			Name:  "expiring",
+			Usage: "only list claims expiring soon",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() != 1 {
+			return fmt.Errorf("'claims' expects one argument, the miner address")
+		}
+		mAddr, err := address.NewFromString(cctx.Args().First())
+		if err != nil {
+			return err
+		}
+
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		claims, err := api.VerifregClaimList(cctx.Context, service.Address{Address: mAddr})
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "ID\tClient\tSector\tData\tSize\tTermMin\tTermMax\tTermStart\tExpiration\tExpiringSoon\n")
+		for _, c := range claims {
+			if cctx.Bool("expiring") && !c.ExpiringSoon {
+				continue
+			}
+			client, err := address.NewIDAddress(uint64(c.Client))
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%d\t%s\t%s\t%d\t%d\t%d\t%d\t%t\n",
+				c.ID,
+				client,
+				c.Sector,
+				c.Data,
+				units.BytesSize(float64(c.Size)),
+				c.TermMin,
+				c.TermMax,
+				c.TermStart,
+				c.Expiration,
+				c.ExpiringSoon,
+			)
+		}
+		return w.Flush()
+	},
+}
+
+var verifregAllocationListCmd = &cli.Command{
+	Name:      "allocations",
+	Usage:     "List pending allocations from clients to miners",
+	ArgsUsage: "[miner address]",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:  "client",
+			Usage: "clients to list allocations of, default is the clients of verified deals of miners",
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		req := &service.VerifregAllocationListReq{}
+		switch cctx.NArg() {
+		case 0:
+		case 1:
+			mAddr, err := address.NewFromString(cctx.Args().First())
+			if err != nil {
+				return err
+			}
+			req.Miner = []address.Address{mAddr}
+		default:
+			return fmt.Errorf("too many arguments")
+		}
+		for _, c := range cctx.StringSlice("client") {
+			client, err := address.NewFromString(c)
+			if err != nil {
+				return fmt.Errorf("failed to parse client address: %w", err)
+			}
+			req.Client = append(req.Client, client)
+		}
+
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		allocations, err := api.VerifregAllocationList(cctx.Context, req)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		_, _ = fmt.Fprintf(w, "ID\tClient\tProvider\tData\tSize\tTermMin\tTermMax\tExpiration\tExpired\n")
+		for _, a := range allocations {
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d\t%d\t%d\t%t\n",
+				a.ID,
+				a.ClientAddr,
+				a.ProviderAddr,
+				a.Data,
+				units.BytesSize(float64(a.Size)),
+				a.TermMin,
+				a.TermMax,
+				a.Expiration,
+				a.Expired,
+			)
+		}
+		return w.Flush()
+	},
+}
+
+func parseClaimIDs(args []string) ([]types.ClaimId, error) {
+	ids := make([]types.ClaimId, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.ParseUint(arg, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse claim id %s: %w", arg, err)
+		}
+		ids = append(ids, types.ClaimId(id))
+	}
+	return ids, nil
+}
+
+var verifregExtendClaimsCmd = &cli.Command{
+	Name:      "extend-claims",
+	Usage:     "Extend the max term of claims, should be sent by the client of the claims",
+	ArgsUsage: "<miner address> <claim id>...",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "from",
+			Usage:    "the client of the claims",
+			Required: true,
+		},
+		&cli.Int64Flag{
+			Name:     "term-max",
+			Usage:    "new max term of the claims in epochs",
+			Required: true,
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() < 2 {
+			return fmt.Errorf("must pass miner address and at least one claim id")
+		}
+		mAddr, err := address.NewFromString(cctx.Args().First())
+		if err != nil {
+			return err
+		}
+		from, err := address.NewFromString(cctx.String("from"))
+		if err != nil {
+			return err
+		}
+		ids, err := parseClaimIDs(cctx.Args().Tail())
+		if err != nil {
+			return err
+		}
+
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("This will take some time (maybe 5 epoch), to ensure message is chained...")
+		resp, err := api.VerifregExtendClaimTerms(cctx.Context, &service.VerifregExtendClaimTermsReq{
+			From:     from,
+			Miner:    mAddr,
+			ClaimIDs: ids,
+			TermMax:  abi.ChainEpoch(cctx.Int64("term-max")),
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d claims extended, message id: %s\n", resp.Succeeded, resp.MsgID)
+		return printClaimFailures(resp)
+	},
+}
+
+var verifregRemoveExpiredClaimsCmd = &cli.Command{
+	Name:      "remove-expired-claims",
+	Usage:     "Remove expired claims of a miner",
+	ArgsUsage: "<miner address> [claim id]...",
+	Flags: []cli.Flag{
+		&cli.StringFlag{
+			Name:     "from",
+			Usage:    "the address to send the message",
+			Required: true,
+		},
+	},
+	Action: func(cctx *cli.Context) error {
+		if cctx.NArg() < 1 {
+			return fmt.Errorf("must pass miner address")
+		}
+		mAddr, err := address.NewFromString(cctx.Args().First())
+		if err != nil {
+			return err
+		}
+		from, err := address.NewFromString(cctx.String("from"))
+		if err != nil {
+			return err
+		}
+		ids, err := parseClaimIDs(cctx.Args().Tail())
+		if err != nil {
+			return err
+		}
+
+		api, err := getAPI(cctx)
+		if err != nil {
+			return err
+		}
+
+		fmt.Println("This will take some time (maybe 5 epoch), to ensure message is chained...")
+		resp, err := api.VerifregRemoveExpiredClaims(cctx.Context, &service.VerifregRemoveExpiredClaimsReq{
+			From:     from,
+			Miner:    mAddr,
+			ClaimIDs: ids,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d expired claims removed, message id: %s\n", resp.Succeeded, resp.MsgID)
+		return printClaimFailures(resp)
+	},
+}
+
+// printClaimFailures prints the claims failed in the message, and fails if there is any
+func printClaimFailures(resp *service.VerifregClaimsResp) error {
+	if len(resp.Failed) == 0 {
+		return nil
+	}
+	for _, f := range resp.Failed {
+		fmt.Printf("claim %d failed: %s\n", f.ID, f.Code)
+	}
+	return fmt.Errorf("%d claims failed", len(resp.Failed))
+}
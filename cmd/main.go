@@ -111,6 +111,7 @@ func main() {
 		Flags: []cli.Flag{
 			flagRepo,
 			vtCli.FlagServer,
+			vtCli.FlagToken,
 		},
 		Commands: []*cli.Command{
 			runCmd,
@@ -122,6 +123,10 @@ func main() {
 			vtCli.ChainCmd,
 			vtCli.MultiSigCmd,
 			vtCli.WalletCmd,
+			vtCli.ScheduleCmd,
+			vtCli.PolicyCmd,
+			vtCli.VerifregCmd,
+			vtCli.ThreadCmd,
 		},
 	}
 	app.Setup()
@@ -234,6 +239,7 @@ var runCmd = &cli.Command{
 			builder.Override(new(*service.ServiceImpl), service.NewService),
 			builder.Override(builder.NextInvoke(), utils.SetupLogLevels),
 			builder.Override(builder.NextInvoke(), utils.LoadBuiltinActors),
+			builder.Override(builder.NextInvoke(), service.StartScheduler),
 			builder.Override(builder.NextInvoke(), route.RegisterAndStart),
 		)
 		if err != nil {
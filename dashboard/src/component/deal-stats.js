@@ -0,0 +1,103 @@
+import { Col, Empty, Row, Statistic, Table } from "antd"
+import Card from "./card";
+import { InShort } from "./util";
+import { useDealStats, useMiners } from "../fetcher";
+import { Power } from "../util";
+
+export default function DealStats(props) {
+    const { data: miners } = useMiners()
+    const { data: stats } = useDealStats(miners)
+
+    const ret = function (content) {
+        return (
+            <Card title={"Deal Pipeline"} >
+                {content}
+            </Card>
+        )
+    }
+
+    if (!stats) {
+        return ret(<Empty />)
+    }
+
+    const ageColumns = [
+        {
+            title: 'Count',
+            dataIndex: 'Count',
+            sorter: (a, b) => a.Count - b.Count,
+        },
+        {
+            title: 'Piece Size',
+            dataIndex: 'PieceSize',
+            render: (_, record) => Power(record.PieceSize),
+        },
+        {
+            title: '< 1d',
+            render: (_, record) => record.Age.LessThan1Day,
+        },
+        {
+            title: '1-3d',
+            render: (_, record) => record.Age.OneToThreeDays,
+        },
+        {
+            title: '3-7d',
+            render: (_, record) => record.Age.ThreeToSevenDays,
+        },
+        {
+            title: '> 7d',
+            render: (_, record) => record.Age.MoreThan7Days,
+        },
+        {
+            title: 'Oldest',
+            render: (_, record) => (<InShort text={record.Oldest.ProposalCid["/"]} />),
+        },
+        {
+            title: 'Since',
+            render: (_, record) => new Date(record.Oldest.UpdatedAt).toLocaleString(),
+        },
+    ]
+
+    const stateColumns = [{ title: 'Deal State', dataIndex: 'StateName' }, ...ageColumns]
+    const pieceColumns = [{ title: 'Piece State', dataIndex: 'PieceStatus' }, ...ageColumns]
+    const trendColumns = [
+        { title: 'Date', dataIndex: 'Date' },
+        { title: 'Accepted', dataIndex: 'Accepted' },
+        { title: 'Sealed', dataIndex: 'Sealed' },
+        { title: 'Failed', dataIndex: 'Failed' },
+    ]
+
+    const content = (
+        <>
+            <Row gutter={16}>
+                <Col span={6}>
+                    <Statistic title="Deals" value={stats.Total} />
+                </Col>
+                <Col span={6}>
+                    <Statistic title="Piece Size" value={Power(stats.PieceSize)} />
+                </Col>
+            </Row>
+            <Table
+                rowKey={record => record.State}
+                columns={stateColumns}
+                dataSource={stats.States || []}
+                pagination={false}
+                size="small"
+            ></Table>
+            <Table
+                rowKey={record => record.PieceStatus}
+                columns={pieceColumns}
+                dataSource={stats.PieceStatuses || []}
+                pagination={false}
+                size="small"
+            ></Table>
+            <Table
+                rowKey={record => record.Date}
+                columns={trendColumns}
+                dataSource={stats.Trend || []}
+                pagination={false}
+                size="small"
+            ></Table>
+        </>
+    )
+    return ret(content)
+}
@@ -43,6 +43,15 @@ export default function Header(props) {
                 case "wallet":
                     navigate(`/wallet/${value}`)
                     break
+                case "piece": {
+                    const deal = res.Data?.Pieces?.flatMap(p => p.Deals || [])[0]
+                    if (deal) {
+                        navigate(`/deal/${deal.ProposalCid["/"]}`)
+                    } else {
+                        console.error("no deal found for piece", value)
+                    }
+                    break
+                }
                 default:
                     console.error("unknown type", res.Type)
             }
@@ -78,7 +87,10 @@ export default function Header(props) {
                         <Search style={searchStyle} size='middle' className='App-search' placeholder='enter cid or address' width={400} allowClear onSearch={onSearch} />
                     </Affix>
                 </Col>
-                <Col offset={4} span={2} >
+                <Col offset={2} span={2} >
+                    <Link to={'/msig/inbox'} >Approvals</Link>
+                </Col>
+                <Col span={2} >
                     <Select size='small' defaultValue={"en"} options={[{ label: 'en', value: 'enUS' }]} />
                 </Col>
             </Row>
@@ -1,4 +1,4 @@
-import { Col, Row, Table, Popover, Space, Descriptions, Modal, Button } from "antd"
+import { Col, Row, Table, Popover, Space, Descriptions, Modal, Button, Alert } from "antd"
 import { PauseCircleOutlined, PlayCircleOutlined, ExclamationCircleOutlined, DeleteOutlined } from '@ant-design/icons';
 import { useState } from "react"
 import { Copyable, getDefaultFilters } from "./util";
@@ -9,7 +9,7 @@ import { useThreads } from "../fetcher";
 
 export default function SealingThreadList() {
     const [selectedRowKeys, setSelectedRowKeys] = useState([]);
-    const { data: threads, mutate: updateThreads } = useThreads()
+    const { data: threadList, mutate: updateThreads } = useThreads()
 
     const stopThreads = (threads) => {
         if (!threads) {
@@ -82,7 +82,8 @@ export default function SealingThreadList() {
     }
 
     // prepare data
-    let data = preprocess(threads)
+    let data = preprocess(threadList ? threadList.Threads : [])
+    const failedWorkers = (threadList && Array.isArray(threadList.Workers)) ? threadList.Workers.filter(worker => worker.Error) : []
 
     // the footer of sealing thread table
     const footer = () => {
@@ -217,14 +218,25 @@ export default function SealingThreadList() {
             defaultPageSize: 10,
         }
         return (
-            <Table
-                rowKey={record => record.Key}
-                rowSelection={rowSelection}
-                columns={columns}
-                dataSource={data}
-                pagination={pagination}
-                footer={footer}
-            ></Table>
+            <>
+                {failedWorkers.map(worker => (
+                    <Alert
+                        key={worker.Info.Name}
+                        type="warning"
+                        showIcon
+                        style={{ marginBottom: 8 }}
+                        message={`failed to list threads of ${worker.Info.Name} (${worker.Info.Dest}): ${worker.Error}`}
+                    />
+                ))}
+                <Table
+                    rowKey={record => record.Key}
+                    rowSelection={rowSelection}
+                    columns={columns}
+                    dataSource={data}
+                    pagination={pagination}
+                    footer={footer}
+                ></Table>
+            </>
         )
     }
     return ret(table)
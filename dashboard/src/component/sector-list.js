@@ -63,6 +63,15 @@ export default function SectorList({ miner }) {
             title: 'Deals',
             dataIndex: 'DealIDs',
         },
+        {
+            title: 'ClaimExpiration',
+            dataIndex: 'ClaimExpiration',
+            render: (_, record) => record.ClaimExpiration ? (
+                <span style={record.ClaimExpiringSoon ? { color: 'red' } : {}}>
+                    {record.ClaimExpiration}{record.ClaimExpiringSoon ? ' (expiring soon)' : ''}
+                </span>
+            ) : '-',
+        },
     ]
 
 
@@ -58,7 +58,7 @@ export const useMsgInfo = function (id) {
 }
 
 export const useThreads = function () {
-    return useSWR(rel("/thread/list"), { fallbackData: [] })
+    return useSWR(rel("/thread/list"), { fallbackData: { Threads: [], Workers: [] } })
 }
 
 export const useMiners = function () {
@@ -133,7 +133,10 @@ export const useSectors = function ({ miner, pageIndex, pageSize }) {
 }
 
 export const useDeals = function ({ miner }) {
-    return useSWR(rel(`/deal/storage/"${miner}"`), fetchOrError(miner ? null : new Error("useDeals: miner is null")), { fallbackData: [] })
+    const params = miner ? {
+        "Miner": [`"${miner}"`],
+    } : new Error("useDeals: miner is null")
+    return useSWR([rel("/deal/storage/list"), params], p => fetcherGetWithParams(p).then(res => res.Deals || []), { fallbackData: [] })
 }
 
 export const useDealInfo = function (pCid) {
@@ -141,20 +144,26 @@ export const useDealInfo = function (pCid) {
 }
 
 export const useAllDeals = function (miners) {
-    let ret = []
-    let promises = miners.map(miner => {
-        return axios.get(rel(`/deal/storage/"${miner}"`)).then(res => {
-            return res.data
-        })
-    })
-    const fetcher = () => axios.all(promises).then(res => {
-        res.forEach(data => {
-            ret = ret.concat(data)
-        })
-        return ret
-    })
+    const params = miners && miners.length > 0 ? {
+        "Miner": miners.map(miner => `"${miner}"`),
+    } : new Error("useAllDeals: miners is empty")
+    return useSWR([rel("/deal/storage/list"), params], p => fetcherGetWithParams(p).then(res => res.Deals || []), { fallbackData: [] })
+}
+
+export const useDealStats = function (miners) {
+    const params = miners && miners.length > 0 ? {
+        "Miner": miners.map(miner => `"${miner}"`),
+        "Days": 7,
+    } : new Error("useDealStats: miners is empty")
+    return useSWR([rel("/deal/stats"), params], fetcherGetWithParams)
+}
+
 
-    return useSWR(["all_deals", miners], fetcher, { fallbackData: [] })
+export const useMsigInbox = function (signers) {
+    const params = signers && signers.length > 0 ? {
+        "Signer": signers.map(signer => `"${signer}"`),
+    } : {}
+    return useSWR([rel("/msig/inbox"), params], fetcherGetWithParams)
 }
 
 
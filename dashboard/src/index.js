@@ -9,6 +9,7 @@ import MsgDetail from '@/pages/msg-detail';
 import MinerDetail from '@/pages/miner-detail';
 import WalletDetail from './pages/wallet-detail';
 import DealDetail from './pages/deal-detail';
+import MsigInbox from './pages/msig-inbox';
 import NotFound from './pages/not-found';
 import ErrorPage from './pages/error';
 
@@ -22,6 +23,7 @@ const router = createRoutesFromElements(
         <Route path="/miner/:id" element={<MinerDetail />} />
         <Route path="/wallet/:id" element={<WalletDetail />} />
         <Route path="/deal/:id" element={<DealDetail />} />
+        <Route path="/msig/inbox" element={<MsigInbox />} />
         <Route path="404" element={<NotFound />} />
         <Route path="error" element={<ErrorPage />} />
         <Route path="/message/markbad/:id" action={params => {
@@ -3,6 +3,7 @@ import Summary from '@/component/summary';
 import MsgList from '@/component/msg-list';
 import SealingThreadList from '@/component/sealing-thread-list';
 import DealList from '@/component/deal-list';
+import DealStats from '@/component/deal-stats';
 import Asset from '../component/asset';
 import BlockList from '../component/block-list';
 import ErrorBoundary from '../component/error-boundary';
@@ -16,6 +17,7 @@ function App() {
       <MsgList />
       <SealingThreadList />
       <BlockList />
+      <DealStats />
       <DealList />
     </Space>
   );
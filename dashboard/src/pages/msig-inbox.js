@@ -0,0 +1,101 @@
+import Card from "@/component/card"
+import { Empty, Table, Typography } from "antd"
+import { useMsigInbox } from "../fetcher"
+import { InShort } from "../component/util"
+import { Fil } from "../util.ts"
+
+const { Paragraph } = Typography
+
+// base64ToHex converts the bytes encoded by go json to hex
+const base64ToHex = (b64) => {
+    if (!b64) {
+        return ""
+    }
+    return Array.from(atob(b64), c => c.charCodeAt(0).toString(16).padStart(2, "0")).join("")
+}
+
+const formatAge = (ns) => {
+    const minutes = Math.floor(ns / 6e10)
+    const days = Math.floor(minutes / 1440)
+    const hours = Math.floor((minutes % 1440) / 60)
+    if (days > 0) {
+        return `${days}d ${hours}h`
+    }
+    return `${hours}h ${minutes % 60}m`
+}
+
+export default function MsigInbox() {
+    const title = "Approvals Inbox"
+    const { data, isLoading } = useMsigInbox()
+
+    if (isLoading) {
+        return (<Card title={title} loading={true} />)
+    }
+    if (!data || data.length === 0) {
+        return (<Card title={title}><Empty description="no transactions awaiting approval" /></Card>)
+    }
+
+    const columns = [
+        {
+            title: 'Multisig',
+            dataIndex: 'Msig',
+        },
+        {
+            title: 'TxID',
+            dataIndex: 'ID',
+        },
+        {
+            title: 'To',
+            dataIndex: 'To',
+        },
+        {
+            title: 'Value',
+            render: (_, record) => Fil(record.Value),
+        },
+        {
+            title: 'Method',
+            render: (_, record) => {
+                const method = record.MethodName || `method ${record.Method}`
+                return record.ActorType ? `${record.ActorType}.${method}` : method
+            },
+        },
+        {
+            title: 'Approvals',
+            render: (_, record) => `${record.Approved ? record.Approved.length : 0}/${record.Threshold}`,
+        },
+        {
+            title: 'Awaiting',
+            render: (_, record) => record.Signers.map(s => (<InShort key={s} text={s} />)),
+        },
+        {
+            title: 'Age',
+            sorter: (a, b) => a.Age - b.Age,
+            render: (_, record) => formatAge(record.Age),
+        },
+    ]
+
+    const expandedRowRender = (record) => {
+        return (
+            <>
+                <Paragraph>Proposer: {record.Proposer}</Paragraph>
+                <Paragraph copyable>Hash: {base64ToHex(record.Hash)}</Paragraph>
+                <Paragraph>First Seen: {new Date(record.FirstSeen).toLocaleString()}</Paragraph>
+                {record.DecodeError ?
+                    <Paragraph type="warning">Cannot decode the transaction: {record.DecodeError}</Paragraph> :
+                    <pre>{record.ParamsInJson ? JSON.stringify(record.ParamsInJson, null, 2) : "no params"}</pre>}
+            </>
+        )
+    }
+
+    return (
+        <Card title={title}>
+            <Table
+                rowKey={(record) => `${record.Msig}-${record.ID}`}
+                dataSource={data}
+                columns={columns}
+                expandable={{ expandedRowRender }}
+                pagination={{ pageSize: 20 }}
+            />
+        </Card>
+    )
+}
@@ -46,6 +46,7 @@ func NewDamocles(ctx context.Context, lc fx.Lifecycle, cfg *config.Config) (*Dam
 
 type WorkerThreadInfo = core.WorkerThreadInfo
 type WorkerPingInfo = core.WorkerPingInfo
+type WorkerInfo = core.WorkerInfo
 
 type ThreadInfo struct {
 	*core.WorkerThreadInfo
@@ -9,6 +9,8 @@ import (
 	"github.com/filecoin-project/venus/venus-shared/api/wallet"
 	logging "github.com/ipfs/go-log/v2"
 	"go.uber.org/fx"
+
+	"github.com/ipfs-force-community/venus-tool/repo/config"
 )
 
 var log = logging.Logger("dep")
@@ -24,6 +26,7 @@ type ServiceParams struct {
 	Auth     IAuth
 	Damocles *Damocles
 	Miner    Miner
+	Config   *config.Config
 }
 
 type IWallet interface {
@@ -62,13 +62,13 @@ func (a *multiSig) messageBuilder(ctx context.Context, from address.Address) (mu
 // MsigCreate creates a multisig wallet
 // It takes the following params: <required number of senders>, <approving addresses>, <unlock duration>
 // <initial balance>, <sender address of the create msg>, <gas price>
-func (a *multiSig) MsigCreate(ctx context.Context, req uint64, addrs []address.Address, duration abi.ChainEpoch, val types.BigInt, src address.Address, gp types.BigInt) (*types.MessagePrototype, error) {
+func (a *multiSig) MsigCreate(ctx context.Context, req uint64, addrs []address.Address, start abi.ChainEpoch, duration abi.ChainEpoch, val types.BigInt, src address.Address, gp types.BigInt) (*types.MessagePrototype, error) {
 	mb, err := a.messageBuilder(ctx, src)
 	if err != nil {
 		return nil, err
 	}
 
-	msg, err := mb.Create(addrs, req, 0, duration, val)
+	msg, err := mb.Create(addrs, req, start, duration, val)
 	if err != nil {
 		return nil, err
 	}
@@ -252,6 +252,29 @@ func (a *multiSig) MsigRemoveSigner(ctx context.Context, msig address.Address, p
 	return a.MsigPropose(ctx, msig, msig, types.NewInt(0), proposer, uint64(multisig.Methods.RemoveSigner), enc)
 }
 
+// MsigChangeThresholdPropose proposes changing the number of approvals required by the multisig
+// It takes the following params: <multisig address>, <sender address of the propose msg>, <new threshold>
+func (a *multiSig) MsigChangeThresholdPropose(ctx context.Context, msig address.Address, src address.Address, newThreshold uint64) (*types.MessagePrototype, error) {
+	enc, actErr := serializeThresholdParams(newThreshold)
+	if actErr != nil {
+		return nil, actErr
+	}
+
+	return a.MsigPropose(ctx, msig, msig, big.Zero(), src, uint64(multisig.Methods.ChangeNumApprovalsThreshold), enc)
+}
+
+// MsigLockPropose proposes locking a part of the multisig balance
+// It takes the following params: <multisig address>, <sender address of the propose msg>,
+// <start epoch>, <unlock duration>, <amount to lock>
+func (a *multiSig) MsigLockPropose(ctx context.Context, msig address.Address, src address.Address, start abi.ChainEpoch, duration abi.ChainEpoch, amount types.BigInt) (*types.MessagePrototype, error) {
+	enc, actErr := serializeLockParams(start, duration, amount)
+	if actErr != nil {
+		return nil, actErr
+	}
+
+	return a.MsigPropose(ctx, msig, msig, big.Zero(), src, uint64(multisig.Methods.LockBalance), enc)
+}
+
 // MsigGetVested returns the amount of FIL that vested in a multisig in a certain period.
 // It takes the following params: <multisig address>, <start epoch>, <end epoch>
 func (a *multiSig) MsigGetVested(ctx context.Context, addr address.Address, start types.TipSetKey, end types.TipSetKey) (types.BigInt, error) {
@@ -491,3 +514,27 @@ func serializeRemoveParams(rem address.Address, dec bool) ([]byte, error) {
 
 	return enc, nil
 }
+
+func serializeThresholdParams(newThreshold uint64) ([]byte, error) {
+	enc, actErr := actors.SerializeParams(&multisig2.ChangeNumApprovalsThresholdParams{
+		NewThreshold: newThreshold,
+	})
+	if actErr != nil {
+		return nil, actErr
+	}
+
+	return enc, nil
+}
+
+func serializeLockParams(start abi.ChainEpoch, duration abi.ChainEpoch, amount abi.TokenAmount) ([]byte, error) {
+	enc, actErr := actors.SerializeParams(&multisig2.LockBalanceParams{
+		StartEpoch:     start,
+		UnlockDuration: duration,
+		Amount:         amount,
+	})
+	if actErr != nil {
+		return nil, actErr
+	}
+
+	return enc, nil
+}
@@ -10,7 +10,7 @@ import (
 )
 
 type IMultiSig interface {
-	MsigCreate(ctx context.Context, req uint64, addrs []address.Address, duration abi.ChainEpoch, val types.BigInt, src address.Address, gp types.BigInt) (*types.MessagePrototype, error)
+	MsigCreate(ctx context.Context, req uint64, addrs []address.Address, start abi.ChainEpoch, duration abi.ChainEpoch, val types.BigInt, src address.Address, gp types.BigInt) (*types.MessagePrototype, error)
 	StateMsigInfo(ctx context.Context, addr address.Address, tsk types.TipSetKey) (*types.MsigInfo, error)
 	MsigPropose(ctx context.Context, msig address.Address, to address.Address, amt types.BigInt, src address.Address, method uint64, params []byte) (*types.MessagePrototype, error)
 	MsigAddPropose(ctx context.Context, msig address.Address, src address.Address, newAdd address.Address, inc bool) (*types.MessagePrototype, error)
@@ -27,6 +27,10 @@ type IMultiSig interface {
 	MsigApproveTxnHash(ctx context.Context, msig address.Address, txID uint64, proposer address.Address, to address.Address, amt types.BigInt, src address.Address, method uint64, params []byte) (*types.MessagePrototype, error)
 	MsigCancel(ctx context.Context, msig address.Address, txID uint64, src address.Address) (*types.MessagePrototype, error)
 	MsigRemoveSigner(ctx context.Context, msig address.Address, proposer address.Address, toRemove address.Address, decrease bool) (*types.MessagePrototype, error)
+	// MsigChangeThresholdPropose proposes changing the number of approvals required by the multisig
+	MsigChangeThresholdPropose(ctx context.Context, msig address.Address, src address.Address, newThreshold uint64) (*types.MessagePrototype, error)
+	// MsigLockPropose proposes locking a part of the multisig balance, which unlocks linearly over the duration
+	MsigLockPropose(ctx context.Context, msig address.Address, src address.Address, start abi.ChainEpoch, duration abi.ChainEpoch, amount types.BigInt) (*types.MessagePrototype, error)
 
 	// MsigGetVested returns the amount of FIL that vested in a multisig in a certain period.
 	// It takes the following params: <multisig address>, <start epoch>, <end epoch>
@@ -4,12 +4,16 @@ import (
 	"bytes"
 	"net/http"
 	"os"
+	"sync"
 
 	"github.com/BurntSushi/toml"
 	"github.com/filecoin-project/venus/venus-shared/api"
 )
 
 type Config struct {
+	// lk guards the sections modified at runtime, use View and Update to access them
+	lk sync.RWMutex
+
 	Path        string `toml:"-"`
 	Server      ServerConfig
 	NodeAPI     APIInfo
@@ -19,6 +23,60 @@ type Config struct {
 	AuthAPI     APIInfo
 	DamoclesAPI APIInfo
 	MinerAPI    APIInfo
+	Policy      PolicyConfig
+	Ask         AskConfig
+	Msig        MsigConfig
+}
+
+type PolicyConfig struct {
+	// Addresses configures the spending policy of sender addresses, addresses not listed are not restricted
+	Addresses []AddressPolicy
+}
+
+// AddressPolicy restricts the messages sent from Address through MsgSend,
+// messages exceeding the limits wait for the approval of another operator
+type AddressPolicy struct {
+	Address string
+	// DailyLimit and WeeklyLimit are the max value can be sent in the last 24 hours and 7 days, eg: "100 FIL", empty means no limit
+	DailyLimit  string
+	WeeklyLimit string
+	// AllowedTo lists the destinations allowed, empty means any destination
+	AllowedTo []string
+	// ForbiddenMethods lists the method numbers can not be invoked
+	ForbiddenMethods []uint64
+}
+
+type AskConfig struct {
+	// Templates are named asks which can be applied to many miners
+	Templates []AskTemplate
+}
+
+// AskTemplate is the storage and retrieval ask shared by miners,
+// prices are in FIL, eg: "0.0000001 FIL", sizes are in bytes with units, eg: "32GiB"
+type AskTemplate struct {
+	Name string
+	// Price and VerifiedPrice are per GiB per epoch, storage ask is not managed if Price is empty
+	Price         string
+	VerifiedPrice string
+	MinPieceSize  string
+	// MaxPieceSize is the sector size of miner if empty
+	MaxPieceSize string
+	// Duration is how long the storage ask is valid, eg: 720h, which is the default if empty
+	Duration string
+
+	// RetrievalPrice is per GiB, retrieval ask is not managed if RetrievalPrice is empty
+	RetrievalPrice          string
+	UnsealPrice             string
+	PaymentInterval         string
+	PaymentIntervalIncrease string
+
+	// Miners are assigned to the template, their asks are checked for drift
+	Miners []string
+}
+
+type MsigConfig struct {
+	// Watched lists the multisigs whose pending transactions are shown in the approval inbox
+	Watched []string
 }
 
 type ServerConfig struct {
@@ -41,6 +99,29 @@ func LoadConfig(path string) (*Config, error) {
 }
 
 func (c *Config) Save() error {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	return c.save()
+}
+
+// View calls fn with the config locked for reading, fn must not keep references to the slices of config
+func (c *Config) View(fn func(cfg *Config)) {
+	c.lk.RLock()
+	defer c.lk.RUnlock()
+	fn(c)
+}
+
+// Update calls fn to modify the config and saves it under the same lock, nothing is saved if fn fails
+func (c *Config) Update(fn func(cfg *Config) error) error {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	if err := fn(c); err != nil {
+		return err
+	}
+	return c.save()
+}
+
+func (c *Config) save() error {
 	b := bytes.Buffer{}
 	err := toml.NewEncoder(&b).Encode(c)
 	if err != nil {
@@ -15,6 +15,7 @@ var log = logging.Logger("route")
 func registerRoute(s *service.ServiceImpl, boardPath string) http.Handler {
 	router := gin.Default()
 	router.Use(corsMiddleWare())
+	router.Use(authMiddleWare(s))
 
 	boardPath = strings.TrimRight(boardPath, "/")
 	router.Static("/board", boardPath)
@@ -48,3 +49,22 @@ func corsMiddleWare() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// authMiddleWare verifies the bearer token of request if present, and passes the user of the token to the service as the operator
+func authMiddleWare(s *service.ServiceImpl) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := strings.TrimSpace(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer"))
+		if token == "" {
+			c.Next()
+			return
+		}
+		operator, err := s.VerifyOperator(c, token)
+		if err != nil {
+			log.Warnf("verify token failed: %s", err)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, NewErrResponse(err))
+			return
+		}
+		c.Set(service.OperatorKey, operator)
+		c.Next()
+	}
+}
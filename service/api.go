@@ -10,7 +10,7 @@ import (
 	"github.com/filecoin-project/go-state-types/dline"
 	"github.com/filecoin-project/venus/venus-shared/types"
 	marketTypes "github.com/filecoin-project/venus/venus-shared/types/market"
-	"github.com/ipfs-force-community/venus-tool/dep"
+	"github.com/ipfs-force-community/venus-tool/repo/config"
 	"github.com/ipfs/go-cid"
 )
 
@@ -28,6 +28,19 @@ type IService interface {
 	MsgDecodeParam2Json(ctx context.Context, req *MsgDecodeParamReq) ([]byte, error) // POST:/msg/decodeparam
 	MsgGetMethodName(ctx context.Context, req *MsgGetMethodNameReq) (string, error)  // GET:/msg/getmethodname
 	MsgMarkBad(ctx context.Context, req *MsgID) error                                // POST:/msg/markbad/:ID
+	// MsgParamsSchema returns the json schema and a template of the params of the method
+	MsgParamsSchema(ctx context.Context, req *MsgParamsSchemaReq) (*MsgParamsSchemaResp, error) // GET:/msg/paramsschema
+	// MsgBuild builds an unsigned message with nonce and gas filled, which can be signed offline
+	MsgBuild(ctx context.Context, req *MsgBuildReq) (*MsgBuildResp, error) // POST:/msg/build
+	// MsgSubmitSigned verifies the signature of an offline signed message and checks the spending policy of sender,
+	// then pushes it to the node mpool, as messager only accepts messages signed by itself,
+	// the message is not in messager, but Msg and MsgQuery find it by its cid as id, or list it with IsSigned
+	MsgSubmitSigned(ctx context.Context, req *MsgSubmitSignedReq) (cid.Cid, error) // POST:/msg/submitsigned
+
+	// ScheduleAdd adds a message which will be sent once the trigger is satisfied
+	ScheduleAdd(ctx context.Context, req *ScheduleAddReq) (*ScheduleTask, error)     // POST:/schedule/add
+	ScheduleList(ctx context.Context, req *ScheduleListReq) ([]*ScheduleTask, error) // GET:/schedule/list
+	ScheduleCancel(ctx context.Context, req *ScheduleTaskID) error                   // POST:/schedule/cancel/:ID
 
 	AddrOperate(ctx context.Context, params *AddrsOperateReq) error // PUT:/addr/operate
 	AddrInfo(ctx context.Context, addr Address) (*AddrsResp, error) // GET:/addr/info/:Address
@@ -37,6 +50,16 @@ type IService interface {
 	WalletList(ctx context.Context) ([]address.Address, error)                                                // GET:/wallet/list
 	WalletSignRecordQuery(ctx context.Context, req *WalletSignRecordQueryReq) ([]WalletSignRecordResp, error) // GET:/wallet/signrecord
 
+	// PolicyList returns the spending policies of addresses, which restrict messages sent by MsgSend
+	PolicyList(ctx context.Context) ([]config.AddressPolicy, error) // GET:/policy/list
+	// PolicySet and PolicyRemove require a token identifying the operator, who is recorded in the audit log
+	PolicySet(ctx context.Context, req *config.AddressPolicy) error                                // PUT:/policy/set
+	PolicyRemove(ctx context.Context, req *Address) error                                          // POST:/policy/remove
+	PolicyApprovalList(ctx context.Context, req *PolicyApprovalListReq) ([]*PolicyApproval, error) // GET:/policy/approval/list
+	// PolicyApprove sends the message exceeding spending limits, returns the id of message in messager
+	PolicyApprove(ctx context.Context, req *PolicyApproveReq) (string, error) // POST:/policy/approval/approve
+	PolicyReject(ctx context.Context, req *PolicyApproveReq) error            // POST:/policy/approval/reject
+
 	MinerInfo(ctx context.Context, mAddr Address) (*MinerInfoResp, error)                                                // GET:/miner/info/:Address
 	MinerList(ctx context.Context) ([]address.Address, error)                                                            // GET:/miner/list
 	MinerCreate(ctx context.Context, params *MinerCreateReq) (address.Address, error)                                    // POST:/miner/create
@@ -44,6 +67,11 @@ type IService interface {
 	MinerGetRetrievalAsk(ctx context.Context, mAddr address.Address) (*retrievalmarket.Ask, error)                       // GET:/miner/retrievalask
 	MinerSetStorageAsk(ctx context.Context, p *MinerSetAskReq) error                                                     // PUT:/miner/storageask
 	MinerSetRetrievalAsk(ctx context.Context, p *MinerSetRetrievalAskReq) error                                          // PUT:/miner/retrievalask
+	AskTemplateList(ctx context.Context) ([]config.AskTemplate, error)                                                   // GET:/ask/template/list
+	AskTemplateSet(ctx context.Context, req *config.AskTemplate) error                                                   // PUT:/ask/template/set
+	AskTemplateRemove(ctx context.Context, req *AskTemplateRemoveReq) error                                              // POST:/ask/template/remove
+	AskTemplateApply(ctx context.Context, req *AskTemplateApplyReq) ([]AskDiff, error)                                   // POST:/ask/template/apply
+	AskDrift(ctx context.Context) ([]AskDiff, error)                                                                     // GET:/ask/drift
 	MinerGetDeadlines(ctx context.Context, mAddr address.Address) (*dline.Info, error)                                   // GET:/miner/deadline
 	MinerSetOwner(ctx context.Context, p *MinerSetOwnerReq) error                                                        // PUT:/miner/owner
 	MinerConfirmOwner(ctx context.Context, p *MinerSetOwnerReq) (oldOwner address.Address, err error)                    // PUT:/miner/confirmowner
@@ -56,31 +84,59 @@ type IService interface {
 	MinerWithdrawToBeneficiary(ctx context.Context, req *MinerWithdrawBalanceReq) (abi.TokenAmount, error) // PUT:/miner/withdrawbeneficiary
 	// MinerWithdrawFromMarket withdraw balance from market to miner's owner or worker
 	MinerWithdrawFromMarket(ctx context.Context, req *MinerWithdrawBalanceReq) (abi.TokenAmount, error) // PUT:/miner/withdrawmarket
-	MinerWinCount(ctx context.Context, req *MinerWinCountReq) (MinerWinCountResp, error)                // GET:/miner/wincount
+	// MarketAddBalance adds funds to the market escrow of miner
+	MarketAddBalance(ctx context.Context, req *MarketAddBalanceReq) (abi.TokenAmount, error) // PUT:/market/addbalance
+	// MarketCollateralPlan estimates the provider collateral needed by pending deals and recommends a top-up amount
+	MarketCollateralPlan(ctx context.Context, mAddr Address) (*MarketCollateralPlanResp, error) // GET:/market/collateralplan/:Address
+	MinerWinCount(ctx context.Context, req *MinerWinCountReq) (MinerWinCountResp, error)        // GET:/miner/wincount
+
+	StorageDealList(ctx context.Context, req *StorageDealListReq) (*StorageDealListResp, error)                   // GET:/deal/storage/list
+	StorageDeal(ctx context.Context, proposalCid Cid) (*marketTypes.MinerDeal, error)                             // GET:/deal/storage/info/:Cid
+	StorageDealUpdateState(ctx context.Context, req StorageDealUpdateStateReq) error                              // PUT:/deal/storage/state
+	StorageDealBulkUpdate(ctx context.Context, req *StorageDealBulkUpdateReq) (*StorageDealBulkUpdateResp, error) // POST:/deal/storage/bulkupdate
+	DealReconcile(ctx context.Context, req *DealReconcileReq) (*DealReconcileResp, error)                         // POST:/deal/reconcile
+	DealImport(ctx context.Context, req *DealImportReq) ([]DealImportResult, error)                               // POST:/deal/storage/import
+	DealUnassignedPieces(ctx context.Context, req *DealUnassignedPiecesReq) ([]marketTypes.MinerDeal, error)      // GET:/deal/piece/unassigned
+	DealAssignPiece(ctx context.Context, req *DealAssignPieceReq) error                                           // POST:/deal/piece/assign
+	DealStats(ctx context.Context, req *DealStatsReq) (*DealStatsResp, error)                                     // GET:/deal/stats
+	RetrievalDealList(ctx context.Context, req *RetrievalDealListReq) (*RetrievalDealListResp, error)             // GET:/deal/retrieval
+	RetrievalDealStats(ctx context.Context, req *RetrievalDealListReq) ([]RetrievalDealStats, error)              // GET:/deal/retrieval/stats
 
-	StorageDealList(ctx context.Context, miner Address) ([]marketTypes.MinerDeal, error) // GET:/deal/storage/:Address
-	StorageDeal(ctx context.Context, proposalCid Cid) (*marketTypes.MinerDeal, error)    // GET:/deal/storage/info/:Cid
-	StorageDealUpdateState(ctx context.Context, req StorageDealUpdateStateReq) error     // PUT:/deal/storage/state
-	RetrievalDealList(ctx context.Context) ([]marketTypes.ProviderDealState, error)      // GET:/deal/retrieval
+	SectorExtend(ctx context.Context, req SectorExtendReq) error                  // PUT:/sector/extend
+	SectorGet(ctx context.Context, req SectorGetReq) ([]*SectorResp, error)       // GET:/sector/get
+	SectorList(ctx context.Context, req SectorListReq) ([]*SectorListItem, error) // GET:/sector/list
+	SectorSum(ctx context.Context, miner Address) (uint64, error)                 // GET:/sector/sum
 
-	SectorExtend(ctx context.Context, req SectorExtendReq) error                           // PUT:/sector/extend
-	SectorGet(ctx context.Context, req SectorGetReq) ([]*SectorResp, error)                // GET:/sector/get
-	SectorList(ctx context.Context, req SectorListReq) ([]*types.SectorOnChainInfo, error) // GET:/sector/list
-	SectorSum(ctx context.Context, miner Address) (uint64, error)                          // GET:/sector/sum
+	VerifregClaimList(ctx context.Context, mAddr Address) ([]VerifregClaim, error)                                     // GET:/verifreg/claim/list/:Address
+	VerifregAllocationList(ctx context.Context, req *VerifregAllocationListReq) ([]VerifregAllocation, error)          // GET:/verifreg/allocation/list
+	VerifregExtendClaimTerms(ctx context.Context, req *VerifregExtendClaimTermsReq) (*VerifregClaimsResp, error)       // POST:/verifreg/claim/extend
+	VerifregRemoveExpiredClaims(ctx context.Context, req *VerifregRemoveExpiredClaimsReq) (*VerifregClaimsResp, error) // POST:/verifreg/claim/removeexpired
 
-	MsigCreate(ctx context.Context, req *MultisigCreateReq) (address.Address, error)                  // POST:/msig/create
-	MsigInfo(ctx context.Context, msig address.Address) (*types.MsigInfo, error)                      // GET:/msig/info
-	MsigPropose(ctx context.Context, req *MultisigProposeReq) (*types.ProposeReturn, error)           // POST:/msig/propose
-	MsigListPropose(ctx context.Context, msig address.Address) ([]*types.MsigTransaction, error)      // GET:/msig/proposes
-	MsigAddSigner(ctx context.Context, req *MultisigChangeSignerReq) (*types.ProposeReturn, error)    // POST:/msig/signer/ass
-	MsigRemoveSigner(ctx context.Context, req *MultisigChangeSignerReq) (*types.ProposeReturn, error) // POST:/msig/signer/remove
-	MsigApprove(ctx context.Context, req *MultisigApproveReq) (*types.ApproveReturn, error)           // POST:/msig/approve
-	MsigCancel(ctx context.Context, req *MultisigCancelReq) error                                     // POST:/msig/cancel
-	MsigSwapSigner(ctx context.Context, req *MultisigSwapSignerReq) (*types.ProposeReturn, error)     // POST:/msig/signer/swap
+	MsigCreate(ctx context.Context, req *MultisigCreateReq) (*MultisigCreateResp, error)                    // POST:/msig/create
+	MsigInfo(ctx context.Context, msig address.Address) (*types.MsigInfo, error)                            // GET:/msig/info
+	MsigVesting(ctx context.Context, msig address.Address) (*MsigVestingResp, error)                        // GET:/msig/vesting
+	MsigAvailable(ctx context.Context, msig address.Address) (abi.TokenAmount, error)                       // GET:/msig/available
+	MsigVested(ctx context.Context, req *MsigVestedReq) (abi.TokenAmount, error)                            // GET:/msig/vested
+	MsigPropose(ctx context.Context, req *MultisigProposeReq) (*types.ProposeReturn, error)                 // POST:/msig/propose
+	MsigProposeCall(ctx context.Context, req *MsigProposeCallReq) (*MsigProposeCallResp, error)             // POST:/msig/propose/call
+	MsigListPropose(ctx context.Context, msig address.Address) ([]*MsigTransaction, error)                  // GET:/msig/proposes
+	MsigWatchList(ctx context.Context) ([]address.Address, error)                                           // GET:/msig/watch/list
+	MsigWatch(ctx context.Context, req *MsigWatchReq) error                                                 // PUT:/msig/watch
+	MsigUnwatch(ctx context.Context, req *MsigWatchReq) error                                               // POST:/msig/watch/remove
+	MsigInbox(ctx context.Context, req *MsigInboxReq) ([]*MsigInboxItem, error)                             // GET:/msig/inbox
+	MsigHistory(ctx context.Context, req *MsigHistoryReq) (*MsigHistoryResp, error)                         // GET:/msig/history
+	MsigAddSigner(ctx context.Context, req *MultisigChangeSignerReq) (*types.ProposeReturn, error)          // POST:/msig/signer/ass
+	MsigRemoveSigner(ctx context.Context, req *MultisigChangeSignerReq) (*types.ProposeReturn, error)       // POST:/msig/signer/remove
+	MsigApprove(ctx context.Context, req *MultisigApproveReq) (*types.ApproveReturn, error)                 // POST:/msig/approve
+	MsigCancel(ctx context.Context, req *MultisigCancelReq) error                                           // POST:/msig/cancel
+	MsigSwapSigner(ctx context.Context, req *MultisigSwapSignerReq) (*types.ProposeReturn, error)           // POST:/msig/signer/swap
+	MsigChangeThreshold(ctx context.Context, req *MultisigChangeThresholdReq) (*types.ProposeReturn, error) // POST:/msig/threshold
+	MsigLockBalance(ctx context.Context, req *MultisigLockBalanceReq) (*types.ProposeReturn, error)         // POST:/msig/lock
 
-	ThreadList(ctx context.Context) ([]*dep.ThreadInfo, error)  // GET:/thread/list
-	ThreadStop(ctx context.Context, req *ThreadStopReq) error   // PUT:/thread/stop
-	ThreadStart(ctx context.Context, req *ThreadStartReq) error // PUT:/thread/start
+	ThreadList(ctx context.Context) (*ThreadListResp, error)                                          // GET:/thread/list
+	ThreadStop(ctx context.Context, req *ThreadStopReq) error                                         // PUT:/thread/stop
+	ThreadStart(ctx context.Context, req *ThreadStartReq) error                                       // PUT:/thread/start
+	ThreadBulkControl(ctx context.Context, req *ThreadBulkControlReq) (*ThreadBulkControlResp, error) // POST:/thread/bulk
 
 	Search(ctx context.Context, req SearchReq) (*SearchResp, error)                        // GET:/search/:Key
 	MinedBlockList(ctx context.Context, req MinedBlockListReq) (MinedBlockListResp, error) // GET:/minedblock/list
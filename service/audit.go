@@ -0,0 +1,50 @@
+package service
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+)
+
+const auditFile = "audit.log"
+
+// AuditEntry is a record of an operation changing the state of deals, messages, etc.
+type AuditEntry struct {
+	Time     time.Time
+	Action   string
+	Operator string
+	Detail   interface{}
+}
+
+// auditLogger appends audit entries to a file, one json object per line
+type auditLogger struct {
+	lk   sync.Mutex
+	path string
+}
+
+func newAuditLogger(path string) *auditLogger {
+	return &auditLogger{path: path}
+}
+
+func (a *auditLogger) record(action, operator string, detail interface{}) error {
+	data, err := json.Marshal(AuditEntry{
+		Time:     time.Now(),
+		Action:   action,
+		Operator: operator,
+		Detail:   detail,
+	})
+	if err != nil {
+		return err
+	}
+
+	a.lk.Lock()
+	defer a.lk.Unlock()
+	f, err := os.OpenFile(a.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
@@ -9,21 +9,27 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
 	"github.com/filecoin-project/venus/venus-shared/actors/builtin"
 
 	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/pkg/crypto"
+	_ "github.com/filecoin-project/venus/pkg/crypto/bls"
+	_ "github.com/filecoin-project/venus/pkg/crypto/delegated"
+	_ "github.com/filecoin-project/venus/pkg/crypto/secp"
 	"github.com/filecoin-project/venus/pkg/state"
 	nodeV1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
 	market "github.com/filecoin-project/venus/venus-shared/api/market/v1"
 	"github.com/filecoin-project/venus/venus-shared/api/messager"
 	"github.com/filecoin-project/venus/venus-shared/blockstore"
 	"github.com/filecoin-project/venus/venus-shared/types"
-	marketTypes "github.com/filecoin-project/venus/venus-shared/types/market"
 	msgTypes "github.com/filecoin-project/venus/venus-shared/types/messager"
 	walletTypes "github.com/filecoin-project/venus/venus-shared/types/wallet"
+	vsUtils "github.com/filecoin-project/venus/venus-shared/utils"
 	mkRepo "github.com/ipfs-force-community/droplet/v2/models/repo"
 	minerTypes "github.com/ipfs-force-community/sophon-miner/types"
 	"github.com/ipfs/go-cid"
@@ -33,11 +39,15 @@ import (
 
 	"github.com/ipfs-force-community/venus-tool/dep"
 	"github.com/ipfs-force-community/venus-tool/pkg/multisig"
+	"github.com/ipfs-force-community/venus-tool/repo/config"
 	"github.com/ipfs-force-community/venus-tool/utils"
 )
 
 var log = logging.Logger("service")
 
+// msgPageSize is the batch size to fetch messages from messager when filtering them
+const msgPageSize = 500
+
 var (
 	ErrEmptyMiner    = fmt.Errorf("empty miner: please check the api config of miner")
 	ErrEmptyAuth     = fmt.Errorf("empty auth: please check the api config of auth")
@@ -50,17 +60,39 @@ type ServiceImpl struct {
 	Multisig multisig.IMultiSig
 	Market   market.IMarket
 	Wallet   dep.IWallet
+	Config   *config.Config
 
 	// may be nil, detect before use
 	Auth     dep.IAuth
 	Miner    dep.Miner
 	Damocles *dep.Damocles
+
+	scheduler  *scheduler
+	policy     *policyKeeper
+	audit      *auditLogger
+	msigInbox  *msigInbox
+	signedMsgs *signedMsgIndex
+	threads    threadCache
 }
 
 var _ IService = &ServiceImpl{}
 
-func (s *ServiceImpl) PushMessageAndWait(ctx context.Context, msg *types.Message, spec *msgTypes.SendSpec) (*msgTypes.Message, error) {
+// pushMessage pushes the message to messager, all outgoing messages pass through it to check the spending policy of sender
+func (s *ServiceImpl) pushMessage(ctx context.Context, msg *types.Message, spec *msgTypes.SendSpec) (string, error) {
+	release, err := s.checkPolicy(ctx, msg, spec, true)
+	if err != nil {
+		return "", err
+	}
 	id, err := s.Messager.PushMessage(ctx, msg, spec)
+	if err != nil {
+		release()
+		return "", err
+	}
+	return id, nil
+}
+
+func (s *ServiceImpl) PushMessageAndWait(ctx context.Context, msg *types.Message, spec *msgTypes.SendSpec) (*msgTypes.Message, error) {
+	id, err := s.pushMessage(ctx, msg, spec)
 	if err != nil {
 		return nil, err
 	}
@@ -103,56 +135,83 @@ func (s *ServiceImpl) GetDefaultWallet(ctx context.Context) (address.Address, er
 	return wallets[0], nil
 }
 
-func (s *ServiceImpl) MsgSend(ctx context.Context, req *MsgSendReq) (string, error) {
-	dec := func(req EncodedParams, to address.Address, method abi.MethodNum) ([]byte, error) {
-		switch req.EncType {
-		case EncJson:
-			act, err := s.Node.StateGetActor(ctx, to, types.EmptyTSK)
-			if err != nil {
-				return nil, err
-			}
-			return req.DecodeJSON(act.Code, method)
-		case EncHex:
-			return req.DecodeHex()
-		case EncBase64:
-			de, err := base64.StdEncoding.DecodeString(req.Data)
-			if err != nil {
-				return nil, err
-			}
-			return de, nil
-		default:
-			return nil, fmt.Errorf("unknown encoding type: %s", req.EncType)
+func (s *ServiceImpl) decodeMsgParams(ctx context.Context, req EncodedParams, to address.Address, method abi.MethodNum) ([]byte, error) {
+	switch req.EncType {
+	case EncJson:
+		act, err := s.Node.StateGetActor(ctx, to, types.EmptyTSK)
+		if err != nil {
+			return nil, err
+		}
+		return req.DecodeJSON(act.Code, method)
+	case EncHex:
+		return req.DecodeHex()
+	case EncBase64:
+		de, err := base64.StdEncoding.DecodeString(req.Data)
+		if err != nil {
+			return nil, err
 		}
+		return de, nil
+	default:
+		return nil, fmt.Errorf("unknown encoding type: %s", req.EncType)
 	}
+}
 
+func (s *ServiceImpl) MsgSend(ctx context.Context, req *MsgSendReq) (string, error) {
+	if err := s.resolveMethodName(ctx, req); err != nil {
+		return "", err
+	}
+	if req.Value.Nil() {
+		req.Value = big.Zero()
+	}
 	log.Infof("msg send: from(%s), to(%s), value(%s), method(%d), params(%s)", req.From, req.To, req.Value, req.Method, req.Params)
 
+	msg, err := s.buildMsgSendReq(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return s.pushMessage(ctx, msg, &req.SendSpec)
+}
+
+// pushMsgSendReq pushes the message to messager without checking the policy, the message is approved already
+func (s *ServiceImpl) pushMsgSendReq(ctx context.Context, req *MsgSendReq) (string, error) {
+	msg, err := s.buildMsgSendReq(ctx, req)
+	if err != nil {
+		return "", err
+	}
+	return s.Messager.PushMessage(ctx, msg, &req.SendSpec)
+}
+
+func (s *ServiceImpl) buildMsgSendReq(ctx context.Context, req *MsgSendReq) (*types.Message, error) {
 	var decParams []byte
 	if req.Params != nil {
 		var err error
-		decParams, err = dec(*req.Params, req.To, req.Method)
+		decParams, err = s.decodeMsgParams(ctx, *req.Params, req.To, req.Method)
 		if err != nil {
-			return "", fmt.Errorf("decode params failed: %s", err)
+			return nil, fmt.Errorf("decode params failed: %s", err)
 		}
 	}
 
-	msg := &types.Message{
+	return &types.Message{
 		From:  req.From,
 		To:    req.To,
 		Value: req.Value,
 
 		Method: req.Method,
 		Params: decParams,
-	}
-
-	return s.Messager.PushMessage(ctx, msg, &req.SendSpec)
+	}, nil
 }
 
 func (s *ServiceImpl) MsgQuery(ctx context.Context, params *MsgQueryReq) ([]*MsgResp, error) {
 	var msgs []*msgTypes.Message
 	var err error
-	if params.ID != "" {
-		msg, err := s.Messager.GetMessageByUid(ctx, params.ID)
+	if params.Cid != nil {
+		msg, err := s.getMsgByCid(ctx, *params.Cid)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
+	} else if params.ID != "" {
+		msg, err := s.getMsgByID(ctx, params.ID)
 		if err != nil {
 			return nil, err
 		}
@@ -162,6 +221,9 @@ func (s *ServiceImpl) MsgQuery(ctx context.Context, params *MsgQueryReq) ([]*Msg
 		if err != nil {
 			log.Warnf("get default wallet failed: %s", err)
 		}
+		if len(params.From) > 1 {
+			return nil, fmt.Errorf("only one sender can be indicated to query by nonce")
+		}
 		if len(params.From) != 0 {
 			from = params.From[0]
 		}
@@ -170,7 +232,14 @@ func (s *ServiceImpl) MsgQuery(ctx context.Context, params *MsgQueryReq) ([]*Msg
 		}
 		msg, err := s.Messager.GetMessageByFromAndNonce(ctx, from, params.Nonce)
 		if err != nil {
-			return nil, err
+			signed, ok, serr := s.getSignedMsgByNonce(ctx, from, params.Nonce)
+			if serr != nil {
+				return nil, serr
+			}
+			if !ok {
+				return nil, err
+			}
+			msg = signed
 		}
 		msgs = append(msgs, msg)
 	} else if params.IsBlocked {
@@ -194,6 +263,18 @@ func (s *ServiceImpl) MsgQuery(ctx context.Context, params *MsgQueryReq) ([]*Msg
 		if err != nil {
 			return nil, err
 		}
+	} else if params.IsSigned {
+		msgs, err = s.listSignedMsgs(ctx, params.From)
+		if err != nil {
+			return nil, err
+		}
+	} else if params.OnChain {
+		msgs, err = s.listChainMsgs(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+	} else if params.postFiltered() {
+		return s.listFilteredMsgs(ctx, params)
 	} else {
 		msgs, err = s.Messager.ListMessage(ctx, &params.MsgQueryParams)
 		if err != nil {
@@ -203,61 +284,179 @@ func (s *ServiceImpl) MsgQuery(ctx context.Context, params *MsgQueryReq) ([]*Msg
 
 	var ret []*MsgResp
 	for idx := range msgs {
-		msg := msgs[idx]
-		resp := &MsgResp{
-			Message: *msg,
+		if resp := s.matchMsg(ctx, params, msgs[idx]); resp != nil {
+			ret = append(ret, resp)
 		}
+	}
 
-		act, err := s.Node.StateGetActor(ctx, msg.To, types.EmptyTSK)
-		if err != nil {
-			log.Warnf("get actor failed: %s", err)
-		}
-		methodMeta, err := utils.GetMethodMeta(act.Code, msg.Method)
+	return ret, nil
+}
+
+// matchMsg returns the message decoded if it matches the filters of req, nil otherwise,
+// the message is only decoded after passing the other filters
+func (s *ServiceImpl) matchMsg(ctx context.Context, req *MsgQueryReq, msg *msgTypes.Message) *MsgResp {
+	if !req.match(msg) {
+		return nil
+	}
+	resp := s.msgToResp(ctx, msg)
+	if !req.matchMethod(resp) {
+		return nil
+	}
+	return resp
+}
+
+// listFilteredMsgs fetches messages from messager page by page and filters them,
+// Offset and Limit of req are applied to the messages matched
+func (s *ServiceImpl) listFilteredMsgs(ctx context.Context, req *MsgQueryReq) ([]*MsgResp, error) {
+	params := req.MsgQueryParams
+	params.Offset, params.Limit = 0, msgPageSize
+
+	skip := req.Offset
+	var ret []*MsgResp
+	for {
+		msgs, err := s.Messager.ListMessage(ctx, &params)
 		if err != nil {
-			log.Warnf("get method meta failed: %s", err)
+			return nil, err
 		}
-		resp.MethodName = methodMeta.Name
-
-		// decode params
-		if len(msg.Params) != 0 {
-			paramsRV := methodMeta.Params
-			if paramsRV.Kind() == reflect.Ptr {
-				paramsRV = paramsRV.Elem()
+		for _, msg := range msgs {
+			resp := s.matchMsg(ctx, req, msg)
+			if resp == nil {
+				continue
 			}
-			params := reflect.New(paramsRV).Interface().(cbg.CBORUnmarshaler)
-			if err := params.UnmarshalCBOR(bytes.NewReader(msg.Params)); err != nil {
-				log.Warnf("unmarshal params(%s) failed: %s", msg.Params, err)
+			if skip > 0 {
+				skip--
+				continue
 			}
-			p, err := json.MarshalIndent(params, "", "  ")
-			if err != nil {
-				log.Warnf("marshal params(%s) failed: %s", msg.Params, err)
+			ret = append(ret, resp)
+			if req.Limit > 0 && uint(len(ret)) >= req.Limit {
+				return ret, nil
 			}
-			resp.ParamsInJson = p
 		}
+		if uint(len(msgs)) < params.Limit {
+			return ret, nil
+		}
+		params.Offset += params.Limit
+	}
+}
 
-		ret = append(ret, resp)
+// getMsgByCid looks up the message by signed or unsigned cid from messager and the messages submitted signed,
+// and falls back to the chain for messages not sent through venus-tool
+func (s *ServiceImpl) getMsgByCid(ctx context.Context, c cid.Cid) (*msgTypes.Message, error) {
+	if msg, err := s.Messager.GetMessageBySignedCid(ctx, c); err == nil {
+		return msg, nil
+	}
+	if msg, err := s.Messager.GetMessageByUnsignedCid(ctx, c); err == nil {
+		return msg, nil
+	}
+	if m, ok := s.signedMsgs.get(c); ok {
+		return s.signedMsgToMsg(ctx, m)
 	}
 
-	return ret, nil
+	chainMsg, err := s.Node.ChainGetMessage(ctx, c)
+	if err != nil {
+		return nil, fmt.Errorf("message(%s) not found in messager nor on chain: %s", c, err)
+	}
+	return s.chainMsgToMsg(ctx, c, chainMsg)
 }
 
-func (s *ServiceImpl) Msg(ctx context.Context, id MsgID) (*MsgResp, error) {
-	msg, err := s.Messager.GetMessageByUid(ctx, id.ID)
+// chainMsgToMsg fills the execution result of the chain message by StateSearchMsg,
+// the state of message is FillMsg if it is not executed yet
+func (s *ServiceImpl) chainMsgToMsg(ctx context.Context, c cid.Cid, chainMsg *types.Message) (*msgTypes.Message, error) {
+	unsignedCid := chainMsg.Cid()
+	msg := &msgTypes.Message{
+		UnsignedCid: &unsignedCid,
+		Message:     *chainMsg,
+		State:       msgTypes.FillMsg,
+	}
+	if !c.Equals(unsignedCid) {
+		msg.SignedCid = &c
+	}
+
+	lookup, err := s.Node.StateSearchMsg(ctx, types.EmptyTSK, c, constants.LookbackNoLimit, true)
 	if err != nil {
-		return nil, fmt.Errorf("fail to get message by uid(%s): %s", id.ID, err)
+		return nil, fmt.Errorf("search message(%s) failed: %s", c, err)
+	}
+	if lookup != nil {
+		msg.State = msgTypes.OnChainMsg
+		msg.Height = int64(lookup.Height)
+		msg.Receipt = &lookup.Receipt
+		msg.TipSetKey = lookup.TipSet
+	}
+	return msg, nil
+}
+
+// listChainMsgs lists messages executed on chain in the epoch range, which matches To or any of From
+func (s *ServiceImpl) listChainMsgs(ctx context.Context, params *MsgQueryReq) ([]*msgTypes.Message, error) {
+	var matches []*types.MessageMatch
+	for _, from := range params.From {
+		matches = append(matches, &types.MessageMatch{To: params.To, From: from})
+	}
+	if len(matches) == 0 {
+		if params.To == address.Undef {
+			return nil, fmt.Errorf("to or from is required to search messages on chain")
+		}
+		matches = append(matches, &types.MessageMatch{To: params.To})
+	}
+	if params.FromEpoch <= 0 {
+		return nil, fmt.Errorf("from epoch is required to search messages on chain")
+	}
+
+	tsk := types.EmptyTSK
+	if params.ToEpoch > 0 {
+		ts, err := s.Node.ChainGetTipSetByHeight(ctx, params.ToEpoch, types.EmptyTSK)
+		if err != nil {
+			return nil, fmt.Errorf("get tipset at %d failed: %s", params.ToEpoch, err)
+		}
+		tsk = ts.Key()
+	}
+
+	var cids []cid.Cid
+	seen := make(map[cid.Cid]struct{})
+	for _, match := range matches {
+		matched, err := s.Node.StateListMessages(ctx, match, tsk, params.FromEpoch)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range matched {
+			if _, ok := seen[c]; !ok {
+				seen[c] = struct{}{}
+				cids = append(cids, c)
+			}
+		}
+	}
+
+	msgs := make([]*msgTypes.Message, 0, len(cids))
+	for _, c := range cids {
+		chainMsg, err := s.Node.ChainGetMessage(ctx, c)
+		if err != nil {
+			return nil, fmt.Errorf("get message(%s) failed: %s", c, err)
+		}
+		msg, err := s.chainMsgToMsg(ctx, c, chainMsg)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, msg)
 	}
-	ret := &MsgResp{
+	return msgs, nil
+}
+
+// msgToResp decodes the method name and params of the message
+func (s *ServiceImpl) msgToResp(ctx context.Context, msg *msgTypes.Message) *MsgResp {
+	resp := &MsgResp{
 		Message: *msg,
 	}
+
 	act, err := s.Node.StateGetActor(ctx, msg.To, types.EmptyTSK)
 	if err != nil {
 		log.Warnf("get actor failed: %s", err)
+		return resp
 	}
 	methodMeta, err := utils.GetMethodMeta(act.Code, msg.Method)
 	if err != nil {
 		log.Warnf("get method meta failed: %s", err)
+		return resp
 	}
-	ret.MethodName = methodMeta.Name
+	resp.MethodName = methodMeta.Name
 
 	// decode params
 	if len(msg.Params) != 0 {
@@ -273,10 +472,18 @@ func (s *ServiceImpl) Msg(ctx context.Context, id MsgID) (*MsgResp, error) {
 		if err != nil {
 			log.Warnf("marshal params(%s) failed: %s", msg.Params, err)
 		}
-		ret.ParamsInJson = p
+		resp.ParamsInJson = p
 	}
 
-	return ret, err
+	return resp
+}
+
+func (s *ServiceImpl) Msg(ctx context.Context, id MsgID) (*MsgResp, error) {
+	msg, err := s.getMsgByID(ctx, id.ID)
+	if err != nil {
+		return nil, fmt.Errorf("fail to get message by uid(%s): %s", id.ID, err)
+	}
+	return s.msgToResp(ctx, msg), nil
 }
 
 func (s *ServiceImpl) MsgReplace(ctx context.Context, params *MsgReplaceReq) (cid.Cid, error) {
@@ -308,13 +515,16 @@ func (s *ServiceImpl) MsgDecodeParam2Json(ctx context.Context, req *MsgDecodePar
 	if err != nil {
 		return nil, err
 	}
+	return decodeParams(methodMeta.Params, req.Params)
+}
 
-	paramsRV := methodMeta.Params
-	if paramsRV.Kind() == reflect.Ptr {
-		paramsRV = paramsRV.Elem()
+// decodeParams unmarshals the cbor params to the type of method params and marshals it to json
+func decodeParams(paramsType reflect.Type, raw []byte) ([]byte, error) {
+	if paramsType.Kind() == reflect.Ptr {
+		paramsType = paramsType.Elem()
 	}
-	params := reflect.New(paramsRV).Interface().(cbg.CBORUnmarshaler)
-	if err := params.UnmarshalCBOR(bytes.NewReader(req.Params)); err != nil {
+	params := reflect.New(paramsType).Interface().(cbg.CBORUnmarshaler)
+	if err := params.UnmarshalCBOR(bytes.NewReader(raw)); err != nil {
 		return nil, err
 	}
 	return json.Marshal(params)
@@ -334,10 +544,166 @@ func (s *ServiceImpl) MsgGetMethodName(ctx context.Context, req *MsgGetMethodNam
 	return methodMeta.Name, nil
 }
 
+func (s *ServiceImpl) MsgParamsSchema(ctx context.Context, req *MsgParamsSchemaReq) (*MsgParamsSchemaResp, error) {
+	act, err := s.Node.StateGetActor(ctx, req.To, types.EmptyTSK)
+	if err != nil {
+		return nil, err
+	}
+
+	method := req.Method
+	var methodMeta vsUtils.MethodMeta
+	if req.MethodName != "" {
+		method, methodMeta, err = utils.GetMethodByName(act.Code, req.MethodName)
+	} else {
+		methodMeta, err = utils.GetMethodMeta(act.Code, req.Method)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &MsgParamsSchemaResp{
+		Method:     method,
+		MethodName: methodMeta.Name,
+	}
+	if methodMeta.Params == nil || methodMeta.Params == reflect.TypeOf(&abi.EmptyValue{}) {
+		return ret, nil
+	}
+	ret.Schema = utils.GenJSONSchema(methodMeta.Params)
+	ret.Template, err = json.Marshal(ret.Schema.Template())
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// resolveMethodName fills the method number of req by the method name
+func (s *ServiceImpl) resolveMethodName(ctx context.Context, req *MsgSendReq) error {
+	if req.MethodName == "" {
+		return nil
+	}
+	act, err := s.Node.StateGetActor(ctx, req.To, types.EmptyTSK)
+	if err != nil {
+		return fmt.Errorf("get actor of %s failed: %s", req.To, err)
+	}
+	req.Method, _, err = utils.GetMethodByName(act.Code, req.MethodName)
+	return err
+}
+
 func (s *ServiceImpl) MsgMarkBad(ctx context.Context, req *MsgID) error {
 	return s.Messager.MarkBadMessage(ctx, req.ID)
 }
 
+func (s *ServiceImpl) MsgBuild(ctx context.Context, req *MsgBuildReq) (*MsgBuildResp, error) {
+	if err := s.resolveMethodName(ctx, req); err != nil {
+		return nil, err
+	}
+	var decParams []byte
+	if req.Params != nil {
+		var err error
+		decParams, err = s.decodeMsgParams(ctx, *req.Params, req.To, req.Method)
+		if err != nil {
+			return nil, fmt.Errorf("decode params failed: %s", err)
+		}
+	}
+
+	// the offline signer needs to know which key to use, so always build with the key address
+	from, err := s.Node.StateAccountKey(ctx, req.From, types.EmptyTSK)
+	if err != nil {
+		return nil, fmt.Errorf("get account key of from(%s) failed: %s", req.From, err)
+	}
+
+	has, err := s.Messager.HasAddress(ctx, from)
+	if err != nil {
+		log.Warnf("check address(%s) in messager failed: %s", from, err)
+	} else if has {
+		log.Warnf("address(%s) is managed by messager, the nonce of built message may conflict with it", from)
+	}
+
+	nonce, err := s.Node.MpoolGetNonce(ctx, from)
+	if err != nil {
+		return nil, fmt.Errorf("get nonce of from(%s) failed: %s", from, err)
+	}
+
+	msg := &types.Message{
+		From:   from,
+		To:     req.To,
+		Value:  req.Value,
+		Nonce:  nonce,
+		Method: req.Method,
+		Params: decParams,
+	}
+
+	msg, err = s.Node.GasEstimateMessageGas(ctx, msg, &types.MessageSendSpec{
+		MaxFee:            req.MaxFee,
+		GasOverEstimation: req.GasOverEstimation,
+		GasOverPremium:    req.GasOverPremium,
+	}, types.EmptyTSK)
+	if err != nil {
+		return nil, fmt.Errorf("estimate gas failed: %s", err)
+	}
+
+	raw, err := msg.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("serialize message failed: %s", err)
+	}
+
+	log.Infof("msg build: from(%s), to(%s), nonce(%d), method(%d), cid(%s)", msg.From, msg.To, msg.Nonce, msg.Method, msg.Cid())
+
+	return &MsgBuildResp{
+		Message: *msg,
+		Cid:     msg.Cid(),
+		Cbor:    raw,
+		Hex:     hex.EncodeToString(raw),
+	}, nil
+}
+
+func (s *ServiceImpl) MsgSubmitSigned(ctx context.Context, req *MsgSubmitSignedReq) (cid.Cid, error) {
+	smsg := req.SignedMessage
+	if req.Hex != "" {
+		if smsg != nil {
+			return cid.Undef, fmt.Errorf("only one of signed message and hex should be set")
+		}
+		raw, err := hex.DecodeString(req.Hex)
+		if err != nil {
+			return cid.Undef, fmt.Errorf("decode hex failed: %s", err)
+		}
+		smsg = &types.SignedMessage{}
+		if err := smsg.UnmarshalCBOR(bytes.NewReader(raw)); err != nil {
+			return cid.Undef, fmt.Errorf("unmarshal signed message failed: %s", err)
+		}
+	}
+	if smsg == nil {
+		return cid.Undef, fmt.Errorf("param error: signed message is empty")
+	}
+
+	from, err := s.Node.StateAccountKey(ctx, smsg.Message.From, types.EmptyTSK)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("get account key of from(%s) failed: %s", smsg.Message.From, err)
+	}
+
+	if err := crypto.Verify(&smsg.Signature, from, smsg.Message.Cid().Bytes()); err != nil {
+		return cid.Undef, fmt.Errorf("verify signature of message(%s) against from(%s) failed: %s", smsg.Message.Cid(), from, err)
+	}
+
+	// messager only accepts messages signed by itself, so the signed message is pushed to the node mpool directly,
+	// and recorded in a local index, where Msg and MsgQuery look it up by its cid as id
+	release, err := s.checkPolicy(ctx, &smsg.Message, nil, false)
+	if err != nil {
+		return cid.Undef, err
+	}
+	c, err := s.Node.MpoolPush(ctx, smsg)
+	if err != nil {
+		release()
+		return cid.Undef, fmt.Errorf("push signed message failed: %s", err)
+	}
+	log.Infof("push signed message(%s) success, from(%s), nonce(%d)", c, smsg.Message.From, smsg.Message.Nonce)
+	if err := s.signedMsgs.add(c, &smsg.Message, time.Now()); err != nil {
+		log.Errorf("record signed message(%s) failed: %s", c, err)
+	}
+
+	return c, nil
+}
+
 func (s *ServiceImpl) AddrOperate(ctx context.Context, params *AddrsOperateReq) error {
 	has, err := s.Messager.HasAddress(ctx, params.Address)
 	if err != nil {
@@ -447,52 +813,6 @@ func (s *ServiceImpl) WalletList(ctx context.Context) ([]address.Address, error)
 	return s.Wallet.WalletList(ctx)
 }
 
-func (s *ServiceImpl) StorageDealList(ctx context.Context, miner Address) ([]marketTypes.MinerDeal, error) {
-	if miner.Address != address.Undef {
-		deals, err := s.Market.MarketListIncompleteDeals(ctx, &marketTypes.StorageDealQueryParams{Miner: miner.Address,
-			Page: marketTypes.Page{
-				Offset: 0, Limit: 100,
-			},
-		})
-		if err != nil {
-			return nil, err
-		}
-		return deals, nil
-	}
-
-	ret := make([]marketTypes.MinerDeal, 0)
-	miners, err := s.listMiner(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, m := range miners {
-
-		deals, err := s.Market.MarketListIncompleteDeals(ctx, &marketTypes.StorageDealQueryParams{Miner: m})
-		if err != nil {
-			return nil, err
-		}
-		ret = append(ret, deals...)
-	}
-	return ret, nil
-}
-
-func (s *ServiceImpl) StorageDeal(ctx context.Context, proposalCid Cid) (*marketTypes.MinerDeal, error) {
-	id, err := cid.Parse(proposalCid.Cid)
-	if err != nil {
-		return nil, fmt.Errorf("parse cid failed: %w", err)
-	}
-	return s.Market.MarketGetDeal(ctx, id)
-}
-
-func (s *ServiceImpl) StorageDealUpdateState(ctx context.Context, req StorageDealUpdateStateReq) error {
-	return s.Market.UpdateStorageDealStatus(ctx, req.ProposalCid, req.State, req.PieceStatus)
-}
-
-func (s *ServiceImpl) RetrievalDealList(ctx context.Context) ([]marketTypes.ProviderDealState, error) {
-	return s.Market.MarketListRetrievalDeals(ctx, &marketTypes.RetrievalDealQueryParams{})
-}
-
 func (s *ServiceImpl) WalletSignRecordQuery(ctx context.Context, req *WalletSignRecordQueryReq) ([]WalletSignRecordResp, error) {
 	records, err := s.Wallet.ListSignedRecord(ctx, (*types.QuerySignRecordParams)(req))
 	if err != nil {
@@ -520,6 +840,7 @@ func (s *ServiceImpl) Search(ctx context.Context, req SearchReq) (*SearchResp, e
 	}
 
 	dataType := Unknown
+	var pieces *PieceSearchResp
 
 	// judge key type
 	if addr, err := address.NewFromString(key); err == nil {
@@ -546,9 +867,18 @@ func (s *ServiceImpl) Search(ctx context.Context, req SearchReq) (*SearchResp, e
 			return nil, fmt.Errorf("address(%s) is not a miner or wallet address", addr)
 		}
 	} else {
-		if mycid, err := cid.Decode(key); err == nil {
+		// try messager first, uid of message may be a cid too
+		has, err := s.Messager.HasMessageByUid(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("search message by uid(%s) failed: %s", key, err)
+		}
+		if has {
+			dataType = Message
+		}
+
+		if mycid, err := cid.Decode(key); err == nil && dataType == Unknown {
 			// key is cid
-			// cid can be proposal cid or messager id
+			// cid can be proposal cid, piece cid or payload cid
 			// try market
 			_, err := s.Market.MarketGetDeal(ctx, mycid)
 			if err == nil {
@@ -557,15 +887,16 @@ func (s *ServiceImpl) Search(ctx context.Context, req SearchReq) (*SearchResp, e
 			} else if !strings.Contains(err.Error(), mkRepo.ErrNotFound.Error()) {
 				return nil, fmt.Errorf("check deal by cid(%s) failed: %s", key, err)
 			}
-		}
 
-		// try messager
-		has, err := s.Messager.HasMessageByUid(ctx, key)
-		if err != nil {
-			return nil, fmt.Errorf("search message by uid(%s) failed: %s", key, err)
-		}
-		if has {
-			dataType = Message
+			// try piece cid and payload cid at last, failures are taken as not found
+			if dataType == Unknown {
+				pieces, err = s.searchPiece(ctx, mycid)
+				if err != nil {
+					log.Debugf("search piece by cid(%s) failed: %s", key, err)
+				} else if pieces != nil {
+					dataType = Piece
+				}
+			}
 		}
 	}
 
@@ -610,6 +941,12 @@ func (s *ServiceImpl) Search(ctx context.Context, req SearchReq) (*SearchResp, e
 			return nil, err
 		}
 		ret.Data = json.RawMessage(b)
+	case Piece:
+		b, err := json.Marshal(pieces)
+		if err != nil {
+			return nil, err
+		}
+		ret.Data = json.RawMessage(b)
 	case Message:
 		msg, err := s.Messager.GetMessageByUid(ctx, key)
 		if err != nil {
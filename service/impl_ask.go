@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-fil-markets/retrievalmarket"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/venus-shared/types"
+
+	"github.com/ipfs-force-community/venus-tool/repo/config"
+)
+
+// askTemplate is the parsed config.AskTemplate, storage or retrieval is nil if not managed by the template
+type askTemplate struct {
+	name      string
+	storage   *MinerSetAskReq
+	retrieval *retrievalmarket.Ask
+}
+
+func parseAskTemplate(t config.AskTemplate) (*askTemplate, error) {
+	if t.Name == "" {
+		return nil, fmt.Errorf("name of ask template is required")
+	}
+	parseFIL := func(s string) (abi.TokenAmount, error) {
+		v, err := types.ParseFIL(s)
+		if err != nil {
+			return big.Zero(), err
+		}
+		return abi.TokenAmount(v), nil
+	}
+	parseSize := func(s string, def int64) (int64, error) {
+		if s == "" {
+			return def, nil
+		}
+		return units.RAMInBytes(s)
+	}
+
+	ret := &askTemplate{name: t.Name}
+	if t.Price != "" {
+		price, err := parseFIL(t.Price)
+		if err != nil {
+			return nil, fmt.Errorf("parse price failed: %s", err)
+		}
+		verifiedPrice := price
+		if t.VerifiedPrice != "" {
+			if verifiedPrice, err = parseFIL(t.VerifiedPrice); err != nil {
+				return nil, fmt.Errorf("parse verified price failed: %s", err)
+			}
+		}
+		min, err := parseSize(t.MinPieceSize, 256)
+		if err != nil {
+			return nil, fmt.Errorf("parse min piece size failed: %s", err)
+		}
+		if min < 256 {
+			return nil, fmt.Errorf("min piece size must be at least 256 bytes")
+		}
+		max, err := parseSize(t.MaxPieceSize, 0)
+		if err != nil {
+			return nil, fmt.Errorf("parse max piece size failed: %s", err)
+		}
+		duration := 720 * time.Hour
+		if t.Duration != "" {
+			if duration, err = time.ParseDuration(t.Duration); err != nil {
+				return nil, fmt.Errorf("parse duration failed: %s", err)
+			}
+		}
+		if duration < time.Duration(constants.MainNetBlockDelaySecs)*time.Second {
+			return nil, fmt.Errorf("duration must be at least one epoch")
+		}
+		ret.storage = &MinerSetAskReq{
+			Price:         price,
+			VerifiedPrice: verifiedPrice,
+			Duration:      abi.ChainEpoch(duration.Seconds() / float64(constants.MainNetBlockDelaySecs)),
+			MinPieceSize:  abi.PaddedPieceSize(min),
+			MaxPieceSize:  abi.PaddedPieceSize(max),
+		}
+	}
+
+	if t.RetrievalPrice != "" {
+		price, err := parseFIL(t.RetrievalPrice)
+		if err != nil {
+			return nil, fmt.Errorf("parse retrieval price failed: %s", err)
+		}
+		unsealPrice := big.Zero()
+		if t.UnsealPrice != "" {
+			if unsealPrice, err = parseFIL(t.UnsealPrice); err != nil {
+				return nil, fmt.Errorf("parse unseal price failed: %s", err)
+			}
+		}
+		interval, err := parseSize(t.PaymentInterval, 1<<20)
+		if err != nil {
+			return nil, fmt.Errorf("parse payment interval failed: %s", err)
+		}
+		increase, err := parseSize(t.PaymentIntervalIncrease, 1<<20)
+		if err != nil {
+			return nil, fmt.Errorf("parse payment interval increase failed: %s", err)
+		}
+		ret.retrieval = &retrievalmarket.Ask{
+			PricePerByte:            big.Div(price, big.NewInt(1<<30)),
+			UnsealPrice:             unsealPrice,
+			PaymentInterval:         uint64(interval),
+			PaymentIntervalIncrease: uint64(increase),
+		}
+	}
+
+	if ret.storage == nil && ret.retrieval == nil {
+		return nil, fmt.Errorf("neither storage price nor retrieval price is set")
+	}
+	return ret, nil
+}
+
+func findAskTemplate(templates []config.AskTemplate, name string) (int, error) {
+	for i, t := range templates {
+		if t.Name == name {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("ask template %s not found", name)
+}
+
+// askTemplates returns a copy of the ask templates in config
+func (s *ServiceImpl) askTemplates() []config.AskTemplate {
+	var ret []config.AskTemplate
+	s.Config.View(func(cfg *config.Config) {
+		ret = make([]config.AskTemplate, 0, len(cfg.Ask.Templates))
+		for _, t := range cfg.Ask.Templates {
+			t.Miners = append([]string{}, t.Miners...)
+			ret = append(ret, t)
+		}
+	})
+	return ret
+}
+
+func (s *ServiceImpl) AskTemplateList(ctx context.Context) ([]config.AskTemplate, error) {
+	return s.askTemplates(), nil
+}
+
+func (s *ServiceImpl) AskTemplateSet(ctx context.Context, req *config.AskTemplate) error {
+	if _, err := parseAskTemplate(*req); err != nil {
+		return err
+	}
+	for _, m := range req.Miners {
+		if _, err := address.NewFromString(m); err != nil {
+			return fmt.Errorf("invalid miner %s: %s", m, err)
+		}
+	}
+
+	err := s.Config.Update(func(cfg *config.Config) error {
+		if idx, err := findAskTemplate(cfg.Ask.Templates, req.Name); err == nil {
+			cfg.Ask.Templates[idx] = *req
+		} else {
+			cfg.Ask.Templates = append(cfg.Ask.Templates, *req)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("save config failed: %s", err)
+	}
+	log.Infof("set ask template %s: %+v", req.Name, *req)
+	return nil
+}
+
+func (s *ServiceImpl) AskTemplateRemove(ctx context.Context, req *AskTemplateRemoveReq) error {
+	var errNotFound error
+	err := s.Config.Update(func(cfg *config.Config) error {
+		idx, err := findAskTemplate(cfg.Ask.Templates, req.Name)
+		if err != nil {
+			errNotFound = err
+			return err
+		}
+		templates := cfg.Ask.Templates
+		cfg.Ask.Templates = append(templates[:idx:idx], templates[idx+1:]...)
+		return nil
+	})
+	if err != nil {
+		if err == errNotFound {
+			return err
+		}
+		return fmt.Errorf("save config failed: %s", err)
+	}
+	log.Infof("remove ask template %s", req.Name)
+	return nil
+}
+
+// diffAsk compares the current asks of the miner with the template
+func (s *ServiceImpl) diffAsk(ctx context.Context, mAddr address.Address, t *askTemplate) (*AskDiff, error) {
+	ret := &AskDiff{Miner: mAddr, Template: t.name}
+	add := func(field string, current, expected interface{}) {
+		c, e := fmt.Sprint(current), fmt.Sprint(expected)
+		if c != e {
+			ret.Fields = append(ret.Fields, AskFieldDiff{Field: field, Current: c, Template: e})
+		}
+	}
+
+	if t.storage != nil {
+		expected := *t.storage
+		if expected.MaxPieceSize == 0 {
+			info, err := s.Node.StateMinerInfo(ctx, mAddr, types.EmptyTSK)
+			if err != nil {
+				return nil, fmt.Errorf("get miner sector size failed: %s", err)
+			}
+			expected.MaxPieceSize = abi.PaddedPieceSize(info.SectorSize)
+		}
+
+		current, err := s.MinerGetStorageAsk(ctx, mAddr)
+		if err != nil || current == nil {
+			add("StorageAsk", "<none>", "set")
+		} else {
+			add("Price", types.FIL(current.Price), types.FIL(expected.Price))
+			add("VerifiedPrice", types.FIL(current.VerifiedPrice), types.FIL(expected.VerifiedPrice))
+			add("MinPieceSize", types.SizeStr(types.NewInt(uint64(current.MinPieceSize))), types.SizeStr(types.NewInt(uint64(expected.MinPieceSize))))
+			add("MaxPieceSize", types.SizeStr(types.NewInt(uint64(current.MaxPieceSize))), types.SizeStr(types.NewInt(uint64(expected.MaxPieceSize))))
+		}
+	}
+
+	if t.retrieval != nil {
+		expected := t.retrieval
+		current, err := s.MinerGetRetrievalAsk(ctx, mAddr)
+		if err != nil || current == nil {
+			add("RetrievalAsk", "<none>", "set")
+		} else {
+			add("PricePerByte", types.FIL(current.PricePerByte), types.FIL(expected.PricePerByte))
+			add("UnsealPrice", types.FIL(current.UnsealPrice), types.FIL(expected.UnsealPrice))
+			add("PaymentInterval", units.BytesSize(float64(current.PaymentInterval)), units.BytesSize(float64(expected.PaymentInterval)))
+			add("PaymentIntervalIncrease", units.BytesSize(float64(current.PaymentIntervalIncrease)), units.BytesSize(float64(expected.PaymentIntervalIncrease)))
+		}
+	}
+	return ret, nil
+}
+
+func (s *ServiceImpl) AskTemplateApply(ctx context.Context, req *AskTemplateApplyReq) ([]AskDiff, error) {
+	templates := s.askTemplates()
+	idx, err := findAskTemplate(templates, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	t, err := parseAskTemplate(templates[idx])
+	if err != nil {
+		return nil, err
+	}
+
+	miners := req.Miner
+	if len(miners) == 0 {
+		miners, err = s.listMiner(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ret := make([]AskDiff, 0, len(miners))
+	for _, m := range miners {
+		diff, err := s.diffAsk(ctx, m, t)
+		if err != nil {
+			ret = append(ret, AskDiff{Miner: m, Template: t.name, Error: err.Error()})
+			continue
+		}
+		if !req.DryRun {
+			if err := s.applyAsk(ctx, m, t); err != nil {
+				diff.Error = err.Error()
+			} else {
+				diff.Applied = true
+			}
+		}
+		ret = append(ret, *diff)
+	}
+
+	if req.DryRun {
+		return ret, nil
+	}
+
+	// assign the miners applied to the template, and remove them from other templates
+	applied := make(map[address.Address]struct{})
+	for _, d := range ret {
+		if d.Applied {
+			applied[d.Miner] = struct{}{}
+		}
+	}
+	err = s.Config.Update(func(cfg *config.Config) error {
+		// the template may be removed or reordered while applying
+		idx, err := findAskTemplate(cfg.Ask.Templates, req.Name)
+		if err != nil {
+			return err
+		}
+		for i := range cfg.Ask.Templates {
+			assigned := make([]string, 0, len(cfg.Ask.Templates[i].Miners))
+			for _, m := range cfg.Ask.Templates[i].Miners {
+				if addr, err := address.NewFromString(m); err == nil {
+					if _, ok := applied[addr]; ok {
+						continue
+					}
+				}
+				assigned = append(assigned, m)
+			}
+			if i == idx {
+				for m := range applied {
+					assigned = append(assigned, m.String())
+				}
+			}
+			cfg.Ask.Templates[i].Miners = assigned
+		}
+		return nil
+	})
+	if err != nil {
+		return ret, fmt.Errorf("assign miners to template failed: %s", err)
+	}
+	return ret, nil
+}
+
+func (s *ServiceImpl) applyAsk(ctx context.Context, mAddr address.Address, t *askTemplate) error {
+	if t.storage != nil {
+		req := *t.storage
+		req.Miner = mAddr
+		if err := s.MinerSetStorageAsk(ctx, &req); err != nil {
+			return fmt.Errorf("set storage ask failed: %s", err)
+		}
+	}
+	if t.retrieval != nil {
+		if err := s.MinerSetRetrievalAsk(ctx, &MinerSetRetrievalAskReq{Ask: *t.retrieval, Miner: mAddr}); err != nil {
+			return fmt.Errorf("set retrieval ask failed: %s", err)
+		}
+	}
+	return nil
+}
+
+// AskDrift lists the miners whose asks deviate from their assigned templates
+func (s *ServiceImpl) AskDrift(ctx context.Context) ([]AskDiff, error) {
+	templates := s.askTemplates()
+
+	ret := make([]AskDiff, 0)
+	for _, cfgTemplate := range templates {
+		t, err := parseAskTemplate(cfgTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ask template %s: %s", cfgTemplate.Name, err)
+		}
+		for _, m := range cfgTemplate.Miners {
+			mAddr, err := address.NewFromString(m)
+			if err != nil {
+				return nil, fmt.Errorf("invalid miner %s of ask template %s: %s", m, t.name, err)
+			}
+			diff, err := s.diffAsk(ctx, mAddr, t)
+			if err != nil {
+				ret = append(ret, AskDiff{Miner: mAddr, Template: t.name, Error: err.Error()})
+				continue
+			}
+			if len(diff.Fields) > 0 {
+				ret = append(ret, *diff)
+			}
+		}
+	}
+	return ret, nil
+}
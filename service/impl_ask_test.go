@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-fil-markets/retrievalmarket"
+	"github.com/filecoin-project/go-fil-markets/storagemarket"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	nodeV1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	market "github.com/filecoin-project/venus/venus-shared/api/market/v1"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	marketTypes "github.com/filecoin-project/venus/venus-shared/types/market"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ipfs-force-community/venus-tool/repo/config"
+)
+
+type askNode struct {
+	nodeV1.FullNode
+	sectorSize abi.SectorSize
+}
+
+func (n *askNode) StateMinerInfo(ctx context.Context, maddr address.Address, tsk types.TipSetKey) (types.MinerInfo, error) {
+	return types.MinerInfo{SectorSize: n.sectorSize}, nil
+}
+
+type askMarket struct {
+	market.IMarket
+	storage   *storagemarket.StorageAsk
+	retrieval *retrievalmarket.Ask
+}
+
+func (m *askMarket) MarketGetAsk(ctx context.Context, mAddr address.Address) (*marketTypes.SignedStorageAsk, error) {
+	if m.storage == nil {
+		return nil, fmt.Errorf("ask not found")
+	}
+	return &marketTypes.SignedStorageAsk{Ask: m.storage}, nil
+}
+
+func (m *askMarket) MarketGetRetrievalAsk(ctx context.Context, mAddr address.Address) (*retrievalmarket.Ask, error) {
+	if m.retrieval == nil {
+		return nil, fmt.Errorf("ask not found")
+	}
+	return m.retrieval, nil
+}
+
+func TestParseAskTemplate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		template  config.AskTemplate
+		wantErr   bool
+		storage   *MinerSetAskReq
+		retrieval *retrievalmarket.Ask
+	}{
+		{name: "no name", template: config.AskTemplate{Price: "1 FIL"}, wantErr: true},
+		{name: "no price", template: config.AskTemplate{Name: "t"}, wantErr: true},
+		{name: "bad price", template: config.AskTemplate{Name: "t", Price: "x"}, wantErr: true},
+		{name: "min piece size too small", template: config.AskTemplate{Name: "t", Price: "1 FIL", MinPieceSize: "128B"}, wantErr: true},
+		{name: "bad duration", template: config.AskTemplate{Name: "t", Price: "1 FIL", Duration: "x"}, wantErr: true},
+		{name: "duration too short", template: config.AskTemplate{Name: "t", Price: "1 FIL", Duration: "1s"}, wantErr: true},
+		{name: "bad payment interval", template: config.AskTemplate{Name: "t", RetrievalPrice: "1 FIL", PaymentInterval: "x"}, wantErr: true},
+		{
+			name:     "storage with defaults",
+			template: config.AskTemplate{Name: "t", Price: "0.1 FIL"},
+			storage: &MinerSetAskReq{
+				Price:         big.NewInt(1e17),
+				VerifiedPrice: big.NewInt(1e17),
+				Duration:      86400,
+				MinPieceSize:  256,
+			},
+		},
+		{
+			name:     "storage",
+			template: config.AskTemplate{Name: "t", Price: "0.1 FIL", VerifiedPrice: "0", MinPieceSize: "1KiB", MaxPieceSize: "32GiB", Duration: "24h"},
+			storage: &MinerSetAskReq{
+				Price:         big.NewInt(1e17),
+				VerifiedPrice: big.Zero(),
+				Duration:      2880,
+				MinPieceSize:  1 << 10,
+				MaxPieceSize:  32 << 30,
+			},
+		},
+		{
+			name:     "retrieval priced per GiB",
+			template: config.AskTemplate{Name: "t", RetrievalPrice: "1 FIL", UnsealPrice: "0.5 FIL", PaymentInterval: "2MiB"},
+			retrieval: &retrievalmarket.Ask{
+				PricePerByte:            big.Div(big.NewInt(1e18), big.NewInt(1<<30)),
+				UnsealPrice:             big.NewInt(5e17),
+				PaymentInterval:         2 << 20,
+				PaymentIntervalIncrease: 1 << 20,
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseAskTemplate(tt.template)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.template.Name, got.name)
+			assert.Equal(t, tt.storage, got.storage)
+			assert.Equal(t, tt.retrieval, got.retrieval)
+		})
+	}
+}
+
+func TestDiffAsk(t *testing.T) {
+	mAddr, err := address.NewIDAddress(1000)
+	assert.NoError(t, err)
+	template, err := parseAskTemplate(config.AskTemplate{
+		Name:           "t",
+		Price:          "0.1 FIL",
+		RetrievalPrice: "0",
+	})
+	assert.NoError(t, err)
+	storageAsk := func(price int64, max abi.PaddedPieceSize) *storagemarket.StorageAsk {
+		return &storagemarket.StorageAsk{
+			Price:         big.NewInt(price),
+			VerifiedPrice: big.NewInt(1e17),
+			MinPieceSize:  256,
+			MaxPieceSize:  max,
+		}
+	}
+	retrievalAsk := func() *retrievalmarket.Ask {
+		ask := *template.retrieval
+		return &ask
+	}
+
+	testCases := []struct {
+		name      string
+		storage   *storagemarket.StorageAsk
+		retrieval *retrievalmarket.Ask
+		want      []string
+	}{
+		{
+			name:      "same as template",
+			storage:   storageAsk(1e17, 32<<30),
+			retrieval: retrievalAsk(),
+		},
+		{
+			name:      "price differs",
+			storage:   storageAsk(2e17, 32<<30),
+			retrieval: retrievalAsk(),
+			want:      []string{"Price"},
+		},
+		{
+			name:      "max piece size defaults to sector size",
+			storage:   storageAsk(1e17, 64<<30),
+			retrieval: retrievalAsk(),
+			want:      []string{"MaxPieceSize"},
+		},
+		{
+			name: "asks not set",
+			want: []string{"StorageAsk", "RetrievalAsk"},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ServiceImpl{
+				Node:   &askNode{sectorSize: 32 << 30},
+				Market: &askMarket{storage: tt.storage, retrieval: tt.retrieval},
+			}
+			diff, err := s.diffAsk(context.Background(), mAddr, template)
+			assert.NoError(t, err)
+			assert.Equal(t, mAddr, diff.Miner)
+			assert.Equal(t, "t", diff.Template)
+			fields := make([]string, 0, len(diff.Fields))
+			for _, f := range diff.Fields {
+				fields = append(fields, f.Field)
+			}
+			if len(tt.want) == 0 {
+				assert.Empty(t, fields)
+			} else {
+				assert.Equal(t, tt.want, fields)
+			}
+		})
+	}
+}
@@ -0,0 +1,936 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-commp-utils/writer"
+	"github.com/filecoin-project/go-commp-utils/zerocomm"
+	commcid "github.com/filecoin-project/go-fil-commcid"
+	"github.com/filecoin-project/go-fil-markets/retrievalmarket"
+	"github.com/filecoin-project/go-fil-markets/storagemarket"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	marketTypes "github.com/filecoin-project/venus/venus-shared/types/market"
+	"github.com/ipfs/go-cid"
+)
+
+// dealPageSize is the batch size to fetch deals from market
+const dealPageSize = 1000
+
+func (s *ServiceImpl) StorageDealList(ctx context.Context, req *StorageDealListReq) (*StorageDealListResp, error) {
+	miners := req.Miner
+	if len(miners) == 0 {
+		var err error
+		miners, err = s.listMiner(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	deals := make([]marketTypes.MinerDeal, 0)
+	for _, m := range miners {
+		minerDeals, err := s.listStorageDeals(ctx, m, req)
+		if err != nil {
+			return nil, fmt.Errorf("list deals of %s failed: %s", m, err)
+		}
+		deals = append(deals, minerDeals...)
+	}
+
+	sort.Slice(deals, func(i, j int) bool {
+		if req.Asc {
+			return deals[i].CreationTime.Time().Before(deals[j].CreationTime.Time())
+		}
+		return deals[i].CreationTime.Time().After(deals[j].CreationTime.Time())
+	})
+
+	ret := &StorageDealListResp{Total: len(deals)}
+	start, end := req.Offset, len(deals)
+	if start > end {
+		start = end
+	}
+	if req.Limit > 0 && start+req.Limit < end {
+		end = start + req.Limit
+	}
+	ret.Deals = deals[start:end]
+	return ret, nil
+}
+
+// storageDealQueryParams converts the filters of req supported by market
+func storageDealQueryParams(miner address.Address, req *StorageDealListReq) *marketTypes.StorageDealQueryParams {
+	params := &marketTypes.StorageDealQueryParams{
+		Miner:             miner,
+		PieceCID:          req.PieceCID,
+		DiscardFailedDeal: !req.IncludeInactive,
+	}
+	if req.State != nil {
+		state := uint64(*req.State)
+		params.State = &state
+	}
+	if req.Client != address.Undef {
+		params.Client = req.Client.String()
+	}
+	return params
+}
+
+// listStorageDeals fetches all deals of the miner matching req from market page by page
+func (s *ServiceImpl) listStorageDeals(ctx context.Context, miner address.Address, req *StorageDealListReq) ([]marketTypes.MinerDeal, error) {
+	params := storageDealQueryParams(miner, req)
+	params.Limit = dealPageSize
+
+	var ret []marketTypes.MinerDeal
+	for {
+		deals, err := s.Market.MarketListIncompleteDeals(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		for i := range deals {
+			if req.match(&deals[i]) {
+				ret = append(ret, deals[i])
+			}
+		}
+		if len(deals) < params.Limit {
+			break
+		}
+		params.Offset += params.Limit
+	}
+	return ret, nil
+}
+
+func (s *ServiceImpl) DealStats(ctx context.Context, req *DealStatsReq) (*DealStatsResp, error) {
+	miners := req.Miner
+	if len(miners) == 0 {
+		var err error
+		miners, err = s.listMiner(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	days := req.Days
+	if days <= 0 {
+		days = 7
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	trendStart := today.AddDate(0, 0, 1-days)
+	ret := &DealStatsResp{Trend: make([]DealDailyStats, days)}
+	for i := range ret.Trend {
+		ret.Trend[i].Date = trendStart.AddDate(0, 0, i).Format("2006-01-02")
+	}
+	dayOf := func(t time.Time) (int, bool) {
+		if t.Before(trendStart) {
+			return 0, false
+		}
+		idx := int(t.Sub(trendStart) / (24 * time.Hour))
+		return idx, idx < days
+	}
+
+	states := make(map[storagemarket.StorageDealStatus]*DealStateStats)
+	pieceStatuses := make(map[marketTypes.PieceStatus]*DealPieceStatusStats)
+	for _, m := range miners {
+		deals, err := s.listStorageDeals(ctx, m, &StorageDealListReq{IncludeInactive: true})
+		if err != nil {
+			return nil, fmt.Errorf("list deals of %s failed: %s", m, err)
+		}
+
+		for i := range deals {
+			deal := &deals[i]
+			size := deal.ClientDealProposal.Proposal.PieceSize
+			brief := DealBrief{
+				ProposalCid:  deal.ProposalCid,
+				DealID:       deal.DealID,
+				Miner:        m,
+				CreationTime: deal.CreationTime.Time(),
+				UpdatedAt:    time.Unix(int64(deal.UpdatedAt), 0),
+			}
+			if deal.UpdatedAt == 0 {
+				brief.UpdatedAt = brief.CreationTime
+			}
+			age := now.Sub(brief.UpdatedAt)
+
+			ret.Total++
+			ret.PieceSize += size
+
+			st, ok := states[deal.State]
+			if !ok {
+				st = &DealStateStats{
+					State:     deal.State,
+					StateName: storagemarket.DealStates[deal.State],
+					Oldest:    brief,
+				}
+				states[deal.State] = st
+			}
+			st.Count++
+			st.PieceSize += size
+			st.Age.add(age)
+			if brief.UpdatedAt.Before(st.Oldest.UpdatedAt) {
+				st.Oldest = brief
+			}
+
+			ps, ok := pieceStatuses[deal.PieceStatus]
+			if !ok {
+				ps = &DealPieceStatusStats{
+					PieceStatus: deal.PieceStatus,
+					Oldest:      brief,
+				}
+				pieceStatuses[deal.PieceStatus] = ps
+			}
+			ps.Count++
+			ps.PieceSize += size
+			ps.Age.add(age)
+			if brief.UpdatedAt.Before(ps.Oldest.UpdatedAt) {
+				ps.Oldest = brief
+			}
+
+			if idx, ok := dayOf(brief.CreationTime); ok {
+				ret.Trend[idx].Accepted++
+			}
+			if idx, ok := dayOf(brief.UpdatedAt); ok {
+				switch deal.State {
+				case storagemarket.StorageDealActive:
+					ret.Trend[idx].Sealed++
+				case storagemarket.StorageDealFailing, storagemarket.StorageDealError, storagemarket.StorageDealSlashed:
+					ret.Trend[idx].Failed++
+				}
+			}
+		}
+	}
+
+	for _, st := range states {
+		ret.States = append(ret.States, *st)
+	}
+	sort.Slice(ret.States, func(i, j int) bool {
+		return ret.States[i].State < ret.States[j].State
+	})
+	for _, ps := range pieceStatuses {
+		ret.PieceStatuses = append(ret.PieceStatuses, *ps)
+	}
+	sort.Slice(ret.PieceStatuses, func(i, j int) bool {
+		return ret.PieceStatuses[i].PieceStatus < ret.PieceStatuses[j].PieceStatus
+	})
+	return ret, nil
+}
+
+func (s *ServiceImpl) StorageDeal(ctx context.Context, proposalCid Cid) (*marketTypes.MinerDeal, error) {
+	id, err := cid.Parse(proposalCid.Cid)
+	if err != nil {
+		return nil, fmt.Errorf("parse cid failed: %w", err)
+	}
+	return s.Market.MarketGetDeal(ctx, id)
+}
+
+func (s *ServiceImpl) StorageDealUpdateState(ctx context.Context, req StorageDealUpdateStateReq) error {
+	return s.Market.UpdateStorageDealStatus(ctx, req.ProposalCid, req.State, req.PieceStatus)
+}
+
+// dealStateTransitions lists the states a deal can be moved from by hand for each target state,
+// deals in a final state like expired or slashed are never moved back to the pipeline
+var dealStateTransitions = map[storagemarket.StorageDealStatus][]storagemarket.StorageDealStatus{
+	storagemarket.StorageDealAwaitingPreCommit: {
+		storagemarket.StorageDealStaged,
+		storagemarket.StorageDealSealing,
+		storagemarket.StorageDealFailing,
+		storagemarket.StorageDealError,
+	},
+	storagemarket.StorageDealSealing: {
+		storagemarket.StorageDealAwaitingPreCommit,
+		storagemarket.StorageDealFailing,
+		storagemarket.StorageDealError,
+	},
+	storagemarket.StorageDealActive: {
+		storagemarket.StorageDealAwaitingPreCommit,
+		storagemarket.StorageDealSealing,
+		storagemarket.StorageDealFailing,
+		storagemarket.StorageDealError,
+	},
+	storagemarket.StorageDealExpired: {
+		storagemarket.StorageDealActive,
+	},
+	storagemarket.StorageDealSlashed: {
+		storagemarket.StorageDealActive,
+	},
+	storagemarket.StorageDealFailing: {
+		storagemarket.StorageDealStaged,
+		storagemarket.StorageDealAwaitingPreCommit,
+		storagemarket.StorageDealSealing,
+		storagemarket.StorageDealPublish,
+		storagemarket.StorageDealPublishing,
+		storagemarket.StorageDealTransferring,
+		storagemarket.StorageDealWaitingForData,
+		storagemarket.StorageDealError,
+	},
+	storagemarket.StorageDealError: {
+		storagemarket.StorageDealStaged,
+		storagemarket.StorageDealAwaitingPreCommit,
+		storagemarket.StorageDealSealing,
+		storagemarket.StorageDealPublish,
+		storagemarket.StorageDealPublishing,
+		storagemarket.StorageDealTransferring,
+		storagemarket.StorageDealWaitingForData,
+		storagemarket.StorageDealFailing,
+	},
+}
+
+// pieceStatusTransitions lists the piece status a deal can be moved from for each target status
+var pieceStatusTransitions = map[marketTypes.PieceStatus][]marketTypes.PieceStatus{
+	marketTypes.Undefine: {marketTypes.Assigned, marketTypes.Packing},
+	marketTypes.Assigned: {marketTypes.Undefine},
+	marketTypes.Packing:  {marketTypes.Undefine, marketTypes.Assigned},
+	marketTypes.Proving:  {marketTypes.Assigned, marketTypes.Packing},
+}
+
+func checkDealTransition(deal *marketTypes.MinerDeal, state storagemarket.StorageDealStatus, pieceStatus marketTypes.PieceStatus) error {
+	if state != storagemarket.StorageDealUnknown && state != deal.State {
+		legal := false
+		for _, from := range dealStateTransitions[state] {
+			legal = legal || from == deal.State
+		}
+		if !legal {
+			return fmt.Errorf("illegal transition from %s to %s", storagemarket.DealStates[deal.State], storagemarket.DealStates[state])
+		}
+	}
+	if pieceStatus != "" && pieceStatus != deal.PieceStatus {
+		legal := false
+		for _, from := range pieceStatusTransitions[pieceStatus] {
+			legal = legal || from == deal.PieceStatus
+		}
+		if !legal {
+			return fmt.Errorf("illegal piece status transition from %s to %s", deal.PieceStatus, pieceStatus)
+		}
+	}
+	return nil
+}
+
+func (s *ServiceImpl) StorageDealBulkUpdate(ctx context.Context, req *StorageDealBulkUpdateReq) (*StorageDealBulkUpdateResp, error) {
+	if req.State == storagemarket.StorageDealUnknown && req.PieceStatus == "" {
+		return nil, fmt.Errorf("must set state or piece status")
+	}
+	operator, err := requireOperator(ctx)
+	if err != nil && !req.DryRun {
+		return nil, err
+	}
+
+	miners := req.Filter.Miner
+	if len(miners) == 0 {
+		var err error
+		miners, err = s.listMiner(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ret := &StorageDealBulkUpdateResp{DryRun: req.DryRun}
+	for _, m := range miners {
+		deals, err := s.listStorageDeals(ctx, m, &req.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("list deals of %s failed: %s", m, err)
+		}
+		for i := range deals {
+			deal := &deals[i]
+			if !req.UpdatedBefore.IsZero() && deal.UpdatedAt != 0 && time.Unix(int64(deal.UpdatedAt), 0).After(req.UpdatedBefore) {
+				continue
+			}
+
+			res := StorageDealUpdateResult{
+				ProposalCid:     deal.ProposalCid,
+				Miner:           m,
+				FromState:       deal.State,
+				ToState:         deal.State,
+				FromPieceStatus: deal.PieceStatus,
+				ToPieceStatus:   deal.PieceStatus,
+			}
+			if req.State != storagemarket.StorageDealUnknown {
+				res.ToState = req.State
+			}
+			if req.PieceStatus != "" {
+				res.ToPieceStatus = req.PieceStatus
+			}
+
+			if err := checkDealTransition(deal, req.State, req.PieceStatus); err != nil {
+				res.Error = err.Error()
+			} else if !req.DryRun {
+				err := s.Market.UpdateStorageDealStatus(ctx, deal.ProposalCid, req.State, req.PieceStatus)
+				if err != nil {
+					res.Error = err.Error()
+				} else {
+					res.Applied = true
+				}
+			}
+
+			if res.Error != "" {
+				ret.Failed++
+			}
+			if res.Applied {
+				ret.Applied++
+			}
+			ret.Results = append(ret.Results, res)
+		}
+	}
+	ret.Matched = len(ret.Results)
+
+	if !req.DryRun && ret.Matched > 0 {
+		err := s.audit.record("deal.bulkupdate", operator, map[string]interface{}{
+			"Request": req,
+			"Applied": ret.Applied,
+			"Failed":  ret.Failed,
+			"Results": ret.Results,
+		})
+		if err != nil {
+			log.Errorf("record audit log failed: %s", err)
+		}
+	}
+	return ret, nil
+}
+
+// DealReconcile compares published deals of miners on chain with deals in droplet,
+// and updates the state of deals in droplet to match the chain only if req.Apply is set
+func (s *ServiceImpl) DealReconcile(ctx context.Context, req *DealReconcileReq) (*DealReconcileResp, error) {
+	operator, err := requireOperator(ctx)
+	if err != nil && req.Apply {
+		return nil, err
+	}
+
+	miners := req.Miner
+	if len(miners) == 0 {
+		var err error
+		miners, err = s.listMiner(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	head, err := s.Node.ChainHead(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get chain head failed: %s", err)
+	}
+	chainDeals, err := s.Node.StateMarketDeals(ctx, head.Key())
+	if err != nil {
+		return nil, fmt.Errorf("get market deals failed: %s", err)
+	}
+
+	ret := &DealReconcileResp{Height: head.Height(), DryRun: !req.Apply}
+	for _, m := range miners {
+		deals, err := s.listStorageDeals(ctx, m, &StorageDealListReq{IncludeInactive: true})
+		if err != nil {
+			return nil, fmt.Errorf("list deals of %s failed: %s", m, err)
+		}
+		localDeals := make(map[abi.DealID]*marketTypes.MinerDeal, len(deals))
+		for i := range deals {
+			if deals[i].DealID != 0 {
+				localDeals[deals[i].DealID] = &deals[i]
+			}
+		}
+
+		sectors, err := s.Node.StateMinerSectors(ctx, m, nil, head.Key())
+		if err != nil {
+			return nil, fmt.Errorf("get sectors of %s failed: %s", m, err)
+		}
+		chainSectors := make(map[abi.DealID]abi.SectorNumber)
+		for _, sector := range sectors {
+			for _, dealID := range sector.DealIDs {
+				chainSectors[dealID] = sector.SectorNumber
+			}
+		}
+
+		var mismatches []DealMismatch
+		for key, chainDeal := range chainDeals {
+			if chainDeal.Proposal.Provider != m {
+				continue
+			}
+			id, err := strconv.ParseUint(key, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parse deal id %s failed: %s", key, err)
+			}
+			dealID := abi.DealID(id)
+			ret.Checked++
+
+			mm := DealMismatch{
+				Miner:            m,
+				DealID:           dealID,
+				ChainSector:      chainSectors[dealID],
+				StartEpoch:       chainDeal.Proposal.StartEpoch,
+				SectorStartEpoch: chainDeal.State.SectorStartEpoch,
+				SlashEpoch:       chainDeal.State.SlashEpoch,
+			}
+			deal := localDeals[dealID]
+			if !classifyDealMismatch(&mm, deal, chainDeal, head.Height()) {
+				continue
+			}
+			if deal == nil {
+				mismatches = append(mismatches, mm)
+				continue
+			}
+
+			if mm.Action != storagemarket.StorageDealUnknown {
+				if err := checkDealTransition(deal, mm.Action, ""); err != nil {
+					mm.Error = err.Error()
+				} else if req.Apply {
+					if err := s.Market.UpdateStorageDealStatus(ctx, deal.ProposalCid, mm.Action, ""); err != nil {
+						mm.Error = err.Error()
+					} else {
+						mm.Applied = true
+					}
+				}
+			}
+			if mm.Error != "" {
+				ret.Failed++
+			}
+			if mm.Applied {
+				ret.Applied++
+			}
+			mismatches = append(mismatches, mm)
+		}
+
+		sort.Slice(mismatches, func(i, j int) bool {
+			return mismatches[i].DealID < mismatches[j].DealID
+		})
+		ret.Mismatches = append(ret.Mismatches, mismatches...)
+	}
+
+	if ret.Applied > 0 {
+		err := s.audit.record("deal.reconcile", operator, map[string]interface{}{
+			"Request":    req,
+			"Height":     ret.Height,
+			"Applied":    ret.Applied,
+			"Failed":     ret.Failed,
+			"Mismatches": ret.Mismatches,
+		})
+		if err != nil {
+			log.Errorf("record audit log failed: %s", err)
+		}
+	}
+	return ret, nil
+}
+
+// classifyDealMismatch compares the local deal with the deal on chain at height, and fills the kind and the action of mm,
+// deal is nil if it is missing locally, returns false if they match
+func classifyDealMismatch(mm *DealMismatch, deal *marketTypes.MinerDeal, chainDeal *types.MarketDeal, height abi.ChainEpoch) bool {
+	if deal == nil {
+		mm.Kind = DealMissingLocally
+		return true
+	}
+	mm.ProposalCid = &deal.ProposalCid
+	mm.LocalState = deal.State
+	mm.LocalSector = deal.SectorNumber
+
+	switch {
+	case chainDeal.State.SlashEpoch != -1:
+		if deal.State == storagemarket.StorageDealSlashed {
+			return false
+		}
+		mm.Kind = DealSlashed
+		mm.Action = storagemarket.StorageDealSlashed
+	case chainDeal.State.SectorStartEpoch != -1:
+		if deal.State != storagemarket.StorageDealActive {
+			mm.Kind = DealActiveOnChain
+			mm.Action = storagemarket.StorageDealActive
+		} else if mm.ChainSector != 0 && mm.ChainSector != deal.SectorNumber {
+			mm.Kind = DealSectorMismatch
+		} else {
+			return false
+		}
+	case height > chainDeal.Proposal.StartEpoch:
+		switch deal.State {
+		case storagemarket.StorageDealError, storagemarket.StorageDealFailing,
+			storagemarket.StorageDealExpired, storagemarket.StorageDealSlashed:
+			return false
+		}
+		mm.Kind = DealNotActivated
+		mm.Action = storagemarket.StorageDealError
+	default:
+		return false
+	}
+	return true
+}
+
+// calcCommP calculates the piece cid and padded size of the car file
+func calcCommP(path string) (*writer.DataCIDSize, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	w := &writer.Writer{}
+	if _, err := io.Copy(w, f); err != nil {
+		return nil, fmt.Errorf("read %s failed: %s", path, err)
+	}
+	ret, err := w.Sum()
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// padCommP pads the piece commitment of size from up to size to with zero pieces, the same way as droplet does
+// when the car file is smaller than the piece of the proposal
+func padCommP(pieceCID cid.Cid, from, to abi.PaddedPieceSize) (cid.Cid, error) {
+	comm, err := commcid.CIDToDataCommitmentV1(pieceCID)
+	if err != nil {
+		return cid.Undef, err
+	}
+	for size := from; size < to; size *= 2 {
+		zero, err := commcid.CIDToDataCommitmentV1(zerocomm.ZeroPieceCommitment(size.Unpadded()))
+		if err != nil {
+			return cid.Undef, err
+		}
+		node := sha256.Sum256(append(comm, zero...))
+		// the hash is truncated to 254 bits
+		node[31] &= 0x3f
+		comm = node[:]
+	}
+	return commcid.DataCommitmentV1ToCID(comm)
+}
+
+func (s *ServiceImpl) checkDealImport(ctx context.Context, ref *DealImportRef, skipCommP bool) error {
+	deal, err := s.Market.MarketGetDeal(ctx, ref.ProposalCid)
+	if err != nil {
+		return fmt.Errorf("get deal failed: %s", err)
+	}
+	if deal.State != storagemarket.StorageDealWaitingForData {
+		return fmt.Errorf("deal is in state %s rather than %s", storagemarket.DealStates[deal.State], storagemarket.DealStates[storagemarket.StorageDealWaitingForData])
+	}
+	if skipCommP {
+		return nil
+	}
+
+	commP, err := calcCommP(ref.File)
+	if err != nil {
+		return fmt.Errorf("calculate commP failed: %s", err)
+	}
+	proposal := &deal.ClientDealProposal.Proposal
+	if commP.PieceSize > proposal.PieceSize {
+		return fmt.Errorf("piece size of file %d exceeds piece size of proposal %d", commP.PieceSize, proposal.PieceSize)
+	}
+	pieceCID := commP.PieceCID
+	if commP.PieceSize < proposal.PieceSize {
+		pieceCID, err = padCommP(commP.PieceCID, commP.PieceSize, proposal.PieceSize)
+		if err != nil {
+			return fmt.Errorf("pad commP failed: %s", err)
+		}
+	}
+	if !pieceCID.Equals(proposal.PieceCID) {
+		return fmt.Errorf("commP of file %s mismatch piece cid of proposal %s", pieceCID, proposal.PieceCID)
+	}
+	return nil
+}
+
+func (s *ServiceImpl) DealImport(ctx context.Context, req *DealImportReq) ([]DealImportResult, error) {
+	if len(req.Refs) == 0 {
+		return nil, fmt.Errorf("no deal to import")
+	}
+
+	ret := make([]DealImportResult, 0, len(req.Refs))
+	for i := range req.Refs {
+		ref := &req.Refs[i]
+		res := DealImportResult{ProposalCid: ref.ProposalCid, File: ref.File}
+		if err := s.checkDealImport(ctx, ref, req.SkipCommP); err != nil {
+			res.Error = err.Error()
+		} else {
+			// commP is checked above already, or skipped on request
+			err := s.Market.DealsImportData(ctx, marketTypes.ImportDataRef{
+				ProposalCID: ref.ProposalCid,
+				File:        ref.File,
+			}, true)
+			if err != nil {
+				res.Error = err.Error()
+			}
+		}
+		ret = append(ret, res)
+	}
+	return ret, nil
+}
+
+// DealUnassignedPieces lists deals waiting for being packed into a sector
+func (s *ServiceImpl) DealUnassignedPieces(ctx context.Context, req *DealUnassignedPiecesReq) ([]marketTypes.MinerDeal, error) {
+	miners := req.Miner
+	if len(miners) == 0 {
+		var err error
+		miners, err = s.listMiner(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	state := storagemarket.StorageDealAwaitingPreCommit
+	filter := &StorageDealListReq{
+		State:       &state,
+		PieceStatus: marketTypes.Undefine,
+	}
+	ret := make([]marketTypes.MinerDeal, 0)
+	for _, m := range miners {
+		deals, err := s.listStorageDeals(ctx, m, filter)
+		if err != nil {
+			return nil, fmt.Errorf("list deals of %s failed: %s", m, err)
+		}
+		ret = append(ret, deals...)
+	}
+	return ret, nil
+}
+
+func (s *ServiceImpl) DealAssignPiece(ctx context.Context, req *DealAssignPieceReq) error {
+	if req.DealID == 0 {
+		return fmt.Errorf("deal id is required")
+	}
+	deal, err := s.findStorageDeal(ctx, req.Miner, req.DealID)
+	if err != nil {
+		return err
+	}
+	if deal.PieceStatus != marketTypes.Undefine {
+		return fmt.Errorf("piece of deal %d is %s already", req.DealID, deal.PieceStatus)
+	}
+	if size := deal.ClientDealProposal.Proposal.PieceSize; req.Offset%size != 0 {
+		return fmt.Errorf("offset %d is not aligned to piece size %d", req.Offset, size)
+	}
+
+	return s.Market.UpdateDealOnPacking(ctx, req.Miner, req.DealID, req.Sector, req.Offset)
+}
+
+// findStorageDeal looks up the deal of the miner by deal id page by page,
+// market ignores the deal id in query params
+func (s *ServiceImpl) findStorageDeal(ctx context.Context, miner address.Address, dealID abi.DealID) (*marketTypes.MinerDeal, error) {
+	params := &marketTypes.StorageDealQueryParams{
+		Miner: miner,
+		Page: marketTypes.Page{
+			Limit: dealPageSize,
+		},
+	}
+	for {
+		deals, err := s.Market.MarketListIncompleteDeals(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		for i := range deals {
+			if deals[i].DealID == dealID {
+				return &deals[i], nil
+			}
+		}
+		if len(deals) < params.Limit {
+			return nil, fmt.Errorf("deal %d of %s not found", dealID, miner)
+		}
+		params.Offset += params.Limit
+	}
+}
+
+func (s *ServiceImpl) RetrievalDealList(ctx context.Context, req *RetrievalDealListReq) (*RetrievalDealListResp, error) {
+	deals, err := s.listRetrievalDeals(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(deals, func(i, j int) bool {
+		return deals[i].CreatedAt > deals[j].CreatedAt
+	})
+
+	ret := &RetrievalDealListResp{Total: len(deals)}
+	start, end := req.Offset, len(deals)
+	if start > end {
+		start = end
+	}
+	if req.Limit > 0 && start+req.Limit < end {
+		end = start + req.Limit
+	}
+	ret.Deals = deals[start:end]
+	return ret, nil
+}
+
+func (s *ServiceImpl) RetrievalDealStats(ctx context.Context, req *RetrievalDealListReq) ([]RetrievalDealStats, error) {
+	deals, err := s.listRetrievalDeals(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[address.Address]*RetrievalDealStats)
+	for i := range deals {
+		deal := &deals[i]
+		st, ok := stats[deal.Miner]
+		if !ok {
+			st = &RetrievalDealStats{
+				Miner:          deal.Miner,
+				FundsReceived:  big.Zero(),
+				FailureReasons: make(map[string]int),
+			}
+			stats[deal.Miner] = st
+		}
+
+		st.Deals++
+		st.BytesSent += deal.TotalSent
+		if !deal.FundsReceived.Nil() {
+			st.FundsReceived = big.Add(st.FundsReceived, deal.FundsReceived)
+		}
+		switch deal.Status {
+		case retrievalmarket.DealStatusCompleted:
+			st.Completed++
+		case retrievalmarket.DealStatusFailing, retrievalmarket.DealStatusErrored, retrievalmarket.DealStatusRejected:
+			st.Failed++
+			reason := deal.Message
+			if reason == "" {
+				reason = retrievalmarket.DealStatuses[deal.Status]
+			}
+			st.FailureReasons[reason]++
+		}
+	}
+
+	ret := make([]RetrievalDealStats, 0, len(stats))
+	for _, st := range stats {
+		ret = append(ret, *st)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].Miner.String() < ret[j].Miner.String()
+	})
+	return ret, nil
+}
+
+// listRetrievalDeals fetches all retrieval deals matching req from market page by page,
+// and finds out the miner of each deal by the storage deal it retrieved from
+func (s *ServiceImpl) listRetrievalDeals(ctx context.Context, req *RetrievalDealListReq) ([]RetrievalDeal, error) {
+	params := &marketTypes.RetrievalDealQueryParams{
+		Receiver:   req.Receiver,
+		PayloadCID: req.PayloadCID,
+		Page: marketTypes.Page{
+			Limit: dealPageSize,
+		},
+	}
+	if req.Status != nil {
+		status := uint64(*req.Status)
+		params.Status = &status
+	}
+
+	miners := make(map[cid.Cid]address.Address)
+	getMiner := func(proposalCid cid.Cid) address.Address {
+		if !proposalCid.Defined() {
+			return address.Undef
+		}
+		if m, ok := miners[proposalCid]; ok {
+			return m
+		}
+		m := address.Undef
+		deal, err := s.Market.MarketGetDeal(ctx, proposalCid)
+		if err != nil {
+			log.Warnf("get storage deal %s failed: %s", proposalCid, err)
+		} else {
+			m = deal.ClientDealProposal.Proposal.Provider
+		}
+		miners[proposalCid] = m
+		return m
+	}
+
+	var ret []RetrievalDeal
+	for {
+		deals, err := s.Market.MarketListRetrievalDeals(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		for i := range deals {
+			deal := RetrievalDeal{
+				ProviderDealState: deals[i],
+				Miner:             getMiner(deals[i].SelStorageProposalCid),
+			}
+			if req.match(&deal) {
+				ret = append(ret, deal)
+			}
+		}
+		if len(deals) < params.Limit {
+			break
+		}
+		params.Offset += params.Limit
+	}
+	return ret, nil
+}
+
+// searchPiece finds deals, sectors and shard of the piece, or pieces containing the payload,
+// nil is returned if nothing is found
+func (s *ServiceImpl) searchPiece(ctx context.Context, c cid.Cid) (*PieceSearchResp, error) {
+	ret := &PieceSearchResp{}
+	pieceCids := []cid.Cid{c}
+	if info, err := s.Market.PiecesGetCIDInfo(ctx, c); err == nil && len(info.PieceBlockLocations) > 0 {
+		ret.PayloadCID = &c
+		pieceCids = pieceCids[:0]
+		seen := make(map[cid.Cid]struct{})
+		for _, loc := range info.PieceBlockLocations {
+			if _, ok := seen[loc.PieceCID]; !ok {
+				seen[loc.PieceCID] = struct{}{}
+				pieceCids = append(pieceCids, loc.PieceCID)
+			}
+		}
+	}
+
+	miners, err := s.listMiner(ctx)
+	if err != nil {
+		return nil, err
+	}
+	shards, err := s.Market.DagstoreListShards(ctx)
+	if err != nil {
+		log.Warnf("list dagstore shards failed: %s", err)
+	}
+
+	for _, pieceCid := range pieceCids {
+		item := PieceSearchItem{PieceCID: pieceCid}
+		for _, m := range miners {
+			deals, err := s.listStorageDeals(ctx, m, &StorageDealListReq{PieceCID: pieceCid.String(), IncludeInactive: true})
+			if err != nil {
+				return nil, fmt.Errorf("list deals of %s failed: %s", m, err)
+			}
+			item.Deals = append(item.Deals, deals...)
+		}
+
+		// sectors are from deals of droplet, and the piece store which records the sectors of deals sealed
+		dealMiners := make(map[abi.DealID]address.Address)
+		sectors := make(map[abi.DealID]abi.SectorNumber)
+		for _, deal := range item.Deals {
+			dealMiners[deal.DealID] = deal.ClientDealProposal.Proposal.Provider
+			if deal.DealID != 0 && deal.SectorNumber != 0 {
+				sectors[deal.DealID] = deal.SectorNumber
+			}
+		}
+		pieceInfo, err := s.Market.PiecesGetPieceInfo(ctx, pieceCid)
+		if err == nil {
+			for _, d := range pieceInfo.Deals {
+				sectors[d.DealID] = d.SectorID
+			}
+		}
+		if len(item.Deals) == 0 && len(sectors) == 0 {
+			continue
+		}
+
+		for dealID, sector := range sectors {
+			m, ok := dealMiners[dealID]
+			if !ok {
+				continue
+			}
+			ps := PieceSector{Miner: m, DealID: dealID, Sector: sector}
+			info, err := s.Node.StateSectorGetInfo(ctx, m, sector, types.EmptyTSK)
+			if err != nil {
+				log.Warnf("get sector(%d) of %s failed: %s", sector, m, err)
+			}
+			ps.SectorInfo = info
+			item.Sectors = append(item.Sectors, ps)
+		}
+		sort.Slice(item.Sectors, func(i, j int) bool {
+			return item.Sectors[i].DealID < item.Sectors[j].DealID
+		})
+
+		for i := range shards {
+			if shards[i].Key == pieceCid.String() {
+				item.Shard = &shards[i]
+				item.Retrievable = shards[i].State == "ShardStateAvailable" || shards[i].State == "ShardStateServing"
+				break
+			}
+		}
+		ret.Pieces = append(ret.Pieces, item)
+	}
+
+	if len(ret.Pieces) == 0 {
+		return nil, nil
+	}
+	return ret, nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-commp-utils/zerocomm"
+	"github.com/filecoin-project/go-fil-markets/storagemarket"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	marketTypes "github.com/filecoin-project/venus/venus-shared/types/market"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPadCommP(t *testing.T) {
+	testCases := []struct {
+		name string
+		from abi.PaddedPieceSize
+		to   abi.PaddedPieceSize
+	}{
+		{name: "no padding", from: 2048, to: 2048},
+		{name: "one level", from: 2048, to: 4096},
+		{name: "several levels", from: 128, to: 1 << 20},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			// padding a zero piece must be the zero piece of the target size
+			got, err := padCommP(zerocomm.ZeroPieceCommitment(tt.from.Unpadded()), tt.from, tt.to)
+			assert.NoError(t, err)
+			assert.Equal(t, zerocomm.ZeroPieceCommitment(tt.to.Unpadded()), got)
+		})
+	}
+}
+
+func TestCheckDealTransition(t *testing.T) {
+	testCases := []struct {
+		name      string
+		from      storagemarket.StorageDealStatus
+		fromPiece marketTypes.PieceStatus
+		to        storagemarket.StorageDealStatus
+		toPiece   marketTypes.PieceStatus
+		wantErr   bool
+	}{
+		{name: "no change", from: storagemarket.StorageDealSealing, fromPiece: marketTypes.Proving},
+		{name: "same state", from: storagemarket.StorageDealSealing, to: storagemarket.StorageDealSealing},
+		{name: "error to awaiting pre commit", from: storagemarket.StorageDealError, to: storagemarket.StorageDealAwaitingPreCommit},
+		{name: "sealing to active", from: storagemarket.StorageDealSealing, to: storagemarket.StorageDealActive},
+		{name: "active to slashed", from: storagemarket.StorageDealActive, to: storagemarket.StorageDealSlashed},
+		{name: "failing to error", from: storagemarket.StorageDealFailing, to: storagemarket.StorageDealError},
+		{name: "expired back to pipeline", from: storagemarket.StorageDealExpired, to: storagemarket.StorageDealAwaitingPreCommit, wantErr: true},
+		{name: "slashed to active", from: storagemarket.StorageDealSlashed, to: storagemarket.StorageDealActive, wantErr: true},
+		{name: "to a state not listed", from: storagemarket.StorageDealError, to: storagemarket.StorageDealValidating, wantErr: true},
+		{name: "assigned piece back to undefine", fromPiece: marketTypes.Assigned, toPiece: marketTypes.Undefine},
+		{name: "packing piece to proving", fromPiece: marketTypes.Packing, toPiece: marketTypes.Proving},
+		{name: "proving piece back to undefine", fromPiece: marketTypes.Proving, toPiece: marketTypes.Undefine, wantErr: true},
+		{
+			name:      "legal state with illegal piece status",
+			from:      storagemarket.StorageDealError,
+			to:        storagemarket.StorageDealAwaitingPreCommit,
+			fromPiece: marketTypes.Proving,
+			toPiece:   marketTypes.Assigned,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			deal := &marketTypes.MinerDeal{State: tt.from, PieceStatus: tt.fromPiece}
+			err := checkDealTransition(deal, tt.to, tt.toPiece)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestClassifyDealMismatch(t *testing.T) {
+	chainDeal := func(start, sectorStart, slash abi.ChainEpoch) *types.MarketDeal {
+		d := &types.MarketDeal{}
+		d.Proposal.StartEpoch = start
+		d.State.SectorStartEpoch = sectorStart
+		d.State.SlashEpoch = slash
+		return d
+	}
+	localDeal := func(state storagemarket.StorageDealStatus, sector abi.SectorNumber) *marketTypes.MinerDeal {
+		return &marketTypes.MinerDeal{State: state, SectorNumber: sector}
+	}
+	const height = 1000
+
+	testCases := []struct {
+		name        string
+		local       *marketTypes.MinerDeal
+		chain       *types.MarketDeal
+		chainSector abi.SectorNumber
+		mismatch    bool
+		kind        DealMismatchKind
+		action      storagemarket.StorageDealStatus
+	}{
+		{
+			name:     "missing locally",
+			chain:    chainDeal(900, 800, -1),
+			mismatch: true,
+			kind:     DealMissingLocally,
+		},
+		{
+			name:        "active on both sides",
+			local:       localDeal(storagemarket.StorageDealActive, 1),
+			chain:       chainDeal(900, 800, -1),
+			chainSector: 1,
+		},
+		{
+			name:        "active on chain only",
+			local:       localDeal(storagemarket.StorageDealAwaitingPreCommit, 1),
+			chain:       chainDeal(900, 800, -1),
+			chainSector: 1,
+			mismatch:    true,
+			kind:        DealActiveOnChain,
+			action:      storagemarket.StorageDealActive,
+		},
+		{
+			name:        "sector differs",
+			local:       localDeal(storagemarket.StorageDealActive, 1),
+			chain:       chainDeal(900, 800, -1),
+			chainSector: 2,
+			mismatch:    true,
+			kind:        DealSectorMismatch,
+		},
+		{
+			name:     "slashed on chain",
+			local:    localDeal(storagemarket.StorageDealActive, 1),
+			chain:    chainDeal(900, 800, 950),
+			mismatch: true,
+			kind:     DealSlashed,
+			action:   storagemarket.StorageDealSlashed,
+		},
+		{
+			name:  "slashed on both sides",
+			local: localDeal(storagemarket.StorageDealSlashed, 1),
+			chain: chainDeal(900, 800, 950),
+		},
+		{
+			name:     "not activated before start epoch",
+			local:    localDeal(storagemarket.StorageDealSealing, 1),
+			chain:    chainDeal(900, -1, -1),
+			mismatch: true,
+			kind:     DealNotActivated,
+			action:   storagemarket.StorageDealError,
+		},
+		{
+			name:  "not activated and already failed locally",
+			local: localDeal(storagemarket.StorageDealError, 1),
+			chain: chainDeal(900, -1, -1),
+		},
+		{
+			name:  "start epoch not reached",
+			local: localDeal(storagemarket.StorageDealSealing, 1),
+			chain: chainDeal(1100, -1, -1),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mm := DealMismatch{ChainSector: tt.chainSector}
+			assert.Equal(t, tt.mismatch, classifyDealMismatch(&mm, tt.local, tt.chain, height))
+			if !tt.mismatch {
+				return
+			}
+			assert.Equal(t, tt.kind, mm.Kind)
+			assert.Equal(t, tt.action, mm.Action)
+			if tt.local != nil {
+				assert.Equal(t, tt.local.State, mm.LocalState)
+			} else {
+				assert.Nil(t, mm.ProposalCid)
+			}
+		})
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/filecoin-project/venus/venus-shared/actors/builtin/miner"
 	"github.com/filecoin-project/venus/venus-shared/blockstore"
 	"github.com/filecoin-project/venus/venus-shared/types"
+	marketTypes "github.com/filecoin-project/venus/venus-shared/types/market"
 	msgTypes "github.com/filecoin-project/venus/venus-shared/types/messager"
 	cbor "github.com/ipfs/go-ipld-cbor"
 )
@@ -167,32 +168,42 @@ func (s *ServiceImpl) MinerSetOwner(ctx context.Context, p *MinerSetOwnerReq) er
 		return fmt.Errorf("get miner(%s) info failed: %s", p.Miner, err)
 	}
 
+	setMsg, err := s.setOwnerMsg(ctx, &minerInfo, p)
+	if err != nil {
+		return err
+	}
+
+	msg, err := s.PushMessageAndWait(ctx, setMsg, nil)
+	if err != nil {
+		return fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
+	}
+
+	return nil
+}
+
+// setOwnerMsg builds the message of the owner to propose the new owner
+func (s *ServiceImpl) setOwnerMsg(ctx context.Context, minerInfo *types.MinerInfo, p *MinerSetOwnerReq) (*types.Message, error) {
 	newOwnerId, err := s.Node.StateLookupID(ctx, p.NewOwner, types.EmptyTSK)
 	if err != nil {
-		return fmt.Errorf("get new owner(%s) id failed: %s", p.NewOwner, err)
+		return nil, fmt.Errorf("get new owner(%s) id failed: %s", p.NewOwner, err)
 	}
 
 	if minerInfo.Owner == newOwnerId {
-		return fmt.Errorf("new owner(%s) is the same as old owner(%s)", p.NewOwner, minerInfo.Owner)
+		return nil, fmt.Errorf("new owner(%s) is the same as old owner(%s)", p.NewOwner, minerInfo.Owner)
 	}
 
 	param, err := actors.SerializeParams(&newOwnerId)
 	if err != nil {
-		return fmt.Errorf("serialize params failed: %s", err)
+		return nil, fmt.Errorf("serialize params failed: %s", err)
 	}
 
-	msg, err := s.PushMessageAndWait(ctx, &types.Message{
+	return &types.Message{
 		From:   minerInfo.Owner,
 		To:     p.Miner,
 		Method: builtin.MethodsMiner.ChangeOwnerAddress,
 		Params: param,
 		Value:  big.Zero(),
-	}, nil)
-	if err != nil {
-		return fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
-	}
-
-	return nil
+	}, nil
 }
 
 func (s *ServiceImpl) MinerConfirmOwner(ctx context.Context, p *MinerSetOwnerReq) (oldOwner address.Address, err error) {
@@ -202,32 +213,42 @@ func (s *ServiceImpl) MinerConfirmOwner(ctx context.Context, p *MinerSetOwnerReq
 	}
 	oldOwner = minerInfo.Owner
 
+	confirmMsg, err := s.confirmOwnerMsg(ctx, &minerInfo, p)
+	if err != nil {
+		return address.Undef, err
+	}
+
+	msg, err := s.PushMessageAndWait(ctx, confirmMsg, nil)
+	if err != nil {
+		return address.Undef, fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
+	}
+
+	return oldOwner, nil
+}
+
+// confirmOwnerMsg builds the message of the new owner to confirm the owner change
+func (s *ServiceImpl) confirmOwnerMsg(ctx context.Context, minerInfo *types.MinerInfo, p *MinerSetOwnerReq) (*types.Message, error) {
 	newOwnerId, err := s.Node.StateLookupID(ctx, p.NewOwner, types.EmptyTSK)
 	if err != nil {
-		return address.Undef, fmt.Errorf("get new owner(%s) id failed: %s", p.NewOwner, err)
+		return nil, fmt.Errorf("get new owner(%s) id failed: %s", p.NewOwner, err)
 	}
 
 	if minerInfo.Owner == newOwnerId {
-		return address.Undef, fmt.Errorf("new owner(%s) is the same as old owner(%s)", p.NewOwner, minerInfo.Owner)
+		return nil, fmt.Errorf("new owner(%s) is the same as old owner(%s)", p.NewOwner, minerInfo.Owner)
 	}
 
 	param, err := actors.SerializeParams(&newOwnerId)
 	if err != nil {
-		return address.Undef, fmt.Errorf("serialize params failed: %s", err)
+		return nil, fmt.Errorf("serialize params failed: %s", err)
 	}
 
-	msg, err := s.PushMessageAndWait(ctx, &types.Message{
+	return &types.Message{
 		From:   p.NewOwner,
 		To:     p.Miner,
 		Method: builtin.MethodsMiner.ChangeOwnerAddress,
 		Params: param,
 		Value:  big.Zero(),
-	}, nil)
-	if err != nil {
-		return address.Undef, fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
-	}
-
-	return oldOwner, nil
+	}, nil
 }
 
 func (s *ServiceImpl) MinerSetWorker(ctx context.Context, req *MinerSetWorkerReq) (WorkerChangeEpoch abi.ChainEpoch, err error) {
@@ -236,17 +257,37 @@ func (s *ServiceImpl) MinerSetWorker(ctx context.Context, req *MinerSetWorkerReq
 		return 0, fmt.Errorf("get miner(%s) info failed: %s", req.Miner, err)
 	}
 
+	setMsg, err := s.setWorkerMsg(ctx, &minerInfo, req)
+	if err != nil {
+		return 0, err
+	}
+
+	msg, err := s.PushMessageAndWait(ctx, setMsg, nil)
+	if err != nil {
+		return 0, fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
+	}
+
+	minerInfo, err = s.Node.StateMinerInfo(ctx, req.Miner, types.EmptyTSK)
+	if err != nil {
+		return 0, fmt.Errorf("get miner(%s) info failed: %s", req.Miner, err)
+	}
+
+	return minerInfo.WorkerChangeEpoch, nil
+}
+
+// setWorkerMsg builds the message of the owner to propose the new worker
+func (s *ServiceImpl) setWorkerMsg(ctx context.Context, minerInfo *types.MinerInfo, req *MinerSetWorkerReq) (*types.Message, error) {
 	newWorkerId, err := s.Node.StateLookupID(ctx, req.NewWorker, types.EmptyTSK)
 	if err != nil {
-		return 0, fmt.Errorf("get new worker(%s) id failed: %s", req.NewWorker, err)
+		return nil, fmt.Errorf("get new worker(%s) id failed: %s", req.NewWorker, err)
 	}
 
 	if minerInfo.Worker == newWorkerId {
-		return 0, fmt.Errorf("new worker(%s) is the same as old worker(%s)", req.NewWorker, minerInfo.Worker)
+		return nil, fmt.Errorf("new worker(%s) is the same as old worker(%s)", req.NewWorker, minerInfo.Worker)
 	}
 
 	if minerInfo.NewWorker == newWorkerId {
-		return 0, fmt.Errorf("new worker(%s) has been proposed before, which will be effective after epoch(%d)", minerInfo.NewWorker, minerInfo.WorkerChangeEpoch)
+		return nil, fmt.Errorf("new worker(%s) has been proposed before, which will be effective after epoch(%d)", minerInfo.NewWorker, minerInfo.WorkerChangeEpoch)
 	}
 
 	param, err := actors.SerializeParams(&types.ChangeWorkerAddressParams{
@@ -254,62 +295,62 @@ func (s *ServiceImpl) MinerSetWorker(ctx context.Context, req *MinerSetWorkerReq
 		NewControlAddrs: minerInfo.ControlAddresses,
 	})
 	if err != nil {
-		return 0, fmt.Errorf("serialize params failed: %s", err)
+		return nil, fmt.Errorf("serialize params failed: %s", err)
 	}
 
-	msg, err := s.PushMessageAndWait(ctx, &types.Message{
+	return &types.Message{
 		From:   minerInfo.Owner,
 		To:     req.Miner,
 		Method: builtin.MethodsMiner.ChangeWorkerAddress,
 		Params: param,
 		Value:  big.Zero(),
-	}, nil)
+	}, nil
+}
+
+func (s *ServiceImpl) MinerConfirmWorker(ctx context.Context, req *MinerSetWorkerReq) error {
+	minerInfo, err := s.Node.StateMinerInfo(ctx, req.Miner, types.EmptyTSK)
 	if err != nil {
-		return 0, fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
+		return fmt.Errorf("get miner(%s) info failed: %s", req.Miner, err)
 	}
 
-	minerInfo, err = s.Node.StateMinerInfo(ctx, req.Miner, types.EmptyTSK)
+	confirmMsg, err := s.confirmWorkerMsg(ctx, &minerInfo, req)
 	if err != nil {
-		return 0, fmt.Errorf("get miner(%s) info failed: %s", req.Miner, err)
+		return err
 	}
 
-	return minerInfo.WorkerChangeEpoch, nil
-}
-
-func (s *ServiceImpl) MinerConfirmWorker(ctx context.Context, req *MinerSetWorkerReq) error {
-	minerInfo, err := s.Node.StateMinerInfo(ctx, req.Miner, types.EmptyTSK)
+	msg, err := s.PushMessageAndWait(ctx, confirmMsg, nil)
 	if err != nil {
-		return fmt.Errorf("get miner(%s) info failed: %s", req.Miner, err)
+		return fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
 	}
 
+	return nil
+}
+
+// confirmWorkerMsg builds the message of the owner to confirm the worker change
+func (s *ServiceImpl) confirmWorkerMsg(ctx context.Context, minerInfo *types.MinerInfo, req *MinerSetWorkerReq) (*types.Message, error) {
 	if minerInfo.NewWorker.Empty() {
-		return fmt.Errorf("miner(%s) has no new worker", req.Miner)
+		return nil, fmt.Errorf("miner(%s) has no new worker", req.Miner)
 	}
 
 	if minerInfo.NewWorker != req.NewWorker {
-		return fmt.Errorf("new worker(%s) is not the same as proposed worker(%s)", req.NewWorker, minerInfo.NewWorker)
+		return nil, fmt.Errorf("new worker(%s) is not the same as proposed worker(%s)", req.NewWorker, minerInfo.NewWorker)
 	}
 
 	head, err := s.Node.ChainHead(ctx)
 	if err != nil {
-		return fmt.Errorf("get chain head failed: %s", err)
+		return nil, fmt.Errorf("get chain head failed: %s", err)
 	}
 
 	if head.Height() < minerInfo.WorkerChangeEpoch {
-		return fmt.Errorf("worker change epoch(%d) is not reached", minerInfo.WorkerChangeEpoch)
+		return nil, fmt.Errorf("worker change epoch(%d) is not reached", minerInfo.WorkerChangeEpoch)
 	}
 
-	msg, err := s.PushMessageAndWait(ctx, &types.Message{
+	return &types.Message{
 		From:   minerInfo.Owner,
 		To:     req.Miner,
 		Method: builtin.MethodsMiner.ConfirmChangeWorkerAddressExported,
 		Value:  big.Zero(),
-	}, nil)
-	if err != nil {
-		return fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
-	}
-
-	return nil
+	}, nil
 }
 
 func (s *ServiceImpl) MinerSetControllers(ctx context.Context, req *MinerSetControllersReq) (oldController []address.Address, err error) {
@@ -319,6 +360,21 @@ func (s *ServiceImpl) MinerSetControllers(ctx context.Context, req *MinerSetCont
 	}
 	oldController = minerInfo.ControlAddresses
 
+	setMsg, err := s.setControllersMsg(ctx, &minerInfo, req)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := s.PushMessageAndWait(ctx, setMsg, nil)
+	if err != nil {
+		return nil, fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
+	}
+
+	return oldController, nil
+}
+
+// setControllersMsg builds the message of the owner to replace the control addresses
+func (s *ServiceImpl) setControllersMsg(ctx context.Context, minerInfo *types.MinerInfo, req *MinerSetControllersReq) (*types.Message, error) {
 	newControllers := make([]address.Address, 0, len(req.NewControllers))
 	for _, c := range req.NewControllers {
 		id, err := s.Node.StateLookupID(ctx, c, types.EmptyTSK)
@@ -342,18 +398,13 @@ func (s *ServiceImpl) MinerSetControllers(ctx context.Context, req *MinerSetCont
 		return nil, fmt.Errorf("serialize params failed: %s", err)
 	}
 
-	msg, err := s.PushMessageAndWait(ctx, &types.Message{
+	return &types.Message{
 		From:   minerInfo.Owner,
 		To:     req.Miner,
 		Method: builtin.MethodsMiner.ChangeWorkerAddress,
 		Params: param,
 		Value:  big.Zero(),
-	}, nil)
-	if err != nil {
-		return nil, fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
-	}
-
-	return oldController, nil
+	}, nil
 }
 
 func (s *ServiceImpl) MinerSetBeneficiary(ctx context.Context, req *MinerSetBeneficiaryReq) (*types.PendingBeneficiaryChange, error) {
@@ -362,6 +413,31 @@ func (s *ServiceImpl) MinerSetBeneficiary(ctx context.Context, req *MinerSetBene
 		return nil, fmt.Errorf("get miner(%s) info failed: %s", req.Miner, err)
 	}
 
+	setMsg, err := s.setBeneficiaryMsg(ctx, &minerInfo, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// owner proposal
+	msg, err := s.PushMessageAndWait(ctx, setMsg, nil)
+	if err != nil {
+		return nil, fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
+	}
+
+	minerInfo, err = s.Node.StateMinerInfo(ctx, req.Miner, types.EmptyTSK)
+	if err != nil {
+		return nil, fmt.Errorf("get miner(%s) info failed: %s", req.Miner, err)
+	}
+
+	if minerInfo.PendingBeneficiaryTerm == nil {
+		return nil, fmt.Errorf("owner proposal beneficial change failed")
+	}
+
+	return minerInfo.PendingBeneficiaryTerm, nil
+}
+
+// setBeneficiaryMsg builds the message of the owner to propose the new beneficiary
+func (s *ServiceImpl) setBeneficiaryMsg(ctx context.Context, minerInfo *types.MinerInfo, req *MinerSetBeneficiaryReq) (*types.Message, error) {
 	newBeneficiary, err := s.Node.StateLookupID(ctx, req.NewBeneficiary, types.EmptyTSK)
 	if err != nil {
 		return nil, fmt.Errorf("get beneficiary(%s) id failed: %s", req.NewBeneficiary, err)
@@ -382,51 +458,51 @@ func (s *ServiceImpl) MinerSetBeneficiary(ctx context.Context, req *MinerSetBene
 		return nil, fmt.Errorf("serialize params failed: %s", err)
 	}
 
-	// owner proposal
-	msg, err := s.PushMessageAndWait(ctx, &types.Message{
+	return &types.Message{
 		From:   minerInfo.Owner,
 		To:     req.Miner,
 		Method: builtin.MethodsMiner.ChangeBeneficiary,
 		Params: param,
 		Value:  big.Zero(),
-	}, nil)
+	}, nil
+}
+
+func (s *ServiceImpl) MinerConfirmBeneficiary(ctx context.Context, req *MinerConfirmBeneficiaryReq) (confirmor address.Address, err error) {
+	minerInfo, err := s.Node.StateMinerInfo(ctx, req.Miner, types.EmptyTSK)
 	if err != nil {
-		return nil, fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
+		return address.Undef, fmt.Errorf("get miner(%s) info failed: %s", req.Miner, err)
 	}
 
-	minerInfo, err = s.Node.StateMinerInfo(ctx, req.Miner, types.EmptyTSK)
+	confirmMsg, err := s.confirmBeneficiaryMsg(ctx, &minerInfo, req)
 	if err != nil {
-		return nil, fmt.Errorf("get miner(%s) info failed: %s", req.Miner, err)
+		return address.Undef, err
 	}
 
-	if minerInfo.PendingBeneficiaryTerm == nil {
-		return nil, fmt.Errorf("owner proposal beneficial change failed")
+	msg, err := s.PushMessageAndWait(ctx, confirmMsg, nil)
+	if err != nil {
+		return address.Undef, fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
 	}
 
-	return minerInfo.PendingBeneficiaryTerm, nil
+	return confirmMsg.From, nil
 }
 
-func (s *ServiceImpl) MinerConfirmBeneficiary(ctx context.Context, req *MinerConfirmBeneficiaryReq) (confirmor address.Address, err error) {
-	minerInfo, err := s.Node.StateMinerInfo(ctx, req.Miner, types.EmptyTSK)
-	if err != nil {
-		return address.Undef, fmt.Errorf("get miner(%s) info failed: %s", req.Miner, err)
-	}
-
+// confirmBeneficiaryMsg builds the message of the current beneficiary or the nominee to confirm the beneficiary change
+func (s *ServiceImpl) confirmBeneficiaryMsg(ctx context.Context, minerInfo *types.MinerInfo, req *MinerConfirmBeneficiaryReq) (*types.Message, error) {
 	if minerInfo.PendingBeneficiaryTerm == nil {
-		return address.Undef, fmt.Errorf("miner(%s) no pending beneficiary", req.Miner)
+		return nil, fmt.Errorf("miner(%s) no pending beneficiary", req.Miner)
 	}
 	if minerInfo.PendingBeneficiaryTerm.NewBeneficiary != req.NewBeneficiary {
-		return address.Undef, fmt.Errorf("new beneficiary(%s) is not the same as proposed beneficiary(%s)", req.NewBeneficiary, minerInfo.PendingBeneficiaryTerm.NewBeneficiary)
+		return nil, fmt.Errorf("new beneficiary(%s) is not the same as proposed beneficiary(%s)", req.NewBeneficiary, minerInfo.PendingBeneficiaryTerm.NewBeneficiary)
 	}
 
 	sender := minerInfo.Beneficiary
 	if !req.ByNominee {
 		if minerInfo.PendingBeneficiaryTerm.ApprovedByBeneficiary {
-			return address.Undef, fmt.Errorf("proposal already approved by beneficiary(%s)", minerInfo.Beneficiary)
+			return nil, fmt.Errorf("proposal already approved by beneficiary(%s)", minerInfo.Beneficiary)
 		}
 	} else {
 		if minerInfo.PendingBeneficiaryTerm.ApprovedByNominee {
-			return address.Undef, fmt.Errorf("proposal already approved by nominee(%s)", minerInfo.PendingBeneficiaryTerm.NewBeneficiary)
+			return nil, fmt.Errorf("proposal already approved by nominee(%s)", minerInfo.PendingBeneficiaryTerm.NewBeneficiary)
 		}
 		sender = minerInfo.PendingBeneficiaryTerm.NewBeneficiary
 	}
@@ -437,21 +513,16 @@ func (s *ServiceImpl) MinerConfirmBeneficiary(ctx context.Context, req *MinerCon
 		NewExpiration:  minerInfo.PendingBeneficiaryTerm.NewExpiration,
 	})
 	if err != nil {
-		return address.Undef, fmt.Errorf("serialize params failed: %s", err)
+		return nil, fmt.Errorf("serialize params failed: %s", err)
 	}
 
-	msg, err := s.PushMessageAndWait(ctx, &types.Message{
+	return &types.Message{
 		From:   sender,
 		To:     req.Miner,
 		Method: builtin.MethodsMiner.ChangeBeneficiary,
 		Params: param,
 		Value:  big.Zero(),
-	}, nil)
-	if err != nil {
-		return address.Undef, fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
-	}
-
-	return sender, nil
+	}, nil
 }
 
 func (s *ServiceImpl) MinerGetDeadlines(ctx context.Context, mAddr address.Address) (*dline.Info, error) {
@@ -465,13 +536,30 @@ func (s *ServiceImpl) MinerWithdrawToBeneficiary(ctx context.Context, req *Miner
 		return big.Zero(), fmt.Errorf("get miner(%s) info failed: %s", req.Miner, err)
 	}
 
+	withdrawMsg, err := s.withdrawBalanceMsg(ctx, &minerInfo, req)
+	if err != nil {
+		return big.Zero(), err
+	}
+
+	msg, err := s.PushMessageAndWait(ctx, withdrawMsg, nil)
+
+	if err != nil {
+		return big.Zero(), fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
+	}
+
+	return req.Amount, nil
+}
+
+// withdrawBalanceMsg builds the message of the beneficiary to withdraw the available balance of miner,
+// all available balance is withdrawn if the amount is not set
+func (s *ServiceImpl) withdrawBalanceMsg(ctx context.Context, minerInfo *types.MinerInfo, req *MinerWithdrawBalanceReq) (*types.Message, error) {
 	available, err := s.Node.StateMinerAvailableBalance(ctx, req.Miner, types.EmptyTSK)
 	if err != nil {
-		return big.Zero(), fmt.Errorf("get miner(%s) available balance failed: %s", req.Miner, err)
+		return nil, fmt.Errorf("get miner(%s) available balance failed: %s", req.Miner, err)
 	}
 
 	if available.LessThan(req.Amount) {
-		return big.Zero(), fmt.Errorf("withdraw amount(%s) is greater than available balance(%s)", req.Amount, available)
+		return nil, fmt.Errorf("withdraw amount(%s) is greater than available balance(%s)", req.Amount, available)
 	}
 
 	if req.Amount.LessThanEqual(big.Zero()) {
@@ -482,22 +570,16 @@ func (s *ServiceImpl) MinerWithdrawToBeneficiary(ctx context.Context, req *Miner
 		AmountRequested: req.Amount,
 	})
 	if err != nil {
-		return big.Zero(), fmt.Errorf("serialize params failed: %s", err)
+		return nil, fmt.Errorf("serialize params failed: %s", err)
 	}
 
-	msg, err := s.PushMessageAndWait(ctx, &types.Message{
+	return &types.Message{
 		From:   minerInfo.Beneficiary,
 		To:     req.Miner,
 		Method: builtin.MethodsMiner.WithdrawBalance,
 		Params: param,
 		Value:  big.Zero(),
-	}, nil)
-
-	if err != nil {
-		return big.Zero(), fmt.Errorf("push message(%s) failed: %s", msg.ID, err)
-	}
-
-	return req.Amount, nil
+	}, nil
 }
 
 func (s *ServiceImpl) MinerWithdrawFromMarket(ctx context.Context, req *MinerWithdrawBalanceReq) (abi.TokenAmount, error) {
@@ -558,6 +640,97 @@ func (s *ServiceImpl) MinerWithdrawFromMarket(ctx context.Context, req *MinerWit
 	return req.Amount, nil
 }
 
+func (s *ServiceImpl) MarketAddBalance(ctx context.Context, req *MarketAddBalanceReq) (abi.TokenAmount, error) {
+	if req.Amount.Nil() || req.Amount.LessThanEqual(big.Zero()) {
+		return big.Zero(), fmt.Errorf("amount should be positive")
+	}
+	if req.From == address.Undef {
+		minerInfo, err := s.Node.StateMinerInfo(ctx, req.Miner, types.EmptyTSK)
+		if err != nil {
+			return big.Zero(), fmt.Errorf("get miner(%s) info failed: %s", req.Miner, err)
+		}
+		req.From = minerInfo.Worker
+	}
+
+	params, aErr := actors.SerializeParams(&req.Miner)
+	if aErr != nil {
+		return big.Zero(), fmt.Errorf("serialize params failed: %s", aErr)
+	}
+	// push the message by ourselves instead of droplet, so that it is checked by the spending policy of From
+	_, err := s.PushMessageAndWait(ctx, &types.Message{
+		From:   req.From,
+		To:     builtin.StorageMarketActorAddr,
+		Method: builtin.MethodsMarket.AddBalance,
+		Params: params,
+		Value:  req.Amount,
+	}, nil)
+	if err != nil {
+		return big.Zero(), fmt.Errorf("add balance to market failed: %s", err)
+	}
+
+	return req.Amount, nil
+}
+
+// unreservedDealStates are states of deals before StorageDealReserveProviderFunds,
+// the provider collateral of later deals is either in the reserved balance of droplet or locked on chain
+var unreservedDealStates = map[storagemarket.StorageDealStatus]struct{}{
+	storagemarket.StorageDealValidating:                   {},
+	storagemarket.StorageDealAcceptWait:                   {},
+	storagemarket.StorageDealProposalAccepted:             {},
+	storagemarket.StorageDealStartDataTransfer:            {},
+	storagemarket.StorageDealTransferQueued:               {},
+	storagemarket.StorageDealTransferring:                 {},
+	storagemarket.StorageDealProviderTransferAwaitRestart: {},
+	storagemarket.StorageDealWaitingForData:               {},
+	storagemarket.StorageDealVerifyData:                   {},
+}
+
+// pendingCollateral sums the provider collateral of deals whose collateral is not reserved yet
+func pendingCollateral(deals []marketTypes.MinerDeal) (int, abi.TokenAmount) {
+	count, collateral := 0, big.Zero()
+	for _, deal := range deals {
+		if _, ok := unreservedDealStates[deal.State]; !ok {
+			continue
+		}
+		count++
+		collateral = big.Add(collateral, deal.ClientDealProposal.Proposal.ProviderCollateral)
+	}
+	return count, collateral
+}
+
+func (s *ServiceImpl) MarketCollateralPlan(ctx context.Context, mAddr Address) (*MarketCollateralPlanResp, error) {
+	marketBalance, err := s.Node.StateMarketBalance(ctx, mAddr.Address, types.EmptyTSK)
+	if err != nil {
+		return nil, fmt.Errorf("get miner(%s) market balance failed: %s", mAddr.Address, err)
+	}
+	reserved, err := s.Market.MarketGetReserved(ctx, mAddr.Address)
+	if err != nil {
+		return nil, fmt.Errorf("get miner(%s) reserved balance failed: %s", mAddr.Address, err)
+	}
+
+	deals, err := s.listStorageDeals(ctx, mAddr.Address, &StorageDealListReq{})
+	if err != nil {
+		return nil, fmt.Errorf("list deals of %s failed: %s", mAddr.Address, err)
+	}
+
+	ret := &MarketCollateralPlanResp{
+		Miner:     mAddr.Address,
+		Escrow:    marketBalance.Escrow,
+		Locked:    marketBalance.Locked,
+		Reserved:  reserved,
+		Available: big.Subtract(big.Subtract(marketBalance.Escrow, marketBalance.Locked), reserved),
+		TopUp:     big.Zero(),
+	}
+	ret.PendingDeals, ret.PendingCollateral = pendingCollateral(deals)
+
+	if ret.Available.LessThan(ret.PendingCollateral) {
+		ret.TopUp = big.Sub(ret.PendingCollateral, ret.Available)
+		ret.Warning = fmt.Sprintf("available balance(%s) is less than the collateral(%s) needed by %d pending deals",
+			types.FIL(ret.Available), types.FIL(ret.PendingCollateral), ret.PendingDeals)
+	}
+	return ret, nil
+}
+
 func (s *ServiceImpl) SectorExtend(ctx context.Context, req SectorExtendReq) error {
 	var err error
 	rawParams := &types.ExtendSectorExpirationParams{}
@@ -632,10 +805,19 @@ func (s *ServiceImpl) SectorGet(ctx context.Context, req SectorGetReq) ([]*Secto
 		})
 	}
 
+	sectors := make([]*types.SectorOnChainInfo, 0, len(ret))
+	for _, r := range ret {
+		sectors = append(sectors, &r.SectorOnChainInfo)
+	}
+	claims := s.sectorClaimInfos(ctx, req.Miner, sectors)
+	for _, r := range ret {
+		r.SectorClaimInfo = claims[r.SectorNumber]
+	}
+
 	return ret, nil
 }
 
-func (s *ServiceImpl) SectorList(ctx context.Context, req SectorListReq) ([]*types.SectorOnChainInfo, error) {
+func (s *ServiceImpl) SectorList(ctx context.Context, req SectorListReq) ([]*SectorListItem, error) {
 	mAddr := req.Miner
 
 	// load miner state
@@ -666,7 +848,7 @@ func (s *ServiceImpl) SectorList(ctx context.Context, req SectorListReq) ([]*typ
 		return nil, fmt.Errorf("get iterator to range allocated sectors of miner(%s) failed: %s", mAddr, err)
 	}
 
-	ret := make([]*types.SectorOnChainInfo, 0)
+	ret := make([]*SectorListItem, 0)
 	sectorNums := make([]uint64, 0, pageSize)
 
 	n, err := iterator.Nth(uint64(start))
@@ -688,6 +870,7 @@ func (s *ServiceImpl) SectorList(ctx context.Context, req SectorListReq) ([]*typ
 	}
 	log.Infof("sector numbers of miner(%s): %v", mAddr, sectorNums)
 
+	sectors := make([]*types.SectorOnChainInfo, 0, len(sectorNums))
 	for _, num := range sectorNums {
 		sector, err := mst.GetSector(abi.SectorNumber(num))
 		if sector == nil {
@@ -696,12 +879,22 @@ func (s *ServiceImpl) SectorList(ctx context.Context, req SectorListReq) ([]*typ
 			} else {
 				log.Warnf("sector(%s) not found")
 			}
-			ret = append(ret, &types.SectorOnChainInfo{
-				SectorNumber: abi.SectorNumber(num),
+			ret = append(ret, &SectorListItem{
+				SectorOnChainInfo: types.SectorOnChainInfo{
+					SectorNumber: abi.SectorNumber(num),
+				},
 			})
 			continue
 		}
-		ret = append(ret, sector)
+		sectors = append(sectors, sector)
+		ret = append(ret, &SectorListItem{
+			SectorOnChainInfo: *sector,
+		})
+	}
+
+	claims := s.sectorClaimInfos(ctx, mAddr, sectors)
+	for _, item := range ret {
+		item.SectorClaimInfo = claims[item.SectorNumber]
 	}
 	return ret, nil
 }
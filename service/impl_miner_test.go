@@ -0,0 +1,66 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-fil-markets/storagemarket"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	marketTypes "github.com/filecoin-project/venus/venus-shared/types/market"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingCollateral(t *testing.T) {
+	deal := func(state storagemarket.StorageDealStatus, collateral int64) marketTypes.MinerDeal {
+		d := marketTypes.MinerDeal{State: state}
+		d.ClientDealProposal.Proposal.ProviderCollateral = big.NewInt(collateral)
+		return d
+	}
+
+	testCases := []struct {
+		name       string
+		deals      []marketTypes.MinerDeal
+		count      int
+		collateral abi.TokenAmount
+	}{
+		{name: "no deals", collateral: big.Zero()},
+		{
+			name: "before reserving funds",
+			deals: []marketTypes.MinerDeal{
+				deal(storagemarket.StorageDealAcceptWait, 1),
+				deal(storagemarket.StorageDealWaitingForData, 2),
+				deal(storagemarket.StorageDealVerifyData, 4),
+			},
+			count:      3,
+			collateral: big.NewInt(7),
+		},
+		{
+			name: "reserved or locked deals are not counted",
+			deals: []marketTypes.MinerDeal{
+				deal(storagemarket.StorageDealTransferring, 1),
+				deal(storagemarket.StorageDealReserveProviderFunds, 2),
+				deal(storagemarket.StorageDealProviderFunding, 4),
+				deal(storagemarket.StorageDealPublish, 8),
+				deal(storagemarket.StorageDealPublishing, 16),
+				deal(storagemarket.StorageDealActive, 32),
+			},
+			count:      1,
+			collateral: big.NewInt(1),
+		},
+		{
+			name: "failed deals are not counted",
+			deals: []marketTypes.MinerDeal{
+				deal(storagemarket.StorageDealError, 1),
+				deal(storagemarket.StorageDealRejecting, 2),
+			},
+			collateral: big.Zero(),
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			count, collateral := pendingCollateral(tt.deals)
+			assert.Equal(t, tt.count, count)
+			assert.True(t, tt.collateral.Equals(collateral), "want %s, got %s", tt.collateral, collateral)
+		})
+	}
+}
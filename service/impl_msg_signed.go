@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	msgTypes "github.com/filecoin-project/venus/venus-shared/types/messager"
+	"github.com/ipfs/go-cid"
+
+	"github.com/ipfs-force-community/venus-tool/utils"
+)
+
+const signedMsgFile = "signed_msg.json"
+
+// signedMsgIndex keeps the messages submitted by MsgSubmitSigned in a json file under the repo,
+// messager only tracks messages signed by itself, so these are looked up here by id, cid or sender and nonce,
+// the id of such a message is its signed cid
+type signedMsgIndex struct {
+	lk   sync.Mutex
+	path string
+	Msgs []*signedMsg
+}
+
+type signedMsg struct {
+	Cid         cid.Cid
+	Message     types.Message
+	SubmittedAt time.Time
+}
+
+func newSignedMsgIndex(path string) (*signedMsgIndex, error) {
+	idx := &signedMsgIndex{path: path}
+	if _, err := utils.LoadJSON(path, idx); err != nil {
+		return nil, fmt.Errorf("load signed message file failed: %s", err)
+	}
+	return idx, nil
+}
+
+func (idx *signedMsgIndex) add(c cid.Cid, msg *types.Message, now time.Time) error {
+	idx.lk.Lock()
+	defer idx.lk.Unlock()
+
+	for _, m := range idx.Msgs {
+		if m.Cid.Equals(c) {
+			return nil
+		}
+	}
+	idx.Msgs = append(idx.Msgs, &signedMsg{Cid: c, Message: *msg, SubmittedAt: now})
+	return utils.SaveJSON(idx.path, idx)
+}
+
+// find returns the messages matched in the order of submission
+func (idx *signedMsgIndex) find(match func(m *signedMsg) bool) []signedMsg {
+	idx.lk.Lock()
+	defer idx.lk.Unlock()
+
+	var ret []signedMsg
+	for _, m := range idx.Msgs {
+		if match(m) {
+			ret = append(ret, *m)
+		}
+	}
+	return ret
+}
+
+// get looks up the message by signed or unsigned cid
+func (idx *signedMsgIndex) get(c cid.Cid) (*signedMsg, bool) {
+	found := idx.find(func(m *signedMsg) bool { return m.Cid.Equals(c) || m.Message.Cid().Equals(c) })
+	if len(found) == 0 {
+		return nil, false
+	}
+	return &found[0], true
+}
+
+// signedMsgToMsg fills the execution result of the message submitted signed from the chain
+func (s *ServiceImpl) signedMsgToMsg(ctx context.Context, m *signedMsg) (*msgTypes.Message, error) {
+	msg, err := s.chainMsgToMsg(ctx, m.Cid, &m.Message)
+	if err != nil {
+		return nil, err
+	}
+	msg.ID = m.Cid.String()
+	msg.CreatedAt = m.SubmittedAt
+	return msg, nil
+}
+
+// getMsgByID looks up the message by id from messager, and falls back to the messages submitted signed
+func (s *ServiceImpl) getMsgByID(ctx context.Context, id string) (*msgTypes.Message, error) {
+	msg, err := s.Messager.GetMessageByUid(ctx, id)
+	if err == nil {
+		return msg, nil
+	}
+	if c, cerr := cid.Decode(id); cerr == nil {
+		if m, ok := s.signedMsgs.get(c); ok {
+			return s.signedMsgToMsg(ctx, m)
+		}
+	}
+	return nil, err
+}
+
+// getSignedMsgByNonce looks up the message submitted signed by the sender and nonce
+func (s *ServiceImpl) getSignedMsgByNonce(ctx context.Context, from address.Address, nonce uint64) (*msgTypes.Message, bool, error) {
+	found := s.signedMsgs.find(func(m *signedMsg) bool {
+		return m.Message.From == from && m.Message.Nonce == nonce
+	})
+	if len(found) == 0 {
+		return nil, false, nil
+	}
+	// the last one replaces the earlier ones with the same nonce
+	msg, err := s.signedMsgToMsg(ctx, &found[len(found)-1])
+	return msg, true, err
+}
+
+// listSignedMsgs lists the messages submitted signed by any of from, all senders if from is empty
+func (s *ServiceImpl) listSignedMsgs(ctx context.Context, from []address.Address) ([]*msgTypes.Message, error) {
+	senders := make(map[address.Address]struct{}, len(from))
+	for _, f := range from {
+		senders[f] = struct{}{}
+	}
+	found := s.signedMsgs.find(func(m *signedMsg) bool {
+		_, ok := senders[m.Message.From]
+		return len(senders) == 0 || ok
+	})
+
+	ret := make([]*msgTypes.Message, 0, len(found))
+	for i := range found {
+		msg, err := s.signedMsgToMsg(ctx, &found[i])
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, msg)
+	}
+	return ret, nil
+}
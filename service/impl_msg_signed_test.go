@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/venus/venus-shared/api/messager"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	msgTypes "github.com/filecoin-project/venus/venus-shared/types/messager"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ipfs-force-community/venus-tool/dep"
+)
+
+// emptyMessager tracks no message
+type emptyMessager struct {
+	messager.IMessager
+}
+
+func (m *emptyMessager) GetMessageByUid(ctx context.Context, id string) (*msgTypes.Message, error) {
+	return nil, fmt.Errorf("message not found")
+}
+
+func (m *emptyMessager) GetMessageByFromAndNonce(ctx context.Context, from address.Address, nonce uint64) (*msgTypes.Message, error) {
+	return nil, fmt.Errorf("message not found")
+}
+
+func (m *emptyMessager) GetMessageBySignedCid(ctx context.Context, id cid.Cid) (*msgTypes.Message, error) {
+	return nil, fmt.Errorf("message not found")
+}
+
+func (m *emptyMessager) GetMessageByUnsignedCid(ctx context.Context, id cid.Cid) (*msgTypes.Message, error) {
+	return nil, fmt.Errorf("message not found")
+}
+
+// emptyWallet has no address
+type emptyWallet struct {
+	dep.IWallet
+}
+
+func (w *emptyWallet) WalletList(ctx context.Context) ([]address.Address, error) {
+	return nil, nil
+}
+
+func TestSignedMsgQuery(t *testing.T) {
+	fromA, err := address.NewIDAddress(1000)
+	assert.NoError(t, err)
+	fromB, err := address.NewIDAddress(1001)
+	assert.NoError(t, err)
+	to, err := address.NewIDAddress(1002)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), signedMsgFile)
+	idx, err := newSignedMsgIndex(path)
+	assert.NoError(t, err)
+	msgs := []*types.Message{
+		{From: fromA, To: to, Nonce: 1},
+		{From: fromB, To: to, Nonce: 1},
+		{From: fromA, To: to, Nonce: 2},
+	}
+	for _, msg := range msgs {
+		// the signed cid is the unsigned one for bls messages
+		assert.NoError(t, idx.add(msg.Cid(), msg, time.Now()))
+	}
+	// adding again is ignored
+	assert.NoError(t, idx.add(msgs[0].Cid(), msgs[0], time.Now()))
+
+	// the index is reloaded from the file
+	idx, err = newSignedMsgIndex(path)
+	assert.NoError(t, err)
+	assert.Len(t, idx.Msgs, len(msgs))
+
+	unknown := (&types.Message{From: fromB, To: to, Nonce: 9}).Cid()
+	msgCid := msgs[2].Cid()
+	testCases := []struct {
+		name       string
+		req        MsgQueryReq
+		wantErr    bool
+		wantNonces []uint64
+	}{
+		{name: "by id", req: MsgQueryReq{ID: msgs[1].Cid().String()}, wantNonces: []uint64{1}},
+		{name: "by unknown id", req: MsgQueryReq{ID: unknown.String()}, wantErr: true},
+		{name: "by cid", req: MsgQueryReq{Cid: &msgCid}, wantNonces: []uint64{2}},
+		{
+			name:       "by sender and nonce",
+			req:        MsgQueryReq{MsgQueryParams: msgTypes.MsgQueryParams{From: []address.Address{fromA}}, Nonce: 2},
+			wantNonces: []uint64{2},
+		},
+		{
+			name:    "by sender and unknown nonce",
+			req:     MsgQueryReq{MsgQueryParams: msgTypes.MsgQueryParams{From: []address.Address{fromA}}, Nonce: 3},
+			wantErr: true,
+		},
+		{name: "list all", req: MsgQueryReq{IsSigned: true}, wantNonces: []uint64{1, 1, 2}},
+		{
+			name:       "list by sender",
+			req:        MsgQueryReq{MsgQueryParams: msgTypes.MsgQueryParams{From: []address.Address{fromA}}, IsSigned: true},
+			wantNonces: []uint64{1, 2},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ServiceImpl{Messager: &emptyMessager{}, Node: &chainMsgNode{}, Wallet: &emptyWallet{}, signedMsgs: idx}
+			got, err := s.MsgQuery(context.Background(), &tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			nonces := make([]uint64, 0, len(got))
+			for _, msg := range got {
+				nonces = append(nonces, msg.Nonce)
+				// the cid is the id of message submitted signed
+				assert.Equal(t, msg.UnsignedCid.String(), msg.ID)
+				assert.Equal(t, msgTypes.OnChainMsg, msg.State)
+			}
+			assert.Equal(t, tt.wantNonces, nonces)
+		})
+	}
+}
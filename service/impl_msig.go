@@ -4,63 +4,97 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/filecoin-project/go-address"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/big"
 	init2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/init"
+	"github.com/filecoin-project/venus/pkg/constants"
+	"github.com/filecoin-project/venus/venus-shared/actors/builtin"
+	"github.com/filecoin-project/venus/venus-shared/actors/builtin/multisig"
 	"github.com/filecoin-project/venus/venus-shared/types"
+	"golang.org/x/crypto/blake2b"
+
+	"github.com/ipfs-force-community/venus-tool/utils"
 )
 
-func (s *ServiceImpl) MsigCreate(ctx context.Context, req *MultisigCreateReq) (address.Address, error) {
+func (s *ServiceImpl) MsigCreate(ctx context.Context, req *MultisigCreateReq) (*MultisigCreateResp, error) {
 	var err error
 	// check params
 	if req.ApprovalsThreshold < 1 {
-		return address.Undef, fmt.Errorf("threshold(%d) must be greater than 1", req.ApprovalsThreshold)
+		return nil, fmt.Errorf("threshold(%d) must be greater than 1", req.ApprovalsThreshold)
 	}
 
 	if uint64(len(req.Signers)) < req.ApprovalsThreshold {
-		return address.Undef, fmt.Errorf("signers(%d) must be greater than threshold(%d)", len(req.Signers), req.ApprovalsThreshold)
+		return nil, fmt.Errorf("signers(%d) must be greater than threshold(%d)", len(req.Signers), req.ApprovalsThreshold)
 	}
 
 	if req.Value.LessThan(big.Zero()) {
-		return address.Undef, fmt.Errorf("value(%s) must be equal or greater than 0", req.Value)
+		return nil, fmt.Errorf("value(%s) must be equal or greater than 0", req.Value)
 	}
 
 	if req.LockedDuration < 0 {
-		return address.Undef, fmt.Errorf("unlockAt(%d) must be equal or greater than 0", req.LockedDuration)
+		return nil, fmt.Errorf("unlockAt(%d) must be equal or greater than 0", req.LockedDuration)
+	}
+
+	if req.StartEpoch < 0 {
+		return nil, fmt.Errorf("start epoch(%d) must be equal or greater than 0", req.StartEpoch)
 	}
 
-	// check signers
+	// check signers, they should be ID addresses or public key addresses, which may be not on chain yet
 	set := make(map[address.Address]struct{})
 	for _, signer := range req.Signers {
 		id, err := s.Node.StateLookupID(ctx, signer, types.EmptyTSK)
 		if err != nil {
-			return address.Undef, fmt.Errorf("lookup signer(%s) failed: %s", signer, err)
+			switch signer.Protocol() {
+			case address.SECP256K1, address.BLS, address.Delegated:
+				id = signer
+			default:
+				return nil, fmt.Errorf("lookup signer(%s) failed: %s", signer, err)
+			}
 		}
 		if _, ok := set[id]; ok {
-			return address.Undef, fmt.Errorf("duplicate signer(%s)", signer)
+			return nil, fmt.Errorf("duplicate signer(%s)", signer)
 		} else {
 			set[id] = struct{}{}
 		}
 	}
 
-	msgPrototype, err := s.Multisig.MsigCreate(ctx, req.ApprovalsThreshold, req.Signers, req.LockedDuration, req.Value, req.From, big.Zero())
+	msgPrototype, err := s.Multisig.MsigCreate(ctx, req.ApprovalsThreshold, req.Signers, req.StartEpoch, req.LockedDuration, req.Value, req.From, big.Zero())
 	if err != nil {
-		return address.Undef, fmt.Errorf("create multisig Prototype failed: %s", err)
+		return nil, fmt.Errorf("create multisig Prototype failed: %s", err)
+	}
+
+	ret := &MultisigCreateResp{
+		DryRun:  req.DryRun,
+		Message: msgPrototype.Message,
+	}
+	var execParams init2.ExecParams
+	if err := execParams.UnmarshalCBOR(bytes.NewReader(msgPrototype.Message.Params)); err != nil {
+		return nil, fmt.Errorf("unmarshal exec params failed: %s", err)
+	}
+	if err := ret.Params.UnmarshalCBOR(bytes.NewReader(execParams.ConstructorParams)); err != nil {
+		return nil, fmt.Errorf("unmarshal multisig constructor params failed: %s", err)
+	}
+	if req.DryRun {
+		return ret, nil
 	}
 
 	msg, err := s.PushMessageAndWait(ctx, &msgPrototype.Message, nil)
 	if err != nil {
-		return address.Undef, fmt.Errorf("push message failed: %s", err)
+		return nil, fmt.Errorf("push message failed: %s", err)
 	}
 
 	var execRet init2.ExecReturn
 	if err := execRet.UnmarshalCBOR(bytes.NewReader(msg.Receipt.Return)); err != nil {
-		return address.Undef, fmt.Errorf("unmarshal multisig create exec return failed: %s", err)
+		return nil, fmt.Errorf("unmarshal multisig create exec return failed: %s", err)
 	}
+	ret.IDAddress = execRet.IDAddress
+	ret.RobustAddress = execRet.RobustAddress
 
-	return execRet.RobustAddress, nil
+	return ret, nil
 }
 
 func (s *ServiceImpl) MsigInfo(ctx context.Context, msig address.Address) (*types.MsigInfo, error) {
@@ -72,6 +106,109 @@ func (s *ServiceImpl) MsigInfo(ctx context.Context, msig address.Address) (*type
 	return info, nil
 }
 
+// msigVestingPeriod is the length of the periods in the vesting timeline
+const msigVestingPeriod = 30 * builtin.EpochsInDay
+
+func (s *ServiceImpl) MsigVesting(ctx context.Context, msig address.Address) (*MsigVestingResp, error) {
+	head, err := s.Node.ChainHead(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get chain head failed: %s", err)
+	}
+	vesting, err := s.Multisig.MsigGetVestingSchedule(ctx, msig, head.Key())
+	if err != nil {
+		return nil, fmt.Errorf("get vesting schedule failed: %s", err)
+	}
+	act, err := s.Node.StateGetActor(ctx, msig, head.Key())
+	if err != nil {
+		return nil, fmt.Errorf("get actor of %s failed: %s", msig, err)
+	}
+	available, err := s.Multisig.MsigGetAvailableBalance(ctx, msig, head.Key())
+	if err != nil {
+		return nil, fmt.Errorf("get available balance failed: %s", err)
+	}
+
+	ret := &MsigVestingResp{
+		MsigVesting: vesting,
+		Height:      head.Height(),
+		Balance:     act.Balance,
+		Available:   available,
+		Locked:      big.Sub(act.Balance, available),
+	}
+
+	epochTime := func(epoch abi.ChainEpoch) time.Time {
+		return time.Unix(int64(head.MinTimestamp())+int64(epoch-head.Height())*int64(constants.MainNetBlockDelaySecs), 0)
+	}
+	vestEnd := vesting.StartEpoch + vesting.UnlockDuration
+	for start := head.Height(); vesting.UnlockDuration > 0 && start < vestEnd; start += msigVestingPeriod {
+		end := start + msigVestingPeriod
+		if end > vestEnd {
+			end = vestEnd
+		}
+		locked := msigLockedAt(vesting, end)
+		ret.Timeline = append(ret.Timeline, MsigVestingPeriod{
+			Start:     start,
+			End:       end,
+			StartTime: epochTime(start),
+			EndTime:   epochTime(end),
+			Unlocked:  big.Sub(msigLockedAt(vesting, start), locked),
+			Locked:    locked,
+		})
+	}
+
+	return ret, nil
+}
+
+// msigLockedAt computes the locked balance at the epoch in the same way as the multisig actor
+func msigLockedAt(vesting types.MsigVesting, epoch abi.ChainEpoch) abi.TokenAmount {
+	elapsed := epoch - vesting.StartEpoch
+	if elapsed < 0 {
+		return vesting.InitialBalance
+	}
+	if elapsed >= vesting.UnlockDuration {
+		return big.Zero()
+	}
+	remaining := big.NewInt(int64(vesting.UnlockDuration - elapsed))
+	duration := big.NewInt(int64(vesting.UnlockDuration))
+	// round up so that the locked amount is never underestimated
+	numerator := big.Add(big.Mul(vesting.InitialBalance, remaining), big.Sub(duration, big.NewInt(1)))
+	return big.Div(numerator, duration)
+}
+
+func (s *ServiceImpl) MsigAvailable(ctx context.Context, msig address.Address) (abi.TokenAmount, error) {
+	available, err := s.Multisig.MsigGetAvailableBalance(ctx, msig, types.EmptyTSK)
+	if err != nil {
+		return big.Zero(), fmt.Errorf("get available balance failed: %s", err)
+	}
+	return available, nil
+}
+
+func (s *ServiceImpl) MsigVested(ctx context.Context, req *MsigVestedReq) (abi.TokenAmount, error) {
+	head, err := s.Node.ChainHead(ctx)
+	if err != nil {
+		return big.Zero(), fmt.Errorf("get chain head failed: %s", err)
+	}
+	to := head
+	if req.To > 0 && req.To < head.Height() {
+		to, err = s.Node.ChainGetTipSetByHeight(ctx, req.To, head.Key())
+		if err != nil {
+			return big.Zero(), fmt.Errorf("get tipset at %d failed: %s", req.To, err)
+		}
+	}
+	if req.From > to.Height() {
+		return big.Zero(), fmt.Errorf("from(%d) must not be after to(%d)", req.From, to.Height())
+	}
+	from, err := s.Node.ChainGetTipSetByHeight(ctx, req.From, head.Key())
+	if err != nil {
+		return big.Zero(), fmt.Errorf("get tipset at %d failed: %s", req.From, err)
+	}
+
+	vested, err := s.Multisig.MsigGetVested(ctx, req.Msig, from.Key(), to.Key())
+	if err != nil {
+		return big.Zero(), fmt.Errorf("get vested amount failed: %s", err)
+	}
+	return vested, nil
+}
+
 func (s *ServiceImpl) MsigPropose(ctx context.Context, req *MultisigProposeReq) (*types.ProposeReturn, error) {
 	var err error
 
@@ -121,17 +258,181 @@ func (s *ServiceImpl) MsigPropose(ctx context.Context, req *MultisigProposeReq)
 	return &msgReturn, nil
 }
 
-func (s *ServiceImpl) MsigListPropose(ctx context.Context, msig address.Address) ([]*types.MsigTransaction, error) {
+func (s *ServiceImpl) MsigProposeCall(ctx context.Context, req *MsigProposeCallReq) (*MsigProposeCallResp, error) {
+	var (
+		calls int
+		miner address.Address
+		build func(minerInfo *types.MinerInfo) (*types.Message, error)
+	)
+	if r := req.SetOwner; r != nil {
+		calls, miner = calls+1, r.Miner
+		build = func(mi *types.MinerInfo) (*types.Message, error) { return s.setOwnerMsg(ctx, mi, r) }
+	}
+	if r := req.ConfirmOwner; r != nil {
+		calls, miner = calls+1, r.Miner
+		build = func(mi *types.MinerInfo) (*types.Message, error) { return s.confirmOwnerMsg(ctx, mi, r) }
+	}
+	if r := req.SetWorker; r != nil {
+		calls, miner = calls+1, r.Miner
+		build = func(mi *types.MinerInfo) (*types.Message, error) { return s.setWorkerMsg(ctx, mi, r) }
+	}
+	if r := req.ConfirmWorker; r != nil {
+		calls, miner = calls+1, r.Miner
+		build = func(mi *types.MinerInfo) (*types.Message, error) { return s.confirmWorkerMsg(ctx, mi, r) }
+	}
+	if r := req.SetControllers; r != nil {
+		calls, miner = calls+1, r.Miner
+		build = func(mi *types.MinerInfo) (*types.Message, error) { return s.setControllersMsg(ctx, mi, r) }
+	}
+	if r := req.SetBeneficiary; r != nil {
+		calls, miner = calls+1, r.Miner
+		build = func(mi *types.MinerInfo) (*types.Message, error) { return s.setBeneficiaryMsg(ctx, mi, r) }
+	}
+	if r := req.ConfirmBeneficiary; r != nil {
+		calls, miner = calls+1, r.Miner
+		build = func(mi *types.MinerInfo) (*types.Message, error) { return s.confirmBeneficiaryMsg(ctx, mi, r) }
+	}
+	if r := req.WithdrawBalance; r != nil {
+		calls, miner = calls+1, r.Miner
+		build = func(mi *types.MinerInfo) (*types.Message, error) { return s.withdrawBalanceMsg(ctx, mi, r) }
+	}
+	if calls != 1 {
+		return nil, fmt.Errorf("exactly one call should be set, got %d", calls)
+	}
+
+	_, err := s.Node.StateLookupID(ctx, req.From, types.EmptyTSK)
+	if err != nil {
+		return nil, fmt.Errorf("lookup proposer(%s) failed: %s", req.From, err)
+	}
+
+	minerInfo, err := s.Node.StateMinerInfo(ctx, miner, types.EmptyTSK)
+	if err != nil {
+		return nil, fmt.Errorf("get miner(%s) info failed: %s", miner, err)
+	}
+	inner, err := build(&minerInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	// the multisig must be the one the miner actor requires to send the call
+	msigID, err := s.Node.StateLookupID(ctx, req.Msig, types.EmptyTSK)
+	if err != nil {
+		return nil, fmt.Errorf("lookup multisig(%s) failed: %s", req.Msig, err)
+	}
+	senderID, err := s.Node.StateLookupID(ctx, inner.From, types.EmptyTSK)
+	if err != nil {
+		return nil, fmt.Errorf("lookup sender(%s) failed: %s", inner.From, err)
+	}
+	if senderID != msigID {
+		return nil, fmt.Errorf("the call must be sent by %s, not multisig %s", inner.From, req.Msig)
+	}
+
+	msgPrototype, err := s.Multisig.MsigPropose(ctx, req.Msig, inner.To, inner.Value, req.From, uint64(inner.Method), inner.Params)
+	if err != nil {
+		return nil, fmt.Errorf("create multisig propose Prototype failed: %s", err)
+	}
+
+	msg, err := s.PushMessageAndWait(ctx, &msgPrototype.Message, nil)
+	if err != nil {
+		return nil, fmt.Errorf("push message failed: %s", err)
+	}
+
+	ret := &MsigProposeCallResp{
+		To:     inner.To,
+		Value:  inner.Value,
+		Method: inner.Method,
+		Params: inner.Params,
+	}
+	err = ret.ProposeReturn.UnmarshalCBOR(bytes.NewReader(msg.Receipt.Return))
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal propose return failed: %s", err)
+	}
+
+	return ret, nil
+}
+
+func (s *ServiceImpl) MsigListPropose(ctx context.Context, msig address.Address) ([]*MsigTransaction, error) {
 	var err error
 
-	ret, err := s.Multisig.MsigGetPending(ctx, msig, types.EmptyTSK)
+	pending, err := s.Multisig.MsigGetPending(ctx, msig, types.EmptyTSK)
 	if err != nil {
 		return nil, fmt.Errorf("create multisig get pending Prototype failed: %s", err)
 	}
 
+	info, err := s.Multisig.StateMsigInfo(ctx, msig, types.EmptyTSK)
+	if err != nil {
+		return nil, fmt.Errorf("get multisig info failed: %s", err)
+	}
+
+	ret := make([]*MsigTransaction, 0, len(pending))
+	for _, txn := range pending {
+		ret = append(ret, s.decodeMsigTransaction(ctx, txn, info.ApprovalsThreshold))
+	}
+
 	return ret, nil
 }
 
+// decodeMsigTransaction resolves the target actor type, method name and params of the pending transaction
+func (s *ServiceImpl) decodeMsigTransaction(ctx context.Context, txn *types.MsigTransaction, threshold uint64) *MsigTransaction {
+	ret := &MsigTransaction{
+		MsigTransaction: *txn,
+		Threshold:       threshold,
+	}
+
+	// the proposer is always the first approver
+	if len(txn.Approved) > 0 {
+		ret.Proposer = txn.Approved[0]
+		hash, err := msigProposalHash(&multisig.ProposalHashData{
+			Requester: ret.Proposer,
+			To:        txn.To,
+			Value:     txn.Value,
+			Method:    txn.Method,
+			Params:    txn.Params,
+		})
+		if err != nil {
+			log.Warnf("compute hash of transaction(%d) failed: %s", txn.ID, err)
+		}
+		ret.Hash = hash
+	}
+
+	if txn.Method == builtin.MethodSend {
+		ret.MethodName = "Send"
+	}
+	act, err := s.Node.StateGetActor(ctx, txn.To, types.EmptyTSK)
+	if err != nil {
+		ret.DecodeError = fmt.Sprintf("get actor %s failed: %s", txn.To, err)
+		return ret
+	}
+	ret.ActorType = builtin.ActorNameByCode(act.Code)
+
+	methodMeta, err := utils.GetMethodMeta(act.Code, txn.Method)
+	if err != nil {
+		ret.DecodeError = err.Error()
+		return ret
+	}
+	ret.MethodName = methodMeta.Name
+
+	if len(txn.Params) > 0 {
+		params, err := decodeParams(methodMeta.Params, txn.Params)
+		if err != nil {
+			ret.DecodeError = fmt.Sprintf("decode params failed: %s", err)
+			return ret
+		}
+		ret.ParamsInJson = params
+	}
+	return ret
+}
+
+// msigProposalHash computes the hash of the proposal in the same way as the multisig actor
+func msigProposalHash(data *multisig.ProposalHashData) ([]byte, error) {
+	b, err := data.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	hash := blake2b.Sum256(b)
+	return hash[:], nil
+}
+
 func (s *ServiceImpl) MsigAddSigner(ctx context.Context, req *MultisigChangeSignerReq) (*types.ProposeReturn, error) {
 	var err error
 
@@ -213,6 +514,96 @@ func (s *ServiceImpl) MsigSwapSigner(ctx context.Context, req *MultisigSwapSigne
 	return &msgReturn, nil
 }
 
+func (s *ServiceImpl) MsigChangeThreshold(ctx context.Context, req *MultisigChangeThresholdReq) (*types.ProposeReturn, error) {
+	var err error
+
+	_, err = s.Node.StateLookupID(ctx, req.Proposer, types.EmptyTSK)
+	if err != nil {
+		return nil, fmt.Errorf("lookup proposer(%s) failed: %s", req.Proposer, err)
+	}
+
+	info, err := s.Multisig.StateMsigInfo(ctx, req.Msig, types.EmptyTSK)
+	if err != nil {
+		return nil, fmt.Errorf("get multisig info failed: %s", err)
+	}
+	if req.NewThreshold < 1 {
+		return nil, fmt.Errorf("threshold(%d) must be at least 1", req.NewThreshold)
+	}
+	if req.NewThreshold > uint64(len(info.Signers)) {
+		return nil, fmt.Errorf("threshold(%d) must not exceed the number of signers(%d)", req.NewThreshold, len(info.Signers))
+	}
+	if req.NewThreshold == info.ApprovalsThreshold {
+		return nil, fmt.Errorf("threshold is already %d", req.NewThreshold)
+	}
+
+	msgPrototype, err := s.Multisig.MsigChangeThresholdPropose(ctx, req.Msig, req.Proposer, req.NewThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("create multisig change threshold propose Prototype failed: %s", err)
+	}
+
+	msg, err := s.PushMessageAndWait(ctx, &msgPrototype.Message, nil)
+	if err != nil {
+		return nil, fmt.Errorf("push message failed: %s", err)
+	}
+
+	var msgReturn types.ProposeReturn
+	err = msgReturn.UnmarshalCBOR(bytes.NewReader(msg.Receipt.Return))
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal change threshold propose return failed: %s", err)
+	}
+
+	return &msgReturn, nil
+}
+
+func (s *ServiceImpl) MsigLockBalance(ctx context.Context, req *MultisigLockBalanceReq) (*types.ProposeReturn, error) {
+	var err error
+
+	_, err = s.Node.StateLookupID(ctx, req.Proposer, types.EmptyTSK)
+	if err != nil {
+		return nil, fmt.Errorf("lookup proposer(%s) failed: %s", req.Proposer, err)
+	}
+
+	if req.UnlockDuration <= 0 {
+		return nil, fmt.Errorf("unlock duration(%d) must be greater than 0", req.UnlockDuration)
+	}
+	if req.Amount.Nil() {
+		return nil, fmt.Errorf("amount is required")
+	}
+	if req.Amount.LessThanEqual(big.Zero()) {
+		return nil, fmt.Errorf("amount(%s) must be greater than 0", req.Amount)
+	}
+
+	info, err := s.Multisig.StateMsigInfo(ctx, req.Msig, types.EmptyTSK)
+	if err != nil {
+		return nil, fmt.Errorf("get multisig info failed: %s", err)
+	}
+	// the multisig actor only allows to lock balance once
+	if info.UnlockDuration != 0 {
+		return nil, fmt.Errorf("balance of %s is already locked until epoch %d", req.Msig, info.StartEpoch+info.UnlockDuration)
+	}
+	if req.Amount.GreaterThan(info.CurrentBalance) {
+		return nil, fmt.Errorf("amount(%s) exceeds the balance(%s)", types.FIL(req.Amount), types.FIL(info.CurrentBalance))
+	}
+
+	msgPrototype, err := s.Multisig.MsigLockPropose(ctx, req.Msig, req.Proposer, req.StartEpoch, req.UnlockDuration, req.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("create multisig lock propose Prototype failed: %s", err)
+	}
+
+	msg, err := s.PushMessageAndWait(ctx, &msgPrototype.Message, nil)
+	if err != nil {
+		return nil, fmt.Errorf("push message failed: %s", err)
+	}
+
+	var msgReturn types.ProposeReturn
+	err = msgReturn.UnmarshalCBOR(bytes.NewReader(msg.Receipt.Return))
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal lock propose return failed: %s", err)
+	}
+
+	return &msgReturn, nil
+}
+
 func (s *ServiceImpl) MsigApprove(ctx context.Context, req *MultisigApproveReq) (*types.ApproveReturn, error) {
 	var err error
 
@@ -221,7 +612,12 @@ func (s *ServiceImpl) MsigApprove(ctx context.Context, req *MultisigApproveReq)
 		return nil, fmt.Errorf("lookup proposer(%s) failed: %s", req.Proposer, err)
 	}
 
-	msgPrototype, err := s.Multisig.MsigApprove(ctx, req.Msig, req.TxID, req.Proposer)
+	txn, err := s.checkMsigTransaction(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	msgPrototype, err := s.Multisig.MsigApproveTxnHash(ctx, req.Msig, req.TxID, txn.Approved[0], txn.To, txn.Value, req.Proposer, uint64(txn.Method), txn.Params)
 	if err != nil {
 		return nil, fmt.Errorf("create multisig approve Prototype failed: %s", err)
 	}
@@ -248,7 +644,12 @@ func (s *ServiceImpl) MsigCancel(ctx context.Context, req *MultisigCancelReq) er
 		return fmt.Errorf("lookup proposer(%s) failed: %s", req.Proposer, err)
 	}
 
-	msgPrototype, err := s.Multisig.MsigCancel(ctx, req.Msig, req.TxID, req.Proposer)
+	txn, err := s.checkMsigTransaction(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	msgPrototype, err := s.Multisig.MsigCancelTxnHash(ctx, req.Msig, req.TxID, txn.To, txn.Value, req.Proposer, uint64(txn.Method), txn.Params)
 	if err != nil {
 		return fmt.Errorf("create multisig cancel Prototype failed: %s", err)
 	}
@@ -260,3 +661,66 @@ func (s *ServiceImpl) MsigCancel(ctx context.Context, req *MultisigCancelReq) er
 
 	return nil
 }
+
+// checkMsigTransaction finds the pending transaction and checks it against the expected proposal and hash,
+// approvals and cancellations are sent with the hash of the returned transaction,
+// so the message fails on chain if the proposal is replaced before it is executed
+func (s *ServiceImpl) checkMsigTransaction(ctx context.Context, req *MultisigTransactionReq) (*types.MsigTransaction, error) {
+	pending, err := s.Multisig.MsigGetPending(ctx, req.Msig, types.EmptyTSK)
+	if err != nil {
+		return nil, fmt.Errorf("get pending transactions of %s failed: %s", req.Msig, err)
+	}
+	var txn *types.MsigTransaction
+	for _, p := range pending {
+		if uint64(p.ID) == req.TxID {
+			txn = p
+			break
+		}
+	}
+	if txn == nil {
+		return nil, fmt.Errorf("transaction(%d) not found in pending transactions of %s", req.TxID, req.Msig)
+	}
+	if len(txn.Approved) == 0 {
+		return nil, fmt.Errorf("transaction(%d) of %s has no proposer", req.TxID, req.Msig)
+	}
+
+	if expect := req.Expect; expect != nil {
+		var diffs []string
+		if expect.To != txn.To {
+			toID, err := s.Node.StateLookupID(ctx, expect.To, types.EmptyTSK)
+			if err != nil || toID != txn.To {
+				diffs = append(diffs, fmt.Sprintf("to %s, expected %s", txn.To, expect.To))
+			}
+		}
+		if !txn.Value.Equals(expect.Value) {
+			diffs = append(diffs, fmt.Sprintf("value %s, expected %s", types.FIL(txn.Value), types.FIL(expect.Value)))
+		}
+		if txn.Method != expect.Method {
+			diffs = append(diffs, fmt.Sprintf("method %d, expected %d", txn.Method, expect.Method))
+		}
+		if !bytes.Equal(txn.Params, expect.Params) {
+			diffs = append(diffs, fmt.Sprintf("params %x, expected %x", txn.Params, expect.Params))
+		}
+		if len(diffs) > 0 {
+			return nil, fmt.Errorf("transaction(%d) of %s differs from expected: %s", req.TxID, req.Msig, strings.Join(diffs, "; "))
+		}
+	}
+
+	if len(req.Hash) > 0 {
+		hash, err := msigProposalHash(&multisig.ProposalHashData{
+			Requester: txn.Approved[0],
+			To:        txn.To,
+			Value:     txn.Value,
+			Method:    txn.Method,
+			Params:    txn.Params,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("compute hash of transaction(%d) failed: %s", req.TxID, err)
+		}
+		if !bytes.Equal(hash, req.Hash) {
+			return nil, fmt.Errorf("hash of transaction(%d) of %s is %x, expected %x", req.TxID, req.Msig, hash, req.Hash)
+		}
+	}
+
+	return txn, nil
+}
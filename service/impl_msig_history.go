@@ -0,0 +1,265 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/builtin"
+	multisig11 "github.com/filecoin-project/go-state-types/builtin/v11/multisig"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/ipfs/go-cid"
+
+	"github.com/ipfs-force-community/venus-tool/utils"
+)
+
+const msigHistoryRange = 30 * builtin.EpochsInDay
+
+// MsigHistory rebuilds the transactions of the multisig from the messages sent to it,
+// transactions proposed before the scanned range only have the approvals and cancellations in range
+func (s *ServiceImpl) MsigHistory(ctx context.Context, req *MsigHistoryReq) (*MsigHistoryResp, error) {
+	head, err := s.Node.ChainHead(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get chain head failed: %s", err)
+	}
+	from := req.From
+	if from <= 0 {
+		from = head.Height() - msigHistoryRange
+	}
+	if from < 0 {
+		from = 0
+	}
+	if from > head.Height() {
+		return nil, fmt.Errorf("from(%d) is greater than the current height(%d)", from, head.Height())
+	}
+
+	act, err := s.Node.StateGetActor(ctx, req.Msig, head.Key())
+	if err != nil {
+		return nil, fmt.Errorf("get actor %s failed: %s", req.Msig, err)
+	}
+	info, err := s.Multisig.StateMsigInfo(ctx, req.Msig, head.Key())
+	if err != nil {
+		return nil, fmt.Errorf("get multisig info failed: %s", err)
+	}
+	pending, err := s.Multisig.MsigGetPending(ctx, req.Msig, head.Key())
+	if err != nil {
+		return nil, fmt.Errorf("get pending transactions of %s failed: %s", req.Msig, err)
+	}
+
+	// messages may be sent to either the id or the robust address of the multisig
+	targets := []address.Address{req.Msig}
+	if id, err := s.Node.StateLookupID(ctx, req.Msig, head.Key()); err == nil && id != req.Msig {
+		targets = append(targets, id)
+	}
+	if robust, err := s.Node.StateLookupRobustAddress(ctx, req.Msig, head.Key()); err == nil && robust != req.Msig {
+		targets = append(targets, robust)
+	}
+	seen := make(map[cid.Cid]struct{})
+	var cids []cid.Cid
+	for _, to := range targets {
+		list, err := s.Node.StateListMessages(ctx, &types.MessageMatch{To: to}, head.Key(), from)
+		if err != nil {
+			return nil, fmt.Errorf("list messages to %s failed: %s", to, err)
+		}
+		for _, c := range list {
+			if _, ok := seen[c]; !ok {
+				seen[c] = struct{}{}
+				cids = append(cids, c)
+			}
+		}
+	}
+
+	ret := &MsigHistoryResp{
+		Msig: req.Msig,
+		From: from,
+		To:   head.Height(),
+	}
+	senders := make(map[address.Address]address.Address)
+	resolve := func(addr address.Address) address.Address {
+		if id, ok := senders[addr]; ok {
+			return id
+		}
+		id, err := s.Node.StateLookupID(ctx, addr, head.Key())
+		if err != nil {
+			log.Debugf("lookup id of %s failed: %s", addr, err)
+			id = addr
+		}
+		senders[addr] = id
+		return id
+	}
+
+	records := make([]*msigHistoryRecord, 0, len(cids))
+	for _, c := range cids {
+		msg, err := s.Node.ChainGetMessage(ctx, c)
+		if err != nil {
+			return nil, fmt.Errorf("get message %s failed: %s", c, err)
+		}
+		lookup, err := s.Node.StateSearchMsg(ctx, head.Key(), c, head.Height()-from+1, true)
+		if err != nil {
+			return nil, fmt.Errorf("search message %s failed: %s", c, err)
+		}
+		if lookup == nil {
+			log.Warnf("receipt of message %s not found", c)
+			continue
+		}
+		event := &MsigHistoryEvent{
+			Height:   lookup.Height,
+			MsgCid:   c,
+			From:     resolve(msg.From),
+			Value:    msg.Value,
+			Method:   fmt.Sprint(msg.Method),
+			ExitCode: lookup.Receipt.ExitCode,
+			TxnID:    -1,
+		}
+		if methodMeta, err := utils.GetMethodMeta(act.Code, msg.Method); err == nil {
+			event.Method = methodMeta.Name
+		}
+		records = append(records, &msigHistoryRecord{event: event, msg: msg, ret: lookup.Receipt.Return})
+	}
+
+	var txns map[int64]*MsigHistoryTxn
+	ret.Events, txns = replayMsigHistory(records, pending)
+	for _, txn := range txns {
+		if txn.Value.Nil() {
+			txn.Value = abi.NewTokenAmount(0)
+		}
+		if txn.To != address.Undef {
+			txn.ToID = resolve(txn.To)
+			txn.MsigTransaction = *s.decodeMsigTransaction(ctx, &txn.MsigTransaction.MsigTransaction, info.ApprovalsThreshold)
+		} else {
+			txn.Threshold = info.ApprovalsThreshold
+			txn.DecodeError = "transaction is proposed before the scanned range"
+		}
+		ret.Transactions = append(ret.Transactions, txn)
+	}
+	sort.Slice(ret.Transactions, func(i, j int) bool {
+		return ret.Transactions[i].ID < ret.Transactions[j].ID
+	})
+
+	return ret, nil
+}
+
+type msigHistoryRecord struct {
+	event *MsigHistoryEvent
+	msg   *types.Message
+	ret   []byte
+}
+
+// replayMsigHistory applies the messages sent to the multisig in the order of height to the transactions,
+// then merges the transactions still pending
+func replayMsigHistory(records []*msigHistoryRecord, pending []*types.MsigTransaction) ([]*MsigHistoryEvent, map[int64]*MsigHistoryTxn) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].event.Height < records[j].event.Height
+	})
+
+	txns := make(map[int64]*MsigHistoryTxn)
+	getTxn := func(id int64) *MsigHistoryTxn {
+		txn, ok := txns[id]
+		if !ok {
+			txn = &MsigHistoryTxn{State: MsigTxnUnknown}
+			txn.ID = id
+			txns[id] = txn
+		}
+		return txn
+	}
+	approve := func(txn *MsigHistoryTxn, signer address.Address) {
+		for _, a := range txn.Approved {
+			if a == signer {
+				return
+			}
+		}
+		txn.Approved = append(txn.Approved, signer)
+	}
+
+	events := make([]*MsigHistoryEvent, 0, len(records))
+	for _, r := range records {
+		events = append(events, r.event)
+		if r.event.ExitCode != exitcode.Ok {
+			continue
+		}
+
+		switch r.msg.Method {
+		case builtin.MethodsMultisig.Propose:
+			var params types.ProposeParams
+			if err := params.UnmarshalCBOR(bytes.NewReader(r.msg.Params)); err != nil {
+				log.Warnf("decode propose params of %s failed: %s", r.event.MsgCid, err)
+				continue
+			}
+			var pret types.ProposeReturn
+			if err := pret.UnmarshalCBOR(bytes.NewReader(r.ret)); err != nil {
+				log.Warnf("decode propose return of %s failed: %s", r.event.MsgCid, err)
+				continue
+			}
+			r.event.TxnID = int64(pret.TxnID)
+			txn := getTxn(int64(pret.TxnID))
+			// the actor keeps the destination as proposed, which is part of the proposal hash
+			txn.To = params.To
+			txn.Value = params.Value
+			txn.Method = params.Method
+			txn.Params = params.Params
+			txn.ProposedAt = r.event.Height
+			txn.Messages = append(txn.Messages, r.event.MsgCid)
+			// the proposer is the first approver
+			txn.Approved = append([]address.Address{r.event.From}, txn.Approved...)
+			if txn.State == MsigTxnUnknown {
+				txn.State = MsigTxnPending
+			}
+			if pret.Applied {
+				txn.State = MsigTxnExecuted
+				txn.ExitCode = pret.Code
+				txn.Return = pret.Ret
+				txn.ClosedAt = r.event.Height
+			}
+		case builtin.MethodsMultisig.Approve:
+			var params multisig11.TxnIDParams
+			if err := params.UnmarshalCBOR(bytes.NewReader(r.msg.Params)); err != nil {
+				log.Warnf("decode approve params of %s failed: %s", r.event.MsgCid, err)
+				continue
+			}
+			var aret types.ApproveReturn
+			if err := aret.UnmarshalCBOR(bytes.NewReader(r.ret)); err != nil {
+				log.Warnf("decode approve return of %s failed: %s", r.event.MsgCid, err)
+				continue
+			}
+			r.event.TxnID = int64(params.ID)
+			txn := getTxn(int64(params.ID))
+			txn.Messages = append(txn.Messages, r.event.MsgCid)
+			approve(txn, r.event.From)
+			if aret.Applied {
+				txn.State = MsigTxnExecuted
+				txn.ExitCode = aret.Code
+				txn.Return = aret.Ret
+				txn.ClosedAt = r.event.Height
+			}
+		case builtin.MethodsMultisig.Cancel:
+			var params multisig11.TxnIDParams
+			if err := params.UnmarshalCBOR(bytes.NewReader(r.msg.Params)); err != nil {
+				log.Warnf("decode cancel params of %s failed: %s", r.event.MsgCid, err)
+				continue
+			}
+			r.event.TxnID = int64(params.ID)
+			txn := getTxn(int64(params.ID))
+			txn.Messages = append(txn.Messages, r.event.MsgCid)
+			txn.State = MsigTxnCancelled
+			txn.ClosedAt = r.event.Height
+		}
+	}
+
+	// transactions still pending carry the full proposal even if proposed before the range
+	for _, p := range pending {
+		txn := getTxn(p.ID)
+		if txn.State == MsigTxnUnknown || txn.State == MsigTxnPending {
+			txn.State = MsigTxnPending
+			txn.To = p.To
+			txn.Value = p.Value
+			txn.Method = p.Method
+			txn.Params = p.Params
+			txn.Approved = p.Approved
+		}
+	}
+	return events, txns
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/builtin"
+	multisig11 "github.com/filecoin-project/go-state-types/builtin/v11/multisig"
+	multisig9 "github.com/filecoin-project/go-state-types/builtin/v9/multisig"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/stretchr/testify/assert"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+func TestReplayMsigHistory(t *testing.T) {
+	signerA, err := address.NewIDAddress(1001)
+	assert.NoError(t, err)
+	signerB, err := address.NewIDAddress(1002)
+	assert.NoError(t, err)
+	// the destination is kept as proposed even if it is not an id address
+	to, err := address.NewFromString("f1abjxfbp274xpdqcpuaykwkfb43omjotacm2p3za")
+	assert.NoError(t, err)
+
+	encode := func(v cbg.CBORMarshaler) []byte {
+		buf := new(bytes.Buffer)
+		assert.NoError(t, v.MarshalCBOR(buf))
+		return buf.Bytes()
+	}
+	record := func(height abi.ChainEpoch, from address.Address, method abi.MethodNum, code exitcode.ExitCode, params, ret cbg.CBORMarshaler) *msigHistoryRecord {
+		r := &msigHistoryRecord{
+			event: &MsigHistoryEvent{Height: height, From: from, ExitCode: code, TxnID: -1},
+			msg:   &types.Message{From: from, Method: method, Params: encode(params)},
+		}
+		if ret != nil {
+			r.ret = encode(ret)
+		}
+		return r
+	}
+	propose := func(height abi.ChainEpoch, from address.Address, id int64, applied bool) *msigHistoryRecord {
+		return record(height, from, builtin.MethodsMultisig.Propose, exitcode.Ok,
+			&types.ProposeParams{To: to, Value: big.NewInt(100), Method: builtin.MethodSend},
+			&types.ProposeReturn{TxnID: multisig9.TxnID(id), Applied: applied})
+	}
+	approve := func(height abi.ChainEpoch, from address.Address, id int64, applied bool) *msigHistoryRecord {
+		return record(height, from, builtin.MethodsMultisig.Approve, exitcode.Ok,
+			&multisig11.TxnIDParams{ID: multisig11.TxnID(id)},
+			&types.ApproveReturn{Applied: applied})
+	}
+	cancel := func(height abi.ChainEpoch, from address.Address, id int64) *msigHistoryRecord {
+		return record(height, from, builtin.MethodsMultisig.Cancel, exitcode.Ok,
+			&multisig11.TxnIDParams{ID: multisig11.TxnID(id)}, nil)
+	}
+
+	type wantTxn struct {
+		state    MsigTxnState
+		approved []address.Address
+		closedAt abi.ChainEpoch
+		// proposed is false if the proposal is before the scanned range
+		proposed bool
+	}
+	testCases := []struct {
+		name    string
+		records []*msigHistoryRecord
+		pending []*types.MsigTransaction
+		want    map[int64]wantTxn
+	}{
+		{
+			name:    "proposed and pending",
+			records: []*msigHistoryRecord{propose(10, signerA, 0, false)},
+			pending: []*types.MsigTransaction{{ID: 0, To: to, Value: big.NewInt(100), Approved: []address.Address{signerA}}},
+			want:    map[int64]wantTxn{0: {state: MsigTxnPending, approved: []address.Address{signerA}, proposed: true}},
+		},
+		{
+			name:    "executed when proposed",
+			records: []*msigHistoryRecord{propose(10, signerA, 0, true)},
+			want:    map[int64]wantTxn{0: {state: MsigTxnExecuted, approved: []address.Address{signerA}, closedAt: 10, proposed: true}},
+		},
+		{
+			name:    "executed by approval in any order",
+			records: []*msigHistoryRecord{approve(20, signerB, 0, true), propose(10, signerA, 0, false)},
+			want:    map[int64]wantTxn{0: {state: MsigTxnExecuted, approved: []address.Address{signerA, signerB}, closedAt: 20, proposed: true}},
+		},
+		{
+			name:    "cancelled",
+			records: []*msigHistoryRecord{propose(10, signerA, 0, false), cancel(15, signerA, 0)},
+			want:    map[int64]wantTxn{0: {state: MsigTxnCancelled, approved: []address.Address{signerA}, closedAt: 15, proposed: true}},
+		},
+		{
+			name: "failed approval is ignored",
+			records: []*msigHistoryRecord{
+				propose(10, signerA, 0, false),
+				record(20, signerB, builtin.MethodsMultisig.Approve, exitcode.ErrForbidden, &multisig11.TxnIDParams{ID: 0}, nil),
+			},
+			pending: []*types.MsigTransaction{{ID: 0, To: to, Value: big.NewInt(100), Approved: []address.Address{signerA}}},
+			want:    map[int64]wantTxn{0: {state: MsigTxnPending, approved: []address.Address{signerA}, proposed: true}},
+		},
+		{
+			name:    "proposed before range and still pending",
+			records: []*msigHistoryRecord{approve(20, signerB, 3, false)},
+			pending: []*types.MsigTransaction{{ID: 3, To: to, Value: big.NewInt(100), Approved: []address.Address{signerA, signerB}}},
+			want:    map[int64]wantTxn{3: {state: MsigTxnPending, approved: []address.Address{signerA, signerB}, proposed: true}},
+		},
+		{
+			name:    "proposed before range and cancelled",
+			records: []*msigHistoryRecord{cancel(20, signerA, 3)},
+			want:    map[int64]wantTxn{3: {state: MsigTxnCancelled, closedAt: 20}},
+		},
+		{
+			name:    "pending only",
+			pending: []*types.MsigTransaction{{ID: 5, To: to, Value: big.NewInt(100), Approved: []address.Address{signerA}}},
+			want:    map[int64]wantTxn{5: {state: MsigTxnPending, approved: []address.Address{signerA}, proposed: true}},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			events, txns := replayMsigHistory(tt.records, tt.pending)
+			assert.Len(t, events, len(tt.records))
+			for i := 1; i < len(events); i++ {
+				assert.LessOrEqual(t, events[i-1].Height, events[i].Height)
+			}
+
+			assert.Len(t, txns, len(tt.want))
+			for id, want := range tt.want {
+				txn, ok := txns[id]
+				if !assert.True(t, ok, "transaction %d not found", id) {
+					continue
+				}
+				assert.Equal(t, want.state, txn.State)
+				assert.Equal(t, want.approved, txn.Approved)
+				assert.Equal(t, want.closedAt, txn.ClosedAt)
+				if want.proposed {
+					assert.Equal(t, to, txn.To)
+				} else {
+					assert.Equal(t, address.Undef, txn.To)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/venus/venus-shared/actors/builtin"
+	"github.com/filecoin-project/venus/venus-shared/types"
+
+	"github.com/ipfs-force-community/venus-tool/repo/config"
+	"github.com/ipfs-force-community/venus-tool/utils"
+)
+
+const msigInboxFile = "msig_inbox.json"
+
+// msigInbox keeps the watched multisigs in config, and the time pending transactions are first seen in a json file under the repo
+type msigInbox struct {
+	cfg *config.Config
+
+	// lk guards FirstSeen
+	lk   sync.Mutex
+	path string
+	// FirstSeen is keyed by the multisig, the id and the hash of transactions
+	FirstSeen map[string]time.Time
+}
+
+func newMsigInbox(cfg *config.Config, path string) (*msigInbox, error) {
+	inbox := &msigInbox{
+		cfg:       cfg,
+		path:      path,
+		FirstSeen: make(map[string]time.Time),
+	}
+	if _, err := utils.LoadJSON(path, inbox); err != nil {
+		return nil, fmt.Errorf("load msig inbox file failed: %s", err)
+	}
+	return inbox, nil
+}
+
+func (in *msigInbox) watched() ([]address.Address, error) {
+	var watched []string
+	in.cfg.View(func(cfg *config.Config) {
+		watched = append([]string{}, cfg.Msig.Watched...)
+	})
+
+	ret := make([]address.Address, 0, len(watched))
+	for _, w := range watched {
+		addr, err := address.NewFromString(w)
+		if err != nil {
+			return nil, fmt.Errorf("parse watched multisig %s failed: %s", w, err)
+		}
+		ret = append(ret, addr)
+	}
+	return ret, nil
+}
+
+func (in *msigInbox) watch(msig address.Address) error {
+	return in.cfg.Update(func(cfg *config.Config) error {
+		for _, w := range cfg.Msig.Watched {
+			if w == msig.String() {
+				return fmt.Errorf("multisig %s is already watched", msig)
+			}
+		}
+		cfg.Msig.Watched = append(cfg.Msig.Watched, msig.String())
+		return nil
+	})
+}
+
+func (in *msigInbox) unwatch(msig address.Address) error {
+	return in.cfg.Update(func(cfg *config.Config) error {
+		for i, w := range cfg.Msig.Watched {
+			if w == msig.String() {
+				cfg.Msig.Watched = append(cfg.Msig.Watched[:i:i], cfg.Msig.Watched[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("multisig %s is not watched", msig)
+	})
+}
+
+// seen records the first seen time of the pending transactions of the listed multisigs and forgets those no longer pending,
+// entries of multisigs not listed, eg: failed to query, are kept
+func (in *msigInbox) seen(listed map[address.Address][]string, now time.Time) (map[string]time.Time, error) {
+	in.lk.Lock()
+	defer in.lk.Unlock()
+
+	firstSeen := make(map[string]time.Time, len(in.FirstSeen))
+	for k, t := range in.FirstSeen {
+		if _, ok := listed[msigOfInboxKey(k)]; !ok {
+			firstSeen[k] = t
+		}
+	}
+	for _, keys := range listed {
+		for _, k := range keys {
+			t, ok := in.FirstSeen[k]
+			if !ok {
+				t = now
+			}
+			firstSeen[k] = t
+		}
+	}
+	in.FirstSeen = firstSeen
+	return firstSeen, utils.SaveJSON(in.path, in)
+}
+
+func msigInboxKey(msig address.Address, txn *MsigTransaction) string {
+	return fmt.Sprintf("%s/%d/%x", msig, txn.ID, txn.Hash)
+}
+
+func msigOfInboxKey(key string) address.Address {
+	msig, _, _ := strings.Cut(key, "/")
+	addr, err := address.NewFromString(msig)
+	if err != nil {
+		return address.Undef
+	}
+	return addr
+}
+
+func (s *ServiceImpl) MsigWatchList(ctx context.Context) ([]address.Address, error) {
+	return s.msigInbox.watched()
+}
+
+func (s *ServiceImpl) MsigWatch(ctx context.Context, req *MsigWatchReq) error {
+	act, err := s.Node.StateGetActor(ctx, req.Msig, types.EmptyTSK)
+	if err != nil {
+		return fmt.Errorf("get actor of %s failed: %s", req.Msig, err)
+	}
+	if !builtin.IsMultisigActor(act.Code) {
+		return fmt.Errorf("%s is not a multisig", req.Msig)
+	}
+	return s.msigInbox.watch(req.Msig)
+}
+
+func (s *ServiceImpl) MsigUnwatch(ctx context.Context, req *MsigWatchReq) error {
+	return s.msigInbox.unwatch(req.Msig)
+}
+
+// MsigInbox lists pending transactions of watched multisigs which are not approved by the signers yet
+func (s *ServiceImpl) MsigInbox(ctx context.Context, req *MsigInboxReq) ([]*MsigInboxItem, error) {
+	signers := req.Signer
+	if len(signers) == 0 {
+		var err error
+		signers, err = s.Wallet.WalletList(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("list wallet failed: %s", err)
+		}
+	}
+	signerIDs := make(map[address.Address]address.Address, len(signers))
+	for _, signer := range signers {
+		id, err := s.Node.StateLookupID(ctx, signer, types.EmptyTSK)
+		if err != nil {
+			// addresses not on chain can not be signers
+			log.Debugf("lookup signer(%s) failed: %s", signer, err)
+			continue
+		}
+		signerIDs[id] = signer
+	}
+
+	watched, err := s.msigInbox.watched()
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*MsigInboxItem
+	listed := make(map[address.Address][]string, len(watched))
+	for _, msig := range watched {
+		info, err := s.Multisig.StateMsigInfo(ctx, msig, types.EmptyTSK)
+		if err != nil {
+			log.Warnf("get info of multisig %s failed: %s", msig, err)
+			continue
+		}
+		pending, err := s.Multisig.MsigGetPending(ctx, msig, types.EmptyTSK)
+		if err != nil {
+			log.Warnf("get pending transactions of multisig %s failed: %s", msig, err)
+			continue
+		}
+
+		listed[msig] = []string{}
+		for _, p := range pending {
+			txn := s.decodeMsigTransaction(ctx, p, info.ApprovalsThreshold)
+			listed[msig] = append(listed[msig], msigInboxKey(msig, txn))
+
+			approved := make(map[address.Address]struct{}, len(p.Approved))
+			for _, a := range p.Approved {
+				approved[a] = struct{}{}
+			}
+			item := &MsigInboxItem{Msig: msig, MsigTransaction: *txn}
+			for _, id := range info.Signers {
+				signer, ok := signerIDs[id]
+				if !ok {
+					continue
+				}
+				if _, ok := approved[id]; !ok {
+					item.Signers = append(item.Signers, signer)
+				}
+			}
+			if len(item.Signers) > 0 {
+				ret = append(ret, item)
+			}
+		}
+	}
+
+	now := time.Now()
+	firstSeen, err := s.msigInbox.seen(listed, now)
+	if err != nil {
+		log.Warnf("save msig inbox failed: %s", err)
+	}
+	for _, item := range ret {
+		item.FirstSeen = firstSeen[msigInboxKey(item.Msig, &item.MsigTransaction)]
+		item.Age = now.Sub(item.FirstSeen)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].FirstSeen.Before(ret[j].FirstSeen)
+	})
+
+	return ret, nil
+}
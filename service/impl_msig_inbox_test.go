@@ -0,0 +1,67 @@
+package service
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ipfs-force-community/venus-tool/repo/config"
+)
+
+func TestMsigInboxSeen(t *testing.T) {
+	msigA, err := address.NewIDAddress(1001)
+	assert.NoError(t, err)
+	msigB, err := address.NewIDAddress(1002)
+	assert.NoError(t, err)
+	txn := func(id int64) *MsigTransaction {
+		ret := &MsigTransaction{Hash: []byte{byte(id)}}
+		ret.ID = id
+		return ret
+	}
+	keyA1 := msigInboxKey(msigA, txn(1))
+	keyA2 := msigInboxKey(msigA, txn(2))
+	keyB1 := msigInboxKey(msigB, txn(1))
+
+	before := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := before.Add(time.Hour)
+
+	testCases := []struct {
+		name   string
+		listed map[address.Address][]string
+		want   map[string]time.Time
+	}{
+		{
+			name:   "new transactions are seen now",
+			listed: map[address.Address][]string{msigA: {keyA1, keyA2}, msigB: {keyB1}},
+			want:   map[string]time.Time{keyA1: before, keyA2: now, keyB1: before},
+		},
+		{
+			name:   "transactions no longer pending are forgotten",
+			listed: map[address.Address][]string{msigA: {}, msigB: {keyB1}},
+			want:   map[string]time.Time{keyB1: before},
+		},
+		{
+			name:   "multisigs not listed are kept",
+			listed: map[address.Address][]string{msigA: {keyA1}},
+			want:   map[string]time.Time{keyA1: before, keyB1: before},
+		},
+		{
+			name: "nothing listed",
+			want: map[string]time.Time{keyA1: before, keyB1: before},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			in, err := newMsigInbox(&config.Config{}, filepath.Join(t.TempDir(), msigInboxFile))
+			assert.NoError(t, err)
+			in.FirstSeen = map[string]time.Time{keyA1: before, keyB1: before}
+
+			got, err := in.seen(tt.listed, now)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/builtin"
+	msig2 "github.com/filecoin-project/specs-actors/v2/actors/builtin/multisig"
+	"github.com/filecoin-project/venus/venus-shared/actors/builtin/multisig"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/blake2b"
+)
+
+func TestMsigProposalHash(t *testing.T) {
+	requester, err := address.NewIDAddress(1000)
+	assert.NoError(t, err)
+	to, err := address.NewFromString("f1abjxfbp274xpdqcpuaykwkfb43omjotacm2p3za")
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		data multisig.ProposalHashData
+	}{
+		{
+			name: "send",
+			data: multisig.ProposalHashData{Requester: requester, To: to, Value: big.NewInt(1e18), Method: builtin.MethodSend},
+		},
+		{
+			name: "method with params",
+			data: multisig.ProposalHashData{Requester: requester, To: requester, Value: big.Zero(), Method: builtin.MethodsMultisig.AddSigner, Params: []byte{0x82, 0x42, 0x00, 0x01, 0xf5}},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := msigProposalHash(&tt.data)
+			assert.NoError(t, err)
+
+			// the hash must be the same as the one computed by the multisig actor
+			want, err := msig2.ComputeProposalHash(&msig2.Transaction{
+				To:       tt.data.To,
+				Value:    tt.data.Value,
+				Method:   abi.MethodNum(tt.data.Method),
+				Params:   tt.data.Params,
+				Approved: []address.Address{tt.data.Requester},
+			}, blake2b.Sum256)
+			assert.NoError(t, err)
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestMsigLockedAt(t *testing.T) {
+	vesting := types.MsigVesting{
+		InitialBalance: big.NewInt(1000),
+		StartEpoch:     100,
+		UnlockDuration: 3,
+	}
+
+	testCases := []struct {
+		name  string
+		epoch abi.ChainEpoch
+		want  abi.TokenAmount
+	}{
+		{name: "before start", epoch: 50, want: big.NewInt(1000)},
+		{name: "at start", epoch: 100, want: big.NewInt(1000)},
+		{name: "rounded up", epoch: 101, want: big.NewInt(667)},
+		{name: "rounded up near the end", epoch: 102, want: big.NewInt(334)},
+		{name: "at the end", epoch: 103, want: big.Zero()},
+		{name: "after the end", epoch: 200, want: big.Zero()},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got := msigLockedAt(vesting, tt.epoch)
+			assert.True(t, tt.want.Equals(got), "want %s, got %s", tt.want, got)
+
+			// the locked amount must be the same as the one computed by the multisig actor
+			st := &msig2.State{
+				InitialBalance: vesting.InitialBalance,
+				StartEpoch:     vesting.StartEpoch,
+				UnlockDuration: vesting.UnlockDuration,
+			}
+			want := st.AmountLocked(tt.epoch - vesting.StartEpoch)
+			assert.True(t, want.Equals(got), "actor computes %s, got %s", want, got)
+		})
+	}
+}
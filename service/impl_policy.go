@@ -0,0 +1,500 @@
+package service
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	msgTypes "github.com/filecoin-project/venus/venus-shared/types/messager"
+
+	"github.com/ipfs-force-community/venus-tool/repo/config"
+	"github.com/ipfs-force-community/venus-tool/utils"
+)
+
+const policyFile = "policy.json"
+
+// OperatorKey is the key of the operator authenticated by the token of request, which is set by the route
+const OperatorKey = "venus-tool/operator"
+
+// authOperator returns the operator authenticated by the token of request, empty if the request carries no token
+func authOperator(ctx context.Context) string {
+	operator, _ := ctx.Value(OperatorKey).(string)
+	return operator
+}
+
+// requireOperator returns the operator authenticated by the token of request, fails if the request carries no token
+func requireOperator(ctx context.Context) (string, error) {
+	operator := authOperator(ctx)
+	if operator == "" {
+		return "", fmt.Errorf("a token identifying the operator is required")
+	}
+	return operator, nil
+}
+
+// VerifyOperator verifies the token by sophon-auth and returns the user of the token as the operator
+func (s *ServiceImpl) VerifyOperator(ctx context.Context, token string) (string, error) {
+	if s.Auth == nil {
+		return "", ErrEmptyAuth
+	}
+	resp, err := s.Auth.Verify(ctx, token)
+	if err != nil {
+		return "", fmt.Errorf("verify token failed: %s", err)
+	}
+	if resp.Name == "" {
+		return "", fmt.Errorf("user of token is empty")
+	}
+	return resp.Name, nil
+}
+
+type spendRecord struct {
+	ID    uint64
+	Time  time.Time
+	Value abi.TokenAmount
+}
+
+// policyKeeper reads the policies from config, and persists the approvals and spending records into a json file under the repo
+type policyKeeper struct {
+	lk   sync.Mutex
+	cfg  *config.Config
+	path string
+
+	NextID    uint64
+	Approvals []*PolicyApproval
+	// Spent records the value sent from the addresses with policy in the last 7 days
+	Spent map[string][]spendRecord
+}
+
+func newPolicyKeeper(cfg *config.Config, path string) (*policyKeeper, error) {
+	pk := &policyKeeper{
+		cfg:    cfg,
+		path:   path,
+		NextID: 1,
+		Spent:  map[string][]spendRecord{},
+	}
+	if _, err := utils.LoadJSON(path, pk); err != nil {
+		return nil, fmt.Errorf("load policy file failed: %s", err)
+	}
+	if pk.Spent == nil {
+		pk.Spent = map[string][]spendRecord{}
+	}
+	for _, p := range cfg.Policy.Addresses {
+		if _, err := parseAddressPolicy(p); err != nil {
+			return nil, fmt.Errorf("invalid policy of %s: %s", p.Address, err)
+		}
+	}
+	return pk, nil
+}
+
+// policies returns a copy of the address policies in config
+func (pk *policyKeeper) policies() []config.AddressPolicy {
+	var ret []config.AddressPolicy
+	pk.cfg.View(func(cfg *config.Config) {
+		ret = append([]config.AddressPolicy{}, cfg.Policy.Addresses...)
+	})
+	return ret
+}
+
+// save must be called with lk held
+func (pk *policyKeeper) save() error {
+	return utils.SaveJSON(pk.path, pk)
+}
+
+type addressPolicy struct {
+	config.AddressPolicy
+	addr      address.Address
+	daily     abi.TokenAmount
+	weekly    abi.TokenAmount
+	allowedTo []address.Address
+	forbidden map[abi.MethodNum]struct{}
+}
+
+func parseAddressPolicy(p config.AddressPolicy) (*addressPolicy, error) {
+	var err error
+	ret := &addressPolicy{
+		AddressPolicy: p,
+		forbidden:     map[abi.MethodNum]struct{}{},
+	}
+	ret.addr, err = address.NewFromString(p.Address)
+	if err != nil {
+		return nil, fmt.Errorf("parse address failed: %s", err)
+	}
+	parseLimit := func(limit string) (abi.TokenAmount, error) {
+		if limit == "" {
+			return big.Int{}, nil
+		}
+		fil, err := types.ParseFIL(limit)
+		if err != nil {
+			return big.Int{}, fmt.Errorf("parse limit %q failed: %s", limit, err)
+		}
+		return abi.TokenAmount(fil), nil
+	}
+	if ret.daily, err = parseLimit(p.DailyLimit); err != nil {
+		return nil, err
+	}
+	if ret.weekly, err = parseLimit(p.WeeklyLimit); err != nil {
+		return nil, err
+	}
+	for _, to := range p.AllowedTo {
+		addr, err := address.NewFromString(to)
+		if err != nil {
+			return nil, fmt.Errorf("parse allowed destination %s failed: %s", to, err)
+		}
+		ret.allowedTo = append(ret.allowedTo, addr)
+	}
+	for _, m := range p.ForbiddenMethods {
+		ret.forbidden[abi.MethodNum(m)] = struct{}{}
+	}
+	return ret, nil
+}
+
+// sameAddress compares addresses by their id address if they are different in form
+func (s *ServiceImpl) sameAddress(ctx context.Context, a, b address.Address) bool {
+	if a == b {
+		return true
+	}
+	aID, err := s.Node.StateLookupID(ctx, a, types.EmptyTSK)
+	if err != nil {
+		return false
+	}
+	bID, err := s.Node.StateLookupID(ctx, b, types.EmptyTSK)
+	if err != nil {
+		return false
+	}
+	return aID == bID
+}
+
+func (s *ServiceImpl) findPolicy(ctx context.Context, from address.Address) (*addressPolicy, error) {
+	policies := s.policy.policies()
+
+	for _, p := range policies {
+		ap, err := parseAddressPolicy(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy of %s: %s", p.Address, err)
+		}
+		if s.sameAddress(ctx, ap.addr, from) {
+			return ap, nil
+		}
+	}
+	return nil, nil
+}
+
+// checkPolicy rejects the message if it is forbidden by the policy of sender,
+// and creates an approval request if the message exceeds the spending limits.
+// The value of message is reserved in spending records if it passes, release it if the message fails to send.
+// Signed messages can not be resent once approved, so they are rejected instead if not approvable.
+func (s *ServiceImpl) checkPolicy(ctx context.Context, msg *types.Message, spec *msgTypes.SendSpec, approvable bool) (release func(), err error) {
+	release = func() {}
+	p, err := s.findPolicy(ctx, msg.From)
+	if err != nil || p == nil {
+		return release, err
+	}
+
+	if _, ok := p.forbidden[msg.Method]; ok {
+		return release, fmt.Errorf("method %d is forbidden for %s by policy", msg.Method, msg.From)
+	}
+	if len(p.allowedTo) > 0 {
+		allowed := false
+		for _, to := range p.allowedTo {
+			if s.sameAddress(ctx, to, msg.To) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return release, fmt.Errorf("destination %s is not allowed for %s by policy", msg.To, msg.From)
+		}
+	}
+
+	value := msg.Value
+	if value.Nil() {
+		value = big.Zero()
+	}
+
+	pk := s.policy
+	pk.lk.Lock()
+	defer pk.lk.Unlock()
+
+	now := time.Now()
+	records := pk.pruneSpent(p.Address, now)
+	reason := ""
+	for _, limit := range []struct {
+		name  string
+		limit abi.TokenAmount
+		dur   time.Duration
+	}{
+		{"daily limit", p.daily, 24 * time.Hour},
+		{"weekly limit", p.weekly, 7 * 24 * time.Hour},
+	} {
+		if limit.limit.Nil() {
+			continue
+		}
+		spent := big.Zero()
+		for _, r := range records {
+			if now.Sub(r.Time) < limit.dur {
+				spent = big.Add(spent, r.Value)
+			}
+		}
+		if big.Add(spent, value).GreaterThan(limit.limit) {
+			reason = fmt.Sprintf("%s %s exceeded, spent %s", limit.name, types.FIL(limit.limit), types.FIL(spent))
+			break
+		}
+	}
+
+	if reason != "" && !approvable {
+		return release, fmt.Errorf("%s, signed messages exceeding the limits are rejected", reason)
+	}
+	if reason != "" {
+		// the requester and the approver are told apart by their tokens
+		requester := authOperator(ctx)
+		if requester == "" {
+			return release, fmt.Errorf("%s, an approval request can only be created with a token identifying the requester", reason)
+		}
+		req := MsgSendReq{
+			From:   msg.From,
+			To:     msg.To,
+			Value:  value,
+			Method: msg.Method,
+		}
+		if len(msg.Params) > 0 {
+			req.Params = &EncodedParams{Data: hex.EncodeToString(msg.Params), EncType: EncHex}
+		}
+		if spec != nil {
+			req.SendSpec = *spec
+		}
+		approval := &PolicyApproval{
+			ID:        pk.NextID,
+			Msg:       req,
+			Reason:    reason,
+			State:     PolicyApprovalPending,
+			Requester: requester,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		pk.NextID++
+		pk.Approvals = append(pk.Approvals, approval)
+		if err := pk.save(); err != nil {
+			return release, fmt.Errorf("save approval request failed: %s", err)
+		}
+		log.Warnf("message from %s to %s value %s: %s, approval request(%d) created", msg.From, msg.To, types.FIL(value), reason, approval.ID)
+		return release, fmt.Errorf("%s, approval request(%d) is created, the message will be sent once another user approves it", reason, approval.ID)
+	}
+
+	id := pk.recordSpent(p.Address, now, value)
+	if err := pk.save(); err != nil {
+		return release, fmt.Errorf("save spending record failed: %s", err)
+	}
+	return func() {
+		pk.lk.Lock()
+		defer pk.lk.Unlock()
+		records := pk.Spent[p.Address]
+		for i := range records {
+			if records[i].ID == id {
+				pk.Spent[p.Address] = append(records[:i], records[i+1:]...)
+				break
+			}
+		}
+		if err := pk.save(); err != nil {
+			log.Errorf("save spending record failed: %s", err)
+		}
+	}, nil
+}
+
+// pruneSpent drops records older than 7 days, must be called with lk held
+func (pk *policyKeeper) pruneSpent(key string, now time.Time) []spendRecord {
+	records := pk.Spent[key]
+	kept := records[:0]
+	for _, r := range records {
+		if now.Sub(r.Time) < 7*24*time.Hour {
+			kept = append(kept, r)
+		}
+	}
+	pk.Spent[key] = kept
+	return kept
+}
+
+// recordSpent must be called with lk held
+func (pk *policyKeeper) recordSpent(key string, now time.Time, value abi.TokenAmount) uint64 {
+	id := pk.NextID
+	pk.NextID++
+	pk.Spent[key] = append(pk.Spent[key], spendRecord{ID: id, Time: now, Value: value})
+	return id
+}
+
+func (s *ServiceImpl) PolicyList(ctx context.Context) ([]config.AddressPolicy, error) {
+	return s.policy.policies(), nil
+}
+
+func (s *ServiceImpl) PolicySet(ctx context.Context, req *config.AddressPolicy) error {
+	operator, err := requireOperator(ctx)
+	if err != nil {
+		return err
+	}
+	p, err := parseAddressPolicy(*req)
+	if err != nil {
+		return err
+	}
+
+	err = s.policy.cfg.Update(func(cfg *config.Config) error {
+		policies := cfg.Policy.Addresses
+		replaced := false
+		for i := range policies {
+			if addr, err := address.NewFromString(policies[i].Address); err == nil && addr == p.addr {
+				policies[i] = *req
+				replaced = true
+			}
+		}
+		if !replaced {
+			policies = append(policies, *req)
+		}
+		cfg.Policy.Addresses = policies
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("save config failed: %s", err)
+	}
+	log.Infof("set policy of %s by %s: %+v", req.Address, operator, *req)
+	if err := s.audit.record("policy.set", operator, req); err != nil {
+		log.Errorf("record audit log failed: %s", err)
+	}
+	return nil
+}
+
+func (s *ServiceImpl) PolicyRemove(ctx context.Context, req *Address) error {
+	operator, err := requireOperator(ctx)
+	if err != nil {
+		return err
+	}
+	errNotFound := fmt.Errorf("policy of %s not found", req.Address)
+	err = s.policy.cfg.Update(func(cfg *config.Config) error {
+		policies := make([]config.AddressPolicy, 0, len(cfg.Policy.Addresses))
+		for _, p := range cfg.Policy.Addresses {
+			if addr, err := address.NewFromString(p.Address); err == nil && addr == req.Address {
+				continue
+			}
+			policies = append(policies, p)
+		}
+		if len(policies) == len(cfg.Policy.Addresses) {
+			return errNotFound
+		}
+		cfg.Policy.Addresses = policies
+		return nil
+	})
+	if err == errNotFound {
+		return err
+	}
+	if err != nil {
+		return fmt.Errorf("save config failed: %s", err)
+	}
+	log.Infof("remove policy of %s by %s", req.Address, operator)
+	if err := s.audit.record("policy.remove", operator, req); err != nil {
+		log.Errorf("record audit log failed: %s", err)
+	}
+	return nil
+}
+
+func (s *ServiceImpl) PolicyApprovalList(ctx context.Context, req *PolicyApprovalListReq) ([]*PolicyApproval, error) {
+	pk := s.policy
+	pk.lk.Lock()
+	defer pk.lk.Unlock()
+
+	ret := make([]*PolicyApproval, 0, len(pk.Approvals))
+	for _, a := range pk.Approvals {
+		if req.State == "" || a.State == req.State {
+			approval := *a
+			ret = append(ret, &approval)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ID < ret[j].ID })
+	return ret, nil
+}
+
+// takeApproval marks the pending approval as approved or rejected by the operator,
+// who must be authenticated by token and be another user than the requester
+func (pk *policyKeeper) takeApproval(operator string, req *PolicyApproveReq, state PolicyApprovalState) (*PolicyApproval, error) {
+	if operator == "" {
+		return nil, fmt.Errorf("approval request(%d) can only be handled with a token identifying the operator", req.ID)
+	}
+
+	pk.lk.Lock()
+	defer pk.lk.Unlock()
+
+	for _, a := range pk.Approvals {
+		if a.ID != req.ID {
+			continue
+		}
+		if a.State != PolicyApprovalPending {
+			return nil, fmt.Errorf("approval request(%d) is already %s", a.ID, a.State)
+		}
+		if a.Requester == operator {
+			return nil, fmt.Errorf("approval request(%d) must be handled by a user other than the requester", a.ID)
+		}
+		a.State = state
+		a.Approver = operator
+		a.UpdatedAt = time.Now()
+		if err := pk.save(); err != nil {
+			return nil, err
+		}
+		approval := *a
+		return &approval, nil
+	}
+	return nil, fmt.Errorf("approval request(%d) not found", req.ID)
+}
+
+func (s *ServiceImpl) PolicyApprove(ctx context.Context, req *PolicyApproveReq) (string, error) {
+	operator := authOperator(ctx)
+	approval, err := s.policy.takeApproval(operator, req, PolicyApprovalApproved)
+	if err != nil {
+		return "", err
+	}
+
+	msgID, err := s.pushMsgSendReq(ctx, &approval.Msg)
+	p, perr := s.findPolicy(ctx, approval.Msg.From)
+	if perr != nil {
+		log.Warnf("find policy of %s failed: %s", approval.Msg.From, perr)
+	}
+
+	pk := s.policy
+	pk.lk.Lock()
+	defer pk.lk.Unlock()
+	for _, a := range pk.Approvals {
+		if a.ID != approval.ID {
+			continue
+		}
+		if err != nil {
+			// give it back, so that it can be approved again
+			a.State = PolicyApprovalPending
+			a.Approver = ""
+		} else {
+			a.MsgID = msgID
+		}
+		a.UpdatedAt = time.Now()
+	}
+	if err == nil && p != nil {
+		pk.recordSpent(p.Address, time.Now(), approval.Msg.Value)
+	}
+	if serr := pk.save(); serr != nil {
+		log.Errorf("save approval request(%d) failed: %s", approval.ID, serr)
+	}
+	if err != nil {
+		return "", fmt.Errorf("send message of approval request(%d) failed: %s", approval.ID, err)
+	}
+	log.Infof("approval request(%d) approved by %s, message(%s)", approval.ID, operator, msgID)
+	return msgID, nil
+}
+
+func (s *ServiceImpl) PolicyReject(ctx context.Context, req *PolicyApproveReq) error {
+	operator := authOperator(ctx)
+	approval, err := s.policy.takeApproval(operator, req, PolicyApprovalRejected)
+	if err != nil {
+		return err
+	}
+	log.Infof("approval request(%d) rejected by %s", approval.ID, operator)
+	return nil
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/builtin"
+	nodeV1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ipfs-force-community/venus-tool/repo/config"
+)
+
+// idNode takes every address as its own id address
+type idNode struct {
+	nodeV1.FullNode
+}
+
+func (n *idNode) StateLookupID(ctx context.Context, addr address.Address, tsk types.TipSetKey) (address.Address, error) {
+	return addr, nil
+}
+
+func TestPruneSpent(t *testing.T) {
+	now := time.Now()
+	record := func(id uint64, age time.Duration) spendRecord {
+		return spendRecord{ID: id, Time: now.Add(-age), Value: big.NewInt(1)}
+	}
+
+	testCases := []struct {
+		name    string
+		records []spendRecord
+		want    []uint64
+	}{
+		{name: "no records", want: []uint64{}},
+		{
+			name:    "records in 7 days are kept",
+			records: []spendRecord{record(1, time.Hour), record(2, 6*24*time.Hour)},
+			want:    []uint64{1, 2},
+		},
+		{
+			name:    "records of 7 days ago are dropped",
+			records: []spendRecord{record(1, 8*24*time.Hour), record(2, time.Hour), record(3, 7*24*time.Hour)},
+			want:    []uint64{2},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			pk := &policyKeeper{Spent: map[string][]spendRecord{"f01000": tt.records}}
+			kept := pk.pruneSpent("f01000", now)
+			ids := make([]uint64, 0, len(kept))
+			for _, r := range kept {
+				ids = append(ids, r.ID)
+			}
+			assert.Equal(t, tt.want, ids)
+			assert.Equal(t, kept, pk.Spent["f01000"])
+		})
+	}
+}
+
+func TestCheckPolicy(t *testing.T) {
+	from, err := address.NewIDAddress(1000)
+	assert.NoError(t, err)
+	allowed, err := address.NewIDAddress(1001)
+	assert.NoError(t, err)
+	other, err := address.NewIDAddress(1002)
+	assert.NoError(t, err)
+	noPolicy, err := address.NewIDAddress(1003)
+	assert.NoError(t, err)
+
+	fil := func(n int64) abi.TokenAmount {
+		return big.Mul(big.NewInt(n), big.NewInt(1e18))
+	}
+	now := time.Now()
+
+	testCases := []struct {
+		name       string
+		msg        types.Message
+		spent      []spendRecord
+		operator   string
+		approvable bool
+		wantErr    bool
+		// wantApproval is true if an approval request should be created
+		wantApproval bool
+		// wantSpent is the count of spending records after the check
+		wantSpent int
+	}{
+		{
+			name:       "sender without policy",
+			msg:        types.Message{From: noPolicy, To: other, Value: fil(1000)},
+			approvable: true,
+		},
+		{
+			name:       "forbidden method",
+			msg:        types.Message{From: from, To: allowed, Value: big.Zero(), Method: builtin.MethodsMiner.ChangeOwnerAddress},
+			approvable: true,
+			wantErr:    true,
+		},
+		{
+			name:       "destination not allowed",
+			msg:        types.Message{From: from, To: other, Value: fil(1)},
+			approvable: true,
+			wantErr:    true,
+		},
+		{
+			name:       "within limits",
+			msg:        types.Message{From: from, To: allowed, Value: fil(5)},
+			spent:      []spendRecord{{ID: 100, Time: now.Add(-time.Hour), Value: fil(5)}},
+			approvable: true,
+			wantSpent:  2,
+		},
+		{
+			name:       "spent more than a day ago is out of daily limit",
+			msg:        types.Message{From: from, To: allowed, Value: fil(10)},
+			spent:      []spendRecord{{ID: 100, Time: now.Add(-25 * time.Hour), Value: fil(10)}},
+			approvable: true,
+			wantSpent:  2,
+		},
+		{
+			name:         "daily limit exceeded with operator",
+			msg:          types.Message{From: from, To: allowed, Value: fil(6)},
+			spent:        []spendRecord{{ID: 100, Time: now.Add(-time.Hour), Value: fil(5)}},
+			operator:     "alice",
+			approvable:   true,
+			wantErr:      true,
+			wantApproval: true,
+			wantSpent:    1,
+		},
+		{
+			name:         "weekly limit exceeded with operator",
+			msg:          types.Message{From: from, To: allowed, Value: fil(10)},
+			spent:        []spendRecord{{ID: 100, Time: now.Add(-3 * 24 * time.Hour), Value: fil(15)}},
+			operator:     "alice",
+			approvable:   true,
+			wantErr:      true,
+			wantApproval: true,
+			wantSpent:    1,
+		},
+		{
+			name:       "limit exceeded without operator",
+			msg:        types.Message{From: from, To: allowed, Value: fil(11)},
+			approvable: true,
+			wantErr:    true,
+		},
+		{
+			name:       "signed message exceeding limit",
+			msg:        types.Message{From: from, To: allowed, Value: fil(11)},
+			operator:   "alice",
+			approvable: false,
+			wantErr:    true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{}
+			cfg.Policy.Addresses = []config.AddressPolicy{{
+				Address:          from.String(),
+				DailyLimit:       "10 FIL",
+				WeeklyLimit:      "20 FIL",
+				AllowedTo:        []string{allowed.String()},
+				ForbiddenMethods: []uint64{uint64(builtin.MethodsMiner.ChangeOwnerAddress)},
+			}}
+			pk, err := newPolicyKeeper(cfg, filepath.Join(t.TempDir(), policyFile))
+			assert.NoError(t, err)
+			pk.Spent[from.String()] = tt.spent
+			s := &ServiceImpl{Node: &idNode{}, policy: pk}
+
+			ctx := context.Background()
+			if tt.operator != "" {
+				//lint:ignore SA1029 the operator is set by the same string key in route
+				ctx = context.WithValue(ctx, OperatorKey, tt.operator)
+			}
+			release, err := s.checkPolicy(ctx, &tt.msg, nil, tt.approvable)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Len(t, pk.Spent[from.String()], tt.wantSpent)
+			if tt.wantApproval {
+				assert.Len(t, pk.Approvals, 1)
+				assert.Equal(t, tt.operator, pk.Approvals[0].Requester)
+				assert.Equal(t, PolicyApprovalPending, pk.Approvals[0].State)
+			} else {
+				assert.Empty(t, pk.Approvals)
+			}
+
+			// releasing drops the spending record of the message
+			release()
+			if !tt.wantErr && tt.wantSpent > 0 {
+				assert.Len(t, pk.Spent[from.String()], tt.wantSpent-1)
+			}
+		})
+	}
+}
+
+func TestPolicyChangeOperator(t *testing.T) {
+	addr, err := address.NewIDAddress(1000)
+	assert.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		operator string
+		wantErr  bool
+	}{
+		{name: "without operator", wantErr: true},
+		{name: "with operator", operator: "alice"},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			cfg := config.DefaultConfig()
+			cfg.Path = filepath.Join(dir, "config.toml")
+			pk, err := newPolicyKeeper(cfg, filepath.Join(dir, policyFile))
+			assert.NoError(t, err)
+			s := &ServiceImpl{policy: pk, audit: newAuditLogger(filepath.Join(dir, auditFile))}
+
+			ctx := context.Background()
+			if tt.operator != "" {
+				//lint:ignore SA1029 the operator is set by the same string key in route
+				ctx = context.WithValue(ctx, OperatorKey, tt.operator)
+			}
+			err = s.PolicySet(ctx, &config.AddressPolicy{Address: addr.String(), DailyLimit: "10 FIL"})
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Empty(t, pk.policies())
+				assert.Error(t, s.PolicyRemove(ctx, &Address{Address: addr}))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, pk.policies(), 1)
+			assert.NoError(t, s.PolicyRemove(ctx, &Address{Address: addr}))
+			assert.Empty(t, pk.policies())
+
+			data, err := os.ReadFile(filepath.Join(dir, auditFile))
+			assert.NoError(t, err)
+			assert.Equal(t, 2, strings.Count(string(data), `"Operator":"`+tt.operator+`"`))
+		})
+	}
+}
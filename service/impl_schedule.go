@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	"go.uber.org/fx"
+
+	"github.com/ipfs-force-community/venus-tool/utils"
+)
+
+const scheduleFile = "schedule.json"
+
+// scheduler keeps the scheduled tasks in memory and persists them into a json file under the repo
+type scheduler struct {
+	lk     sync.Mutex
+	path   string
+	NextID uint64
+	Tasks  []*ScheduleTask
+}
+
+func newScheduler(path string) (*scheduler, error) {
+	s := &scheduler{
+		path:   path,
+		NextID: 1,
+	}
+	if _, err := utils.LoadJSON(path, s); err != nil {
+		return nil, fmt.Errorf("load schedule file failed: %s", err)
+	}
+	// the process exited while sending, it is unknown whether the message was sent
+	interrupted := false
+	for _, task := range s.Tasks {
+		if task.State == ScheduleTaskFiring {
+			task.State = ScheduleTaskFailed
+			task.Error = "interrupted while firing, check the messages of sender before adding it again"
+			task.UpdatedAt = time.Now()
+			interrupted = true
+		}
+	}
+	if interrupted {
+		if err := s.save(); err != nil {
+			return nil, fmt.Errorf("save schedule file failed: %s", err)
+		}
+	}
+	return s, nil
+}
+
+var scheduleTransitions = map[ScheduleTaskState][]ScheduleTaskState{
+	ScheduleTaskPending: {ScheduleTaskFiring, ScheduleTaskCanceled},
+	ScheduleTaskFiring:  {ScheduleTaskSent, ScheduleTaskFailed},
+}
+
+// transit moves the task to the state, fails if the transition is not allowed
+func (task *ScheduleTask) transit(to ScheduleTaskState) error {
+	for _, s := range scheduleTransitions[task.State] {
+		if s == to {
+			task.State = to
+			return nil
+		}
+	}
+	return fmt.Errorf("task %d is %s, can not be %s", task.ID, task.State, to)
+}
+
+// save must be called with lk held
+func (sc *scheduler) save() error {
+	return utils.SaveJSON(sc.path, sc)
+}
+
+func (sc *scheduler) add(task *ScheduleTask) error {
+	sc.lk.Lock()
+	defer sc.lk.Unlock()
+
+	task.ID = sc.NextID
+	sc.NextID++
+	sc.Tasks = append(sc.Tasks, task)
+	return sc.save()
+}
+
+func (sc *scheduler) list(state ScheduleTaskState) []*ScheduleTask {
+	sc.lk.Lock()
+	defer sc.lk.Unlock()
+
+	ret := make([]*ScheduleTask, 0, len(sc.Tasks))
+	for _, task := range sc.Tasks {
+		if state == "" || task.State == state {
+			t := *task
+			ret = append(ret, &t)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].ID < ret[j].ID })
+	return ret
+}
+
+func (sc *scheduler) update(id uint64, fn func(task *ScheduleTask) error) error {
+	sc.lk.Lock()
+	defer sc.lk.Unlock()
+
+	for _, task := range sc.Tasks {
+		if task.ID == id {
+			if err := fn(task); err != nil {
+				return err
+			}
+			task.UpdatedAt = time.Now()
+			return sc.save()
+		}
+	}
+	return fmt.Errorf("schedule task %d not found", id)
+}
+
+func (s *ServiceImpl) ScheduleAdd(ctx context.Context, req *ScheduleAddReq) (*ScheduleTask, error) {
+	if err := req.Trigger.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid trigger: %s", err)
+	}
+	if req.Msg.From == address.Undef || req.Msg.To == address.Undef {
+		return nil, fmt.Errorf("from and to of message are required")
+	}
+	if req.Msg.Value.Nil() {
+		req.Msg.Value = types.NewInt(0)
+	}
+	if err := s.resolveMethodName(ctx, &req.Msg); err != nil {
+		return nil, err
+	}
+	// decode params early, so that a bad task is rejected instead of failing when fired
+	if req.Msg.Params != nil {
+		if _, err := s.decodeMsgParams(ctx, *req.Msg.Params, req.Msg.To, req.Msg.Method); err != nil {
+			return nil, fmt.Errorf("decode params failed: %s", err)
+		}
+	}
+
+	now := time.Now()
+	task := &ScheduleTask{
+		Msg:       req.Msg,
+		Trigger:   req.Trigger,
+		State:     ScheduleTaskPending,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.scheduler.add(task); err != nil {
+		return nil, fmt.Errorf("save schedule task failed: %s", err)
+	}
+	log.Infof("add schedule task(%d): send from %s to %s %s", task.ID, task.Msg.From, task.Msg.To, task.Trigger)
+	return task, nil
+}
+
+func (s *ServiceImpl) ScheduleList(ctx context.Context, req *ScheduleListReq) ([]*ScheduleTask, error) {
+	return s.scheduler.list(req.State), nil
+}
+
+func (s *ServiceImpl) ScheduleCancel(ctx context.Context, req *ScheduleTaskID) error {
+	return s.scheduler.update(req.ID, func(task *ScheduleTask) error {
+		return task.transit(ScheduleTaskCanceled)
+	})
+}
+
+// StartScheduler watches head changes of the chain and fires the pending tasks whose trigger is satisfied
+func StartScheduler(lc fx.Lifecycle, s *ServiceImpl) {
+	ctx, cancel := context.WithCancel(context.Background())
+	lc.Append(fx.Hook{
+		OnStart: func(context.Context) error {
+			go s.runScheduler(ctx)
+			return nil
+		},
+		OnStop: func(context.Context) error {
+			cancel()
+			return nil
+		},
+	})
+}
+
+func (s *ServiceImpl) runScheduler(ctx context.Context) {
+	for {
+		notifs, err := s.Node.ChainNotify(ctx)
+		if err != nil {
+			log.Warnf("scheduler: subscribe head change failed: %s", err)
+		} else {
+			for changes := range notifs {
+				var head *types.TipSet
+				for _, change := range changes {
+					if change.Type == types.HCApply || change.Type == types.HCCurrent {
+						head = change.Val
+					}
+				}
+				if head != nil {
+					s.processSchedule(ctx, head)
+				}
+			}
+			log.Warn("scheduler: head change channel closed, resubscribe")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
+
+func (s *ServiceImpl) processSchedule(ctx context.Context, head *types.TipSet) {
+	for _, task := range s.scheduler.list(ScheduleTaskPending) {
+		ok, err := s.checkTrigger(ctx, &task.Trigger, head)
+		if err != nil {
+			log.Warnf("scheduler: check trigger of task(%d) failed: %s", task.ID, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		// claim the task before sending, so that it can not be canceled once the message may be sent
+		err = s.scheduler.update(task.ID, func(t *ScheduleTask) error {
+			return t.transit(ScheduleTaskFiring)
+		})
+		if err != nil {
+			log.Infof("scheduler: skip task(%d): %s", task.ID, err)
+			continue
+		}
+
+		msgID, sendErr := s.MsgSend(ctx, &task.Msg)
+		err = s.scheduler.update(task.ID, func(t *ScheduleTask) error {
+			if sendErr != nil {
+				t.Error = sendErr.Error()
+				return t.transit(ScheduleTaskFailed)
+			}
+			t.MsgID = msgID
+			return t.transit(ScheduleTaskSent)
+		})
+		if err != nil {
+			log.Errorf("scheduler: update task(%d) failed: %s", task.ID, err)
+		}
+		if sendErr != nil {
+			log.Errorf("scheduler: send message of task(%d) failed: %s", task.ID, sendErr)
+		} else {
+			log.Infof("scheduler: task(%d) fired at %d, message(%s)", task.ID, head.Height(), msgID)
+		}
+	}
+}
+
+func (s *ServiceImpl) checkTrigger(ctx context.Context, trigger *ScheduleTrigger, head *types.TipSet) (bool, error) {
+	switch trigger.Type {
+	case ScheduleAtEpoch:
+		return head.Height() >= trigger.Epoch, nil
+	case ScheduleAtTime:
+		return !time.Now().Before(trigger.Time), nil
+	case ScheduleBalanceBelow, ScheduleBalanceAbove:
+		actor, err := s.Node.StateGetActor(ctx, trigger.Address, head.Key())
+		if err != nil {
+			return false, err
+		}
+		if trigger.Type == ScheduleBalanceBelow {
+			return actor.Balance.LessThan(trigger.Balance), nil
+		}
+		return actor.Balance.GreaterThan(trigger.Balance), nil
+	}
+	return false, fmt.Errorf("unknown trigger type: %s", trigger.Type)
+}
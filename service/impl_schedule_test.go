@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	nodeV1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ipfs-force-community/venus-tool/utils"
+)
+
+type balanceNode struct {
+	nodeV1.FullNode
+	balance abi.TokenAmount
+}
+
+func (n *balanceNode) StateGetActor(ctx context.Context, addr address.Address, tsk types.TipSetKey) (*types.Actor, error) {
+	return &types.Actor{Balance: n.balance}, nil
+}
+
+func testTipSet(t *testing.T, height abi.ChainEpoch) *types.TipSet {
+	miner, err := address.NewIDAddress(1000)
+	assert.NoError(t, err)
+	c, err := cid.Decode("bafy2bzacecnamqgqmifpluoeldx7zzglxcljo6oja4vrmtj7432rphldpdmm2")
+	assert.NoError(t, err)
+	ts, err := types.NewTipSet([]*types.BlockHeader{{
+		Miner:                 miner,
+		Height:                height,
+		ParentWeight:          big.Zero(),
+		ParentBaseFee:         big.Zero(),
+		ParentStateRoot:       c,
+		ParentMessageReceipts: c,
+		Messages:              c,
+	}})
+	assert.NoError(t, err)
+	return ts
+}
+
+func TestCheckTrigger(t *testing.T) {
+	addr, err := address.NewIDAddress(1001)
+	assert.NoError(t, err)
+	s := &ServiceImpl{Node: &balanceNode{balance: big.NewInt(100)}}
+	head := testTipSet(t, 100)
+
+	testCases := []struct {
+		name    string
+		trigger ScheduleTrigger
+		want    bool
+		wantErr bool
+	}{
+		{name: "epoch reached", trigger: ScheduleTrigger{Type: ScheduleAtEpoch, Epoch: 100}, want: true},
+		{name: "epoch not reached", trigger: ScheduleTrigger{Type: ScheduleAtEpoch, Epoch: 101}},
+		{name: "time passed", trigger: ScheduleTrigger{Type: ScheduleAtTime, Time: time.Now().Add(-time.Minute)}, want: true},
+		{name: "time not passed", trigger: ScheduleTrigger{Type: ScheduleAtTime, Time: time.Now().Add(time.Hour)}},
+		{name: "balance below", trigger: ScheduleTrigger{Type: ScheduleBalanceBelow, Address: addr, Balance: big.NewInt(101)}, want: true},
+		{name: "balance equal is not below", trigger: ScheduleTrigger{Type: ScheduleBalanceBelow, Address: addr, Balance: big.NewInt(100)}},
+		{name: "balance above", trigger: ScheduleTrigger{Type: ScheduleBalanceAbove, Address: addr, Balance: big.NewInt(99)}, want: true},
+		{name: "balance equal is not above", trigger: ScheduleTrigger{Type: ScheduleBalanceAbove, Address: addr, Balance: big.NewInt(100)}},
+		{name: "unknown type", trigger: ScheduleTrigger{Type: "unknown"}, wantErr: true},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := s.checkTrigger(context.Background(), &tt.trigger, head)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestScheduleTaskTransit(t *testing.T) {
+	testCases := []struct {
+		from    ScheduleTaskState
+		to      ScheduleTaskState
+		wantErr bool
+	}{
+		{from: ScheduleTaskPending, to: ScheduleTaskFiring},
+		{from: ScheduleTaskPending, to: ScheduleTaskCanceled},
+		{from: ScheduleTaskPending, to: ScheduleTaskSent, wantErr: true},
+		{from: ScheduleTaskFiring, to: ScheduleTaskSent},
+		{from: ScheduleTaskFiring, to: ScheduleTaskFailed},
+		{from: ScheduleTaskFiring, to: ScheduleTaskCanceled, wantErr: true},
+		{from: ScheduleTaskFiring, to: ScheduleTaskFiring, wantErr: true},
+		{from: ScheduleTaskSent, to: ScheduleTaskCanceled, wantErr: true},
+		{from: ScheduleTaskCanceled, to: ScheduleTaskFiring, wantErr: true},
+		{from: ScheduleTaskFailed, to: ScheduleTaskFiring, wantErr: true},
+	}
+	for _, tt := range testCases {
+		t.Run(string(tt.from)+" to "+string(tt.to), func(t *testing.T) {
+			task := &ScheduleTask{State: tt.from}
+			err := task.transit(tt.to)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Equal(t, tt.from, task.State)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.to, task.State)
+		})
+	}
+}
+
+func TestSchedulerCancelFiring(t *testing.T) {
+	path := filepath.Join(t.TempDir(), scheduleFile)
+	sc, err := newScheduler(path)
+	assert.NoError(t, err)
+	s := &ServiceImpl{scheduler: sc}
+
+	task := &ScheduleTask{State: ScheduleTaskPending}
+	assert.NoError(t, sc.add(task))
+	assert.NoError(t, sc.update(task.ID, func(t *ScheduleTask) error {
+		return t.transit(ScheduleTaskFiring)
+	}))
+	assert.Error(t, s.ScheduleCancel(context.Background(), &ScheduleTaskID{ID: task.ID}))
+
+	// a task left firing by an interrupted process is failed on reload
+	sc, err = newScheduler(path)
+	assert.NoError(t, err)
+	tasks := sc.list("")
+	assert.Len(t, tasks, 1)
+	assert.Equal(t, ScheduleTaskFailed, tasks[0].State)
+	assert.NotEmpty(t, tasks[0].Error)
+
+	var saved scheduler
+	_, err = utils.LoadJSON(path, &saved)
+	assert.NoError(t, err)
+	assert.Equal(t, ScheduleTaskFailed, saved.Tasks[0].State)
+}
@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/exitcode"
+	nodeV1 "github.com/filecoin-project/venus/venus-shared/api/chain/v1"
+	"github.com/filecoin-project/venus/venus-shared/api/messager"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	msgTypes "github.com/filecoin-project/venus/venus-shared/types/messager"
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/assert"
+)
+
+// listMessager pages the messages like messager, filters supported by messager are ignored
+type listMessager struct {
+	messager.IMessager
+	msgs []*msgTypes.Message
+}
+
+func (m *listMessager) ListMessage(ctx context.Context, p *msgTypes.MsgQueryParams) ([]*msgTypes.Message, error) {
+	start, end := int(p.Offset), len(m.msgs)
+	if start > end {
+		start = end
+	}
+	if p.Limit > 0 && start+int(p.Limit) < end {
+		end = start + int(p.Limit)
+	}
+	return m.msgs[start:end], nil
+}
+
+// chainMsgNode keeps messages executed on chain, actors are not found so methods are not decoded
+type chainMsgNode struct {
+	nodeV1.FullNode
+	msgs []*types.Message
+}
+
+func (n *chainMsgNode) StateGetActor(ctx context.Context, actor address.Address, tsk types.TipSetKey) (*types.Actor, error) {
+	return nil, fmt.Errorf("actor not found")
+}
+
+func (n *chainMsgNode) StateListMessages(ctx context.Context, match *types.MessageMatch, tsk types.TipSetKey, toht abi.ChainEpoch) ([]cid.Cid, error) {
+	var ret []cid.Cid
+	for _, msg := range n.msgs {
+		if (match.From == address.Undef || msg.From == match.From) && (match.To == address.Undef || msg.To == match.To) {
+			ret = append(ret, msg.Cid())
+		}
+	}
+	return ret, nil
+}
+
+func (n *chainMsgNode) ChainGetMessage(ctx context.Context, msgID cid.Cid) (*types.Message, error) {
+	for _, msg := range n.msgs {
+		if msg.Cid().Equals(msgID) {
+			return msg, nil
+		}
+	}
+	return nil, fmt.Errorf("message not found")
+}
+
+func (n *chainMsgNode) StateSearchMsg(ctx context.Context, from types.TipSetKey, msg cid.Cid, limit abi.ChainEpoch, allowReplaced bool) (*types.MsgLookup, error) {
+	return &types.MsgLookup{Height: 10}, nil
+}
+
+func TestMsgQueryFiltered(t *testing.T) {
+	from, err := address.NewIDAddress(1000)
+	assert.NoError(t, err)
+	toA, err := address.NewIDAddress(1001)
+	assert.NoError(t, err)
+	toB, err := address.NewIDAddress(1002)
+	assert.NoError(t, err)
+
+	// messages to A are sparse, so they spread over pages of messager
+	var msgs []*msgTypes.Message
+	for i := 0; i < 3*msgPageSize; i++ {
+		to := toB
+		if i%100 == 0 {
+			to = toA
+		}
+		msg := &msgTypes.Message{ID: fmt.Sprintf("%d", i), Message: types.Message{From: from, To: to, Nonce: uint64(i)}}
+		if i%200 == 0 {
+			msg.Receipt = &types.MessageReceipt{ExitCode: exitcode.ErrForbidden}
+		} else {
+			msg.Receipt = &types.MessageReceipt{ExitCode: exitcode.Ok}
+		}
+		msgs = append(msgs, msg)
+	}
+	forbidden := exitcode.ErrForbidden
+
+	testCases := []struct {
+		name    string
+		req     MsgQueryReq
+		wantIDs []string
+	}{
+		{
+			name:    "no filter is paged by messager",
+			req:     MsgQueryReq{MsgQueryParams: msgTypes.MsgQueryParams{Offset: 1, Limit: 2}},
+			wantIDs: []string{"1", "2"},
+		},
+		{
+			name:    "matches in all pages",
+			req:     MsgQueryReq{To: toA},
+			wantIDs: []string{"0", "100", "200", "300", "400", "500", "600", "700", "800", "900", "1000", "1100", "1200", "1300", "1400"},
+		},
+		{
+			name:    "paged after filtering",
+			req:     MsgQueryReq{MsgQueryParams: msgTypes.MsgQueryParams{Offset: 4, Limit: 3}, To: toA},
+			wantIDs: []string{"400", "500", "600"},
+		},
+		{
+			name:    "filters combined",
+			req:     MsgQueryReq{MsgQueryParams: msgTypes.MsgQueryParams{Offset: 1}, To: toA, ExitCode: &forbidden},
+			wantIDs: []string{"200", "400", "600", "800", "1000", "1200", "1400"},
+		},
+		{
+			name: "method is not decoded",
+			req:  MsgQueryReq{To: toA, MethodName: "Send"},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ServiceImpl{Messager: &listMessager{msgs: msgs}, Node: &chainMsgNode{}}
+			got, err := s.MsgQuery(context.Background(), &tt.req)
+			assert.NoError(t, err)
+			ids := make([]string, 0, len(got))
+			for _, msg := range got {
+				ids = append(ids, msg.ID)
+			}
+			if len(tt.wantIDs) == 0 {
+				assert.Empty(t, ids)
+			} else {
+				assert.Equal(t, tt.wantIDs, ids)
+			}
+		})
+	}
+}
+
+func TestListChainMsgs(t *testing.T) {
+	fromA, err := address.NewIDAddress(1000)
+	assert.NoError(t, err)
+	fromB, err := address.NewIDAddress(1001)
+	assert.NoError(t, err)
+	fromC, err := address.NewIDAddress(1002)
+	assert.NoError(t, err)
+	to, err := address.NewIDAddress(1003)
+	assert.NoError(t, err)
+
+	node := &chainMsgNode{msgs: []*types.Message{
+		{From: fromA, To: to, Nonce: 1},
+		{From: fromB, To: to, Nonce: 2},
+		{From: fromC, To: to, Nonce: 3},
+		{From: fromA, To: fromB, Nonce: 4},
+	}}
+
+	testCases := []struct {
+		name       string
+		req        MsgQueryReq
+		wantErr    bool
+		wantNonces []uint64
+	}{
+		{name: "neither to nor from", req: MsgQueryReq{FromEpoch: 1}, wantErr: true},
+		{name: "no from epoch", req: MsgQueryReq{To: to}, wantErr: true},
+		{name: "to", req: MsgQueryReq{To: to, FromEpoch: 1}, wantNonces: []uint64{1, 2, 3}},
+		{
+			name:       "all of from",
+			req:        MsgQueryReq{MsgQueryParams: msgTypes.MsgQueryParams{From: []address.Address{fromA, fromB}}, FromEpoch: 1},
+			wantNonces: []uint64{1, 4, 2},
+		},
+		{
+			name:       "from and to",
+			req:        MsgQueryReq{MsgQueryParams: msgTypes.MsgQueryParams{From: []address.Address{fromA, fromC}}, To: to, FromEpoch: 1},
+			wantNonces: []uint64{1, 3},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &ServiceImpl{Node: node}
+			got, err := s.listChainMsgs(context.Background(), &tt.req)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			nonces := make([]uint64, 0, len(got))
+			for _, msg := range got {
+				nonces = append(nonces, msg.Nonce)
+				assert.Equal(t, msgTypes.OnChainMsg, msg.State)
+			}
+			assert.Equal(t, tt.wantNonces, nonces)
+		})
+	}
+}
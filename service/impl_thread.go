@@ -3,52 +3,153 @@ package service
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/ipfs-force-community/venus-tool/dep"
 )
 
-func (s *ServiceImpl) ThreadList(ctx context.Context) ([]*dep.ThreadInfo, error) {
+const (
+	// threadListConcurrency bounds the number of workers queried at the same time
+	threadListConcurrency = 16
+	// workerCallTimeout bounds every call to a worker
+	workerCallTimeout = 10 * time.Second
+	// threadListCacheTTL keeps the dashboard refreshes from querying every worker
+	threadListCacheTTL = 10 * time.Second
+)
+
+// threadCache keeps the last result of ThreadList, the lock is held while querying,
+// so concurrent requests wait for the result instead of querying the workers again
+type threadCache struct {
+	lk      sync.Mutex
+	resp    *ThreadListResp
+	expires time.Time
+}
+
+func (c *threadCache) invalidate() {
+	c.lk.Lock()
+	defer c.lk.Unlock()
+	c.resp = nil
+}
+
+func (s *ServiceImpl) ThreadList(ctx context.Context) (*ThreadListResp, error) {
 	if s.Damocles == nil {
 		return nil, ErrEmptyDamocles
 	}
-	var ret []*dep.ThreadInfo
+
+	s.threads.lk.Lock()
+	defer s.threads.lk.Unlock()
+	if s.threads.resp != nil && time.Now().Before(s.threads.expires) {
+		return s.threads.resp, nil
+	}
+
 	pingInfos, err := s.Damocles.WorkerPingInfoList(ctx)
 	if err != nil {
 		return nil, err
 	}
+
+	workers := make([]*WorkerThreads, len(pingInfos))
+	threads := make([][]dep.WorkerThreadInfo, len(pingInfos))
+	sem := make(chan struct{}, threadListConcurrency)
+	var wg sync.WaitGroup
 	for i := range pingInfos {
-		pingInfo := pingInfos[i]
-		workerCli, closer, err := dep.NewWorkerClient(ctx, &pingInfo)
-		if err != nil {
-			log.Warnw("create worker client failed", "error", err, "worker", pingInfo.Info.Name, "addr", pingInfo.Info.Dest)
-		}
-		defer closer()
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
 
-		details, err := workerCli.WorkerList()
-		if err != nil {
-			log.Warnw("get thread detail failed", "error", err, "worker", pingInfo.Info.Name, "addr", pingInfo.Info.Dest)
-		}
+			pingInfo := &pingInfos[i]
+			workers[i] = &WorkerThreads{
+				Info:     pingInfo.Info,
+				LastPing: pingInfo.LastPing,
+			}
+			details, err := listWorkerThreads(ctx, pingInfo)
+			if err != nil {
+				log.Warnw("get thread detail failed", "error", err, "worker", pingInfo.Info.Name, "addr", pingInfo.Info.Dest)
+				workers[i].Error = err.Error()
+				return
+			}
+			workers[i].Threads = len(details)
+			threads[i] = details
+		}(i)
+	}
+	wg.Wait()
 
-		for j := range details {
-			detail := details[j]
-			ret = append(ret, &dep.ThreadInfo{
-				WorkerInfo:       &pingInfo.Info,
-				WorkerThreadInfo: &detail,
-				LastPing:         pingInfo.LastPing,
+	ret := &ThreadListResp{
+		Workers:   workers,
+		UpdatedAt: time.Now(),
+	}
+	for i := range pingInfos {
+		for j := range threads[i] {
+			ret.Threads = append(ret.Threads, &dep.ThreadInfo{
+				WorkerInfo:       &pingInfos[i].Info,
+				WorkerThreadInfo: &threads[i][j],
+				LastPing:         pingInfos[i].LastPing,
 			})
-
 		}
-
 	}
+	sort.Slice(ret.Workers, func(i, j int) bool {
+		return ret.Workers[i].Info.Name < ret.Workers[j].Info.Name
+	})
+	sort.SliceStable(ret.Threads, func(i, j int) bool {
+		if ret.Threads[i].WorkerInfo.Name != ret.Threads[j].WorkerInfo.Name {
+			return ret.Threads[i].WorkerInfo.Name < ret.Threads[j].WorkerInfo.Name
+		}
+		return ret.Threads[i].Index < ret.Threads[j].Index
+	})
+
+	s.threads.resp = ret
+	s.threads.expires = ret.UpdatedAt.Add(threadListCacheTTL)
 	return ret, nil
 }
 
+func listWorkerThreads(ctx context.Context, pingInfo *dep.WorkerPingInfo) ([]dep.WorkerThreadInfo, error) {
+	workerCli, closer, err := dep.NewWorkerClient(ctx, pingInfo)
+	if err != nil {
+		return nil, fmt.Errorf("create worker client failed: %s", err)
+	}
+	defer closer()
+
+	var details []dep.WorkerThreadInfo
+	err = callWorker(ctx, func() error {
+		var err error
+		details, err = workerCli.WorkerList()
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list threads: %s", err)
+	}
+	return details, nil
+}
+
+// callWorker runs the call to a worker within workerCallTimeout,
+// the worker client takes no context, so the call is left to finish in background on timeout
+func callWorker(ctx context.Context, call func() error) error {
+	ctx, cancel := context.WithTimeout(ctx, workerCallTimeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- call()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		return err
+	}
+}
+
 func (s *ServiceImpl) ThreadStop(ctx context.Context, req *ThreadStopReq) error {
 	workerCli, closer, err := s.getWorkerClient(ctx, req.WorkerName)
 	if err != nil {
 		return err
 	}
 	defer closer()
+	defer s.threads.invalidate()
 	ok, err := workerCli.WorkerPause(req.Index)
 	if err != nil {
 		return err
@@ -66,6 +167,7 @@ func (s *ServiceImpl) ThreadStart(ctx context.Context, req *ThreadStartReq) erro
 		return err
 	}
 	defer closer()
+	defer s.threads.invalidate()
 	ok, err := workerCli.WorkerResume(index, state)
 	if err != nil {
 		return err
@@ -76,6 +178,170 @@ func (s *ServiceImpl) ThreadStart(ctx context.Context, req *ThreadStartReq) erro
 	return nil
 }
 
+const threadStatePaused = "Paused"
+
+// ThreadBulkControl pauses or resumes the threads matching the selector across workers,
+// pausing skips threads already paused and resuming only affects paused threads
+func (s *ServiceImpl) ThreadBulkControl(ctx context.Context, req *ThreadBulkControlReq) (*ThreadBulkControlResp, error) {
+	if req.Action != ThreadPause && req.Action != ThreadResume {
+		return nil, fmt.Errorf("unknown action %q, must be %s or %s", req.Action, ThreadPause, ThreadResume)
+	}
+	if req.Action == ThreadPause && req.SetState != nil {
+		return nil, fmt.Errorf("state can only be set when resuming threads")
+	}
+	sel := req.ThreadSelector
+	if !req.All && len(sel.Worker) == 0 && len(sel.Plan) == 0 && len(sel.Stage) == 0 && len(sel.State) == 0 {
+		return nil, fmt.Errorf("empty selector matches all threads in the cluster, set all to confirm")
+	}
+	operator, err := requireOperator(ctx)
+	if err != nil && !req.DryRun {
+		return nil, err
+	}
+
+	// always act on the latest state of threads
+	s.threads.invalidate()
+	list, err := s.ThreadList(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &ThreadBulkControlResp{DryRun: req.DryRun}
+	for _, w := range list.Workers {
+		if w.Error != "" && matchAny(sel.Worker, w.Info.Name) {
+			ret.FailedWorkers = append(ret.FailedWorkers, w)
+		}
+	}
+
+	// threads are grouped by worker to share the client
+	var workers []*dep.WorkerPingInfo
+	threads := make(map[string][]int)
+	for _, t := range list.Threads {
+		if !selectThread(&sel, req.Action, t) {
+			continue
+		}
+		jobStage := ""
+		if t.JobStage != nil {
+			jobStage = *t.JobStage
+		}
+
+		res := ThreadControlResult{
+			WorkerName:  t.WorkerInfo.Name,
+			Index:       uint64(t.Index),
+			Plan:        t.Plan,
+			JobState:    t.JobState,
+			JobStage:    jobStage,
+			ThreadState: t.ThreadState.String(),
+		}
+		if t.JobID != nil {
+			res.JobID = *t.JobID
+		}
+		if _, ok := threads[t.WorkerInfo.Name]; !ok {
+			workers = append(workers, &dep.WorkerPingInfo{Info: *t.WorkerInfo, LastPing: t.LastPing})
+		}
+		threads[t.WorkerInfo.Name] = append(threads[t.WorkerInfo.Name], len(ret.Results))
+		ret.Results = append(ret.Results, res)
+	}
+	ret.Matched = len(ret.Results)
+
+	if !req.DryRun {
+		sem := make(chan struct{}, threadListConcurrency)
+		var wg sync.WaitGroup
+		for _, w := range workers {
+			wg.Add(1)
+			go func(w *dep.WorkerPingInfo) {
+				defer wg.Done()
+				sem <- struct{}{}
+				defer func() { <-sem }()
+
+				// each worker only writes to the results of its own threads
+				results := threads[w.Info.Name]
+				workerCli, closer, err := dep.NewWorkerClient(ctx, w)
+				if err != nil {
+					for _, i := range results {
+						ret.Results[i].Error = fmt.Sprintf("create worker client failed: %s", err)
+					}
+					return
+				}
+				defer closer()
+
+				for _, i := range results {
+					res := &ret.Results[i]
+					var ok bool
+					err := callWorker(ctx, func() error {
+						var err error
+						if req.Action == ThreadPause {
+							ok, err = workerCli.WorkerPause(res.Index)
+						} else {
+							ok, err = workerCli.WorkerResume(res.Index, req.SetState)
+						}
+						return err
+					})
+					switch {
+					case err != nil:
+						res.Error = err.Error()
+					case !ok:
+						res.Error = fmt.Sprintf("%s rejected by worker", req.Action)
+					default:
+						res.Applied = true
+					}
+				}
+			}(w)
+		}
+		wg.Wait()
+		s.threads.invalidate()
+	}
+
+	for _, res := range ret.Results {
+		if res.Error != "" {
+			ret.Failed++
+		}
+		if res.Applied {
+			ret.Applied++
+		}
+	}
+
+	if !req.DryRun && ret.Matched > 0 {
+		err := s.audit.record("thread.bulkcontrol", operator, map[string]interface{}{
+			"Request": req,
+			"Applied": ret.Applied,
+			"Failed":  ret.Failed,
+			"Results": ret.Results,
+		})
+		if err != nil {
+			log.Errorf("record audit log failed: %s", err)
+		}
+	}
+	return ret, nil
+}
+
+// selectThread reports whether the thread is matched by the selector and can be acted on,
+// threads already paused are skipped when pausing, and threads not paused when resuming
+func selectThread(sel *ThreadSelector, action ThreadAction, t *dep.ThreadInfo) bool {
+	paused := t.ThreadState.State == threadStatePaused
+	if (action == ThreadPause) == paused {
+		return false
+	}
+	jobStage := ""
+	if t.JobStage != nil {
+		jobStage = *t.JobStage
+	}
+	return matchAny(sel.Worker, t.WorkerInfo.Name) && matchAny(sel.Plan, t.Plan) &&
+		matchAny(sel.Stage, jobStage) && matchAny(sel.State, t.JobState)
+}
+
+// matchAny reports whether v is one of values, empty values match all
+func matchAny(values []string, v string) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *ServiceImpl) getWorkerClient(ctx context.Context, workerName string) (*dep.WorkerClient, func(), error) {
 	if s.Damocles == nil {
 		return nil, nil, ErrEmptyDamocles
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ipfs-force-community/venus-tool/dep"
+)
+
+func TestMatchAny(t *testing.T) {
+	testCases := []struct {
+		name   string
+		values []string
+		v      string
+		want   bool
+	}{
+		{name: "empty values match all", v: "worker-1", want: true},
+		{name: "empty values match empty", v: "", want: true},
+		{name: "one of values", values: []string{"worker-1", "worker-2"}, v: "worker-2", want: true},
+		{name: "not in values", values: []string{"worker-1", "worker-2"}, v: "worker-3"},
+		{name: "empty not in values", values: []string{"worker-1"}, v: ""},
+		{name: "match is case sensitive", values: []string{"Worker-1"}, v: "worker-1"},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchAny(tt.values, tt.v))
+		})
+	}
+}
+
+func TestSelectThread(t *testing.T) {
+	thread := func(worker, plan, state string, stage *string, threadState string) *dep.ThreadInfo {
+		ret := &dep.ThreadInfo{
+			WorkerThreadInfo: &dep.WorkerThreadInfo{Plan: plan, JobState: state, JobStage: stage},
+			WorkerInfo:       &dep.WorkerInfo{Name: worker},
+		}
+		ret.ThreadState.State = threadState
+		return ret
+	}
+	stage := func(s string) *string { return &s }
+
+	running := thread("worker-1", "sealer", "Allocated", stage("PC1"), "Running")
+	paused := thread("worker-1", "sealer", "Allocated", stage("PC1"), threadStatePaused)
+	idle := thread("worker-2", "snapup", "Empty", nil, "Running")
+
+	testCases := []struct {
+		name   string
+		sel    ThreadSelector
+		action ThreadAction
+		thread *dep.ThreadInfo
+		want   bool
+	}{
+		{name: "empty selector pauses running", action: ThreadPause, thread: running, want: true},
+		{name: "pause skips paused", action: ThreadPause, thread: paused},
+		{name: "empty selector resumes paused", action: ThreadResume, thread: paused, want: true},
+		{name: "resume skips running", action: ThreadResume, thread: running},
+		{name: "worker matched", sel: ThreadSelector{Worker: []string{"worker-1"}}, action: ThreadPause, thread: running, want: true},
+		{name: "worker not matched", sel: ThreadSelector{Worker: []string{"worker-2"}}, action: ThreadPause, thread: running},
+		{name: "plan matched", sel: ThreadSelector{Plan: []string{"snapup", "sealer"}}, action: ThreadPause, thread: running, want: true},
+		{name: "plan not matched", sel: ThreadSelector{Plan: []string{"snapup"}}, action: ThreadPause, thread: running},
+		{name: "stage matched", sel: ThreadSelector{Stage: []string{"PC1"}}, action: ThreadPause, thread: running, want: true},
+		{name: "stage not matched", sel: ThreadSelector{Stage: []string{"PC2"}}, action: ThreadPause, thread: running},
+		{name: "no stage not matched by stage", sel: ThreadSelector{Stage: []string{"PC1"}}, action: ThreadPause, thread: idle},
+		{name: "state matched", sel: ThreadSelector{State: []string{"Empty"}}, action: ThreadPause, thread: idle, want: true},
+		{name: "state not matched", sel: ThreadSelector{State: []string{"Empty"}}, action: ThreadPause, thread: running},
+		{
+			name:   "all filters must match",
+			sel:    ThreadSelector{Worker: []string{"worker-1"}, Plan: []string{"sealer"}, Stage: []string{"PC1"}, State: []string{"Empty"}},
+			action: ThreadPause,
+			thread: running,
+		},
+		{
+			name:   "all filters matched",
+			sel:    ThreadSelector{Worker: []string{"worker-1"}, Plan: []string{"sealer"}, Stage: []string{"PC1"}, State: []string{"Allocated"}},
+			action: ThreadResume,
+			thread: paused,
+			want:   true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, selectThread(&tt.sel, tt.action, tt.thread))
+		})
+	}
+}
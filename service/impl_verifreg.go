@@ -0,0 +1,293 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-state-types/abi"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/filecoin-project/go-state-types/builtin"
+	"github.com/filecoin-project/venus/venus-shared/actors"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	msgTypes "github.com/filecoin-project/venus/venus-shared/types/messager"
+	cbg "github.com/whyrusleeping/cbor-gen"
+)
+
+// claimExpiringSoon is how long before the expiration a claim is regarded as expiring soon
+const claimExpiringSoon = 30 * builtin.EpochsInDay
+
+func (s *ServiceImpl) listClaims(ctx context.Context, mAddr address.Address) ([]VerifregClaim, abi.ChainEpoch, error) {
+	head, err := s.Node.ChainHead(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	claims, err := s.Node.StateGetClaims(ctx, mAddr, head.Key())
+	if err != nil {
+		return nil, 0, fmt.Errorf("get claims of %s failed: %s", mAddr, err)
+	}
+
+	ret := make([]VerifregClaim, 0, len(claims))
+	for id, claim := range claims {
+		expiration := claim.TermStart + claim.TermMax
+		ret = append(ret, VerifregClaim{
+			ID:           id,
+			Claim:        claim,
+			Expiration:   expiration,
+			ExpiringSoon: expiration-head.Height() < claimExpiringSoon,
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID < ret[j].ID
+	})
+	return ret, head.Height(), nil
+}
+
+// sectorClaimInfos finds out the earliest expiration of claims in each of the sectors,
+// claims are only fetched if some of the sectors have verified deals, and failures are logged and leave the result empty
+func (s *ServiceImpl) sectorClaimInfos(ctx context.Context, mAddr address.Address, sectors []*types.SectorOnChainInfo) map[abi.SectorNumber]SectorClaimInfo {
+	ret := make(map[abi.SectorNumber]SectorClaimInfo)
+	verified := make(map[abi.SectorNumber]struct{})
+	for _, sector := range sectors {
+		if sector != nil && !sector.VerifiedDealWeight.Nil() && sector.VerifiedDealWeight.GreaterThan(big.Zero()) {
+			verified[sector.SectorNumber] = struct{}{}
+		}
+	}
+	if len(verified) == 0 {
+		return ret
+	}
+
+	claims, _, err := s.listClaims(ctx, mAddr)
+	if err != nil {
+		log.Warnf("list claims of %s failed: %s", mAddr, err)
+		return ret
+	}
+
+	for _, claim := range claims {
+		if _, ok := verified[claim.Sector]; !ok {
+			continue
+		}
+		info, ok := ret[claim.Sector]
+		if !ok || claim.Expiration < info.ClaimExpiration {
+			ret[claim.Sector] = SectorClaimInfo{
+				ClaimExpiration:   claim.Expiration,
+				ClaimExpiringSoon: claim.ExpiringSoon,
+			}
+		}
+	}
+	return ret
+}
+
+func (s *ServiceImpl) VerifregClaimList(ctx context.Context, mAddr Address) ([]VerifregClaim, error) {
+	claims, _, err := s.listClaims(ctx, mAddr.Address)
+	return claims, err
+}
+
+func (s *ServiceImpl) VerifregAllocationList(ctx context.Context, req *VerifregAllocationListReq) ([]VerifregAllocation, error) {
+	miners := req.Miner
+	if len(miners) == 0 {
+		var err error
+		miners, err = s.listMiner(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	head, err := s.Node.ChainHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	providers := make(map[abi.ActorID]address.Address, len(miners))
+	for _, m := range miners {
+		id, err := s.minerActorID(ctx, m)
+		if err != nil {
+			return nil, err
+		}
+		providers[id] = m
+	}
+
+	clients := req.Client
+	if len(clients) == 0 {
+		verified := true
+		seen := make(map[address.Address]struct{})
+		for _, m := range miners {
+			deals, err := s.listStorageDeals(ctx, m, &StorageDealListReq{Verified: &verified})
+			if err != nil {
+				return nil, fmt.Errorf("list deals of %s failed: %s", m, err)
+			}
+			for _, deal := range deals {
+				client := deal.ClientDealProposal.Proposal.Client
+				if _, ok := seen[client]; !ok {
+					seen[client] = struct{}{}
+					clients = append(clients, client)
+				}
+			}
+		}
+	}
+
+	ret := make([]VerifregAllocation, 0)
+	for _, client := range clients {
+		allocations, err := s.Node.StateGetAllocations(ctx, client, head.Key())
+		if err != nil {
+			return nil, fmt.Errorf("get allocations of %s failed: %s", client, err)
+		}
+		for id, allocation := range allocations {
+			provider, ok := providers[allocation.Provider]
+			if !ok {
+				continue
+			}
+			ret = append(ret, VerifregAllocation{
+				ID:           id,
+				Allocation:   allocation,
+				ClientAddr:   client,
+				ProviderAddr: provider,
+				Expired:      allocation.Expiration < head.Height(),
+			})
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].ID < ret[j].ID
+	})
+	return ret, nil
+}
+
+func (s *ServiceImpl) minerActorID(ctx context.Context, mAddr address.Address) (abi.ActorID, error) {
+	id, err := s.Node.StateLookupID(ctx, mAddr, types.EmptyTSK)
+	if err != nil {
+		return 0, fmt.Errorf("lookup id of %s failed: %s", mAddr, err)
+	}
+	actorID, err := address.IDFromAddress(id)
+	if err != nil {
+		return 0, err
+	}
+	return abi.ActorID(actorID), nil
+}
+
+func (s *ServiceImpl) VerifregExtendClaimTerms(ctx context.Context, req *VerifregExtendClaimTermsReq) (*VerifregClaimsResp, error) {
+	if len(req.ClaimIDs) == 0 {
+		return nil, fmt.Errorf("no claim to extend")
+	}
+	provider, err := s.minerActorID(ctx, req.Miner)
+	if err != nil {
+		return nil, err
+	}
+	// only the client of a claim is allowed to extend it
+	client, err := s.minerActorID(ctx, req.From)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, _, err := s.listClaims(ctx, req.Miner)
+	if err != nil {
+		return nil, err
+	}
+	claimsByID := make(map[types.ClaimId]VerifregClaim, len(claims))
+	for _, claim := range claims {
+		claimsByID[claim.ID] = claim
+	}
+
+	params := &types.ExtendClaimTermsParams{}
+	for _, id := range req.ClaimIDs {
+		claim, ok := claimsByID[id]
+		if !ok {
+			return nil, fmt.Errorf("claim %d of %s not found", id, req.Miner)
+		}
+		if claim.Client != client {
+			return nil, fmt.Errorf("claim %d can only be extended by its client f0%d, not %s", id, claim.Client, req.From)
+		}
+		if req.TermMax <= claim.TermMax {
+			return nil, fmt.Errorf("new term max %d of claim %d should be greater than the current %d", req.TermMax, id, claim.TermMax)
+		}
+		params.Terms = append(params.Terms, types.ClaimTerm{
+			Provider: provider,
+			ClaimId:  id,
+			TermMax:  req.TermMax,
+		})
+	}
+
+	msg, err := s.pushVerifregMsg(ctx, req.From, builtin.MethodsVerifiedRegistry.ExtendClaimTerms, params)
+	if err != nil {
+		return nil, err
+	}
+	var ret types.ExtendClaimTermsReturn
+	if err := ret.UnmarshalCBOR(bytes.NewReader(msg.Receipt.Return)); err != nil {
+		return nil, fmt.Errorf("decode return of message(%s) failed: %s", msg.ID, err)
+	}
+	return claimsResp(msg.ID, req.ClaimIDs, types.BatchReturn(ret)), nil
+}
+
+func (s *ServiceImpl) VerifregRemoveExpiredClaims(ctx context.Context, req *VerifregRemoveExpiredClaimsReq) (*VerifregClaimsResp, error) {
+	provider, err := s.minerActorID(ctx, req.Miner)
+	if err != nil {
+		return nil, err
+	}
+
+	claimIDs := req.ClaimIDs
+	if len(claimIDs) == 0 {
+		claims, height, err := s.listClaims(ctx, req.Miner)
+		if err != nil {
+			return nil, err
+		}
+		for _, claim := range claims {
+			if claim.Expiration < height {
+				claimIDs = append(claimIDs, claim.ID)
+			}
+		}
+		if len(claimIDs) == 0 {
+			return nil, fmt.Errorf("no expired claim of %s", req.Miner)
+		}
+	}
+
+	msg, err := s.pushVerifregMsg(ctx, req.From, builtin.MethodsVerifiedRegistry.RemoveExpiredClaims, &types.RemoveExpiredClaimsParams{
+		Provider: provider,
+		ClaimIds: claimIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ret types.RemoveExpiredClaimsReturn
+	if err := ret.UnmarshalCBOR(bytes.NewReader(msg.Receipt.Return)); err != nil {
+		return nil, fmt.Errorf("decode return of message(%s) failed: %s", msg.ID, err)
+	}
+	// the failures are indexed by the claims considered, which are the claims requested
+	considered := make([]types.ClaimId, 0, len(ret.Considered))
+	for _, id := range ret.Considered {
+		considered = append(considered, types.ClaimId(id))
+	}
+	return claimsResp(msg.ID, considered, ret.Results), nil
+}
+
+// claimsResp maps the failures in the batch return to the claims they are indexed by
+func claimsResp(msgID string, ids []types.ClaimId, ret types.BatchReturn) *VerifregClaimsResp {
+	resp := &VerifregClaimsResp{
+		MsgID:     msgID,
+		Succeeded: ret.SuccessCount,
+		Failed:    make([]VerifregClaimFailure, 0, len(ret.FailCodes)),
+	}
+	for _, fc := range ret.FailCodes {
+		failure := VerifregClaimFailure{Code: fc.Code}
+		if fc.Idx < uint64(len(ids)) {
+			failure.ID = ids[fc.Idx]
+		}
+		resp.Failed = append(resp.Failed, failure)
+	}
+	return resp
+}
+
+func (s *ServiceImpl) pushVerifregMsg(ctx context.Context, from address.Address, method abi.MethodNum, params cbg.CBORMarshaler) (*msgTypes.Message, error) {
+	param, aErr := actors.SerializeParams(params)
+	if aErr != nil {
+		return nil, fmt.Errorf("serialize params failed: %s", aErr)
+	}
+
+	return s.PushMessageAndWait(ctx, &types.Message{
+		From:   from,
+		To:     builtin.VerifiedRegistryActorAddr,
+		Method: method,
+		Params: param,
+		Value:  big.Zero(),
+	}, nil)
+}
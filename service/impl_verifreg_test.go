@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/exitcode"
+	"github.com/filecoin-project/venus/venus-shared/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimsResp(t *testing.T) {
+	ids := []types.ClaimId{10, 11, 12}
+
+	testCases := []struct {
+		name string
+		ret  types.BatchReturn
+		want []VerifregClaimFailure
+	}{
+		{
+			name: "all succeeded",
+			ret:  types.BatchReturn{SuccessCount: 3},
+			want: []VerifregClaimFailure{},
+		},
+		{
+			name: "failures are mapped by index",
+			ret: types.BatchReturn{SuccessCount: 1, FailCodes: []types.FailCode{
+				{Idx: 0, Code: exitcode.ErrForbidden},
+				{Idx: 2, Code: exitcode.ErrNotFound},
+			}},
+			want: []VerifregClaimFailure{
+				{ID: 10, Code: exitcode.ErrForbidden},
+				{ID: 12, Code: exitcode.ErrNotFound},
+			},
+		},
+		{
+			name: "all failed",
+			ret: types.BatchReturn{FailCodes: []types.FailCode{
+				{Idx: 0, Code: exitcode.ErrForbidden},
+				{Idx: 1, Code: exitcode.ErrForbidden},
+				{Idx: 2, Code: exitcode.ErrForbidden},
+			}},
+			want: []VerifregClaimFailure{
+				{ID: 10, Code: exitcode.ErrForbidden},
+				{ID: 11, Code: exitcode.ErrForbidden},
+				{ID: 12, Code: exitcode.ErrForbidden},
+			},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := claimsResp("msg", ids, tt.ret)
+			assert.Equal(t, "msg", resp.MsgID)
+			assert.Equal(t, tt.ret.SuccessCount, resp.Succeeded)
+			assert.Equal(t, tt.want, resp.Failed)
+		})
+	}
+}
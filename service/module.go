@@ -1,11 +1,31 @@
 package service
 
 import (
+	"path/filepath"
+
 	"github.com/ipfs-force-community/venus-tool/dep"
 	"github.com/ipfs-force-community/venus-tool/pkg/multisig"
 )
 
 func NewService(params dep.ServiceParams) (*ServiceImpl, error) {
+	repoPath := filepath.Dir(params.Config.Path)
+	sched, err := newScheduler(filepath.Join(repoPath, scheduleFile))
+	if err != nil {
+		return nil, err
+	}
+	policy, err := newPolicyKeeper(params.Config, filepath.Join(repoPath, policyFile))
+	if err != nil {
+		return nil, err
+	}
+	inbox, err := newMsigInbox(params.Config, filepath.Join(repoPath, msigInboxFile))
+	if err != nil {
+		return nil, err
+	}
+	signedMsgs, err := newSignedMsgIndex(filepath.Join(repoPath, signedMsgFile))
+	if err != nil {
+		return nil, err
+	}
+
 	return &ServiceImpl{
 		Messager: params.Messager,
 		Market:   params.Market,
@@ -16,5 +36,12 @@ func NewService(params dep.ServiceParams) (*ServiceImpl, error) {
 		Miner:    params.Miner,
 
 		Multisig: multisig.NewMultiSig(params.Node),
+		Config:   params.Config,
+
+		scheduler:  sched,
+		policy:     policy,
+		msigInbox:  inbox,
+		signedMsgs: signedMsgs,
+		audit:      newAuditLogger(filepath.Join(repoPath, auditFile)),
 	}, nil
 }
@@ -9,7 +9,7 @@ import (
 	"github.com/filecoin-project/go-fil-markets/storagemarket"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/dline"
-	"github.com/ipfs-force-community/venus-tool/dep"
+	"github.com/ipfs-force-community/venus-tool/repo/config"
 	cid "github.com/ipfs/go-cid"
 
 	"github.com/filecoin-project/venus/venus-shared/types"
@@ -18,61 +18,103 @@ import (
 
 type IServiceStruct struct {
 	Internal struct {
-		AddrInfo                   func(ctx context.Context, addr Address) (*AddrsResp, error)                                         ` GET:"/addr/info/:Address"`
-		AddrList                   func(ctx context.Context) ([]*AddrsResp, error)                                                     ` GET:"/addr/list"`
-		AddrOperate                func(ctx context.Context, params *AddrsOperateReq) error                                            ` PUT:"/addr/operate"`
-		ChainGetActor              func(ctx context.Context, addr address.Address) (*types.Actor, error)                               ` GET:"/chain/actor"`
-		ChainGetHead               func(ctx context.Context) (*types.TipSet, error)                                                    ` GET:"/chain/head"`
-		ChainGetNetworkName        func(ctx context.Context) (types.NetworkName, error)                                                ` GET:"/chain/networkname"`
-		MinedBlockList             func(ctx context.Context, req MinedBlockListReq) (MinedBlockListResp, error)                        ` GET:"/minedblock/list"`
-		MinerConfirmBeneficiary    func(ctx context.Context, req *MinerConfirmBeneficiaryReq) (confirmor address.Address, err error)   ` PUT:"/miner/confirmbeneficiary"`
-		MinerConfirmOwner          func(ctx context.Context, p *MinerSetOwnerReq) (oldOwner address.Address, err error)                ` PUT:"/miner/confirmowner"`
-		MinerConfirmWorker         func(ctx context.Context, req *MinerSetWorkerReq) error                                             ` PUT:"/miner/confirmworker"`
-		MinerCreate                func(ctx context.Context, params *MinerCreateReq) (address.Address, error)                          ` POST:"/miner/create"`
-		MinerGetDeadlines          func(ctx context.Context, mAddr address.Address) (*dline.Info, error)                               ` GET:"/miner/deadline"`
-		MinerGetRetrievalAsk       func(ctx context.Context, mAddr address.Address) (*retrievalmarket.Ask, error)                      ` GET:"/miner/retrievalask"`
-		MinerGetStorageAsk         func(ctx context.Context, mAddr address.Address) (*storagemarket.StorageAsk, error)                 ` GET:"/miner/storageask"`
-		MinerInfo                  func(ctx context.Context, mAddr Address) (*MinerInfoResp, error)                                    ` GET:"/miner/info/:Address"`
-		MinerList                  func(ctx context.Context) ([]address.Address, error)                                                ` GET:"/miner/list"`
-		MinerSetBeneficiary        func(ctx context.Context, req *MinerSetBeneficiaryReq) (*types.PendingBeneficiaryChange, error)     ` PUT:"/miner/beneficiary"`
-		MinerSetControllers        func(ctx context.Context, req *MinerSetControllersReq) (oldController []address.Address, err error) ` PUT:"/miner/controllers"`
-		MinerSetOwner              func(ctx context.Context, p *MinerSetOwnerReq) error                                                ` PUT:"/miner/owner"`
-		MinerSetRetrievalAsk       func(ctx context.Context, p *MinerSetRetrievalAskReq) error                                         ` PUT:"/miner/retrievalask"`
-		MinerSetStorageAsk         func(ctx context.Context, p *MinerSetAskReq) error                                                  ` PUT:"/miner/storageask"`
-		MinerSetWorker             func(ctx context.Context, req *MinerSetWorkerReq) (WorkerChangeEpoch abi.ChainEpoch, err error)     ` PUT:"/miner/worker"`
-		MinerWinCount              func(ctx context.Context, req *MinerWinCountReq) (MinerWinCountResp, error)                         ` GET:"/miner/wincount"`
-		MinerWithdrawFromMarket    func(ctx context.Context, req *MinerWithdrawBalanceReq) (abi.TokenAmount, error)                    ` PUT:"/miner/withdrawmarket"`
-		MinerWithdrawToBeneficiary func(ctx context.Context, req *MinerWithdrawBalanceReq) (abi.TokenAmount, error)                    ` PUT:"/miner/withdrawbeneficiary"`
-		Msg                        func(ctx context.Context, id MsgID) (*MsgResp, error)                                               ` GET:"/msg/:ID"`
-		MsgDecodeParam2Json        func(ctx context.Context, req *MsgDecodeParamReq) ([]byte, error)                                   ` POST:"/msg/decodeparam"`
-		MsgGetMethodName           func(ctx context.Context, req *MsgGetMethodNameReq) (string, error)                                 ` GET:"/msg/getmethodname"`
-		MsgMarkBad                 func(ctx context.Context, req *MsgID) error                                                         ` POST:"/msg/markbad/:ID"`
-		MsgQuery                   func(ctx context.Context, params *MsgQueryReq) ([]*MsgResp, error)                                  ` GET:"/msg/query"`
-		MsgReplace                 func(ctx context.Context, params *MsgReplaceReq) (cid.Cid, error)                                   ` POST:"/msg/replace"`
-		MsgSend                    func(ctx context.Context, params *MsgSendReq) (string, error)                                       ` POST:"/msg/send"`
-		MsigAddSigner              func(ctx context.Context, req *MultisigChangeSignerReq) (*types.ProposeReturn, error)               ` POST:"/msig/signer/ass"`
-		MsigApprove                func(ctx context.Context, req *MultisigApproveReq) (*types.ApproveReturn, error)                    ` POST:"/msig/approve"`
-		MsigCancel                 func(ctx context.Context, req *MultisigCancelReq) error                                             ` POST:"/msig/cancel"`
-		MsigCreate                 func(ctx context.Context, req *MultisigCreateReq) (address.Address, error)                          ` POST:"/msig/create"`
-		MsigInfo                   func(ctx context.Context, msig address.Address) (*types.MsigInfo, error)                            ` GET:"/msig/info"`
-		MsigListPropose            func(ctx context.Context, msig address.Address) ([]*types.MsigTransaction, error)                   ` GET:"/msig/proposes"`
-		MsigPropose                func(ctx context.Context, req *MultisigProposeReq) (*types.ProposeReturn, error)                    ` POST:"/msig/propose"`
-		MsigRemoveSigner           func(ctx context.Context, req *MultisigChangeSignerReq) (*types.ProposeReturn, error)               ` POST:"/msig/signer/remove"`
-		MsigSwapSigner             func(ctx context.Context, req *MultisigSwapSignerReq) (*types.ProposeReturn, error)                 ` POST:"/msig/signer/swap"`
-		RetrievalDealList          func(ctx context.Context) ([]marketTypes.ProviderDealState, error)                                  ` GET:"/deal/retrieval"`
-		Search                     func(ctx context.Context, req SearchReq) (*SearchResp, error)                                       ` GET:"/search/:Key"`
-		SectorExtend               func(ctx context.Context, req SectorExtendReq) error                                                ` PUT:"/sector/extend"`
-		SectorGet                  func(ctx context.Context, req SectorGetReq) ([]*SectorResp, error)                                  ` GET:"/sector/get"`
-		SectorList                 func(ctx context.Context, req SectorListReq) ([]*types.SectorOnChainInfo, error)                    ` GET:"/sector/list"`
-		SectorSum                  func(ctx context.Context, miner Address) (uint64, error)                                            ` GET:"/sector/sum"`
-		StorageDeal                func(ctx context.Context, proposalCid Cid) (*marketTypes.MinerDeal, error)                          ` GET:"/deal/storage/info/:Cid"`
-		StorageDealList            func(ctx context.Context, miner Address) ([]marketTypes.MinerDeal, error)                           ` GET:"/deal/storage/:Address"`
-		StorageDealUpdateState     func(ctx context.Context, req StorageDealUpdateStateReq) error                                      ` PUT:"/deal/storage/state"`
-		ThreadList                 func(ctx context.Context) ([]*dep.ThreadInfo, error)                                                ` GET:"/thread/list"`
-		ThreadStart                func(ctx context.Context, req *ThreadStartReq) error                                                ` PUT:"/thread/start"`
-		ThreadStop                 func(ctx context.Context, req *ThreadStopReq) error                                                 ` PUT:"/thread/stop"`
-		WalletList                 func(ctx context.Context) ([]address.Address, error)                                                ` GET:"/wallet/list"`
-		WalletSignRecordQuery      func(ctx context.Context, req *WalletSignRecordQueryReq) ([]WalletSignRecordResp, error)            ` GET:"/wallet/signrecord"`
+		AddrInfo                    func(ctx context.Context, addr Address) (*AddrsResp, error)                                         ` GET:"/addr/info/:Address"`
+		AddrList                    func(ctx context.Context) ([]*AddrsResp, error)                                                     ` GET:"/addr/list"`
+		AddrOperate                 func(ctx context.Context, params *AddrsOperateReq) error                                            ` PUT:"/addr/operate"`
+		AskDrift                    func(ctx context.Context) ([]AskDiff, error)                                                        ` GET:"/ask/drift"`
+		AskTemplateApply            func(ctx context.Context, req *AskTemplateApplyReq) ([]AskDiff, error)                              ` POST:"/ask/template/apply"`
+		AskTemplateList             func(ctx context.Context) ([]config.AskTemplate, error)                                             ` GET:"/ask/template/list"`
+		AskTemplateRemove           func(ctx context.Context, req *AskTemplateRemoveReq) error                                          ` POST:"/ask/template/remove"`
+		AskTemplateSet              func(ctx context.Context, req *config.AskTemplate) error                                            ` PUT:"/ask/template/set"`
+		ChainGetActor               func(ctx context.Context, addr address.Address) (*types.Actor, error)                               ` GET:"/chain/actor"`
+		ChainGetHead                func(ctx context.Context) (*types.TipSet, error)                                                    ` GET:"/chain/head"`
+		ChainGetNetworkName         func(ctx context.Context) (types.NetworkName, error)                                                ` GET:"/chain/networkname"`
+		DealAssignPiece             func(ctx context.Context, req *DealAssignPieceReq) error                                            ` POST:"/deal/piece/assign"`
+		DealImport                  func(ctx context.Context, req *DealImportReq) ([]DealImportResult, error)                           ` POST:"/deal/storage/import"`
+		DealReconcile               func(ctx context.Context, req *DealReconcileReq) (*DealReconcileResp, error)                        ` POST:"/deal/reconcile"`
+		DealStats                   func(ctx context.Context, req *DealStatsReq) (*DealStatsResp, error)                                ` GET:"/deal/stats"`
+		DealUnassignedPieces        func(ctx context.Context, req *DealUnassignedPiecesReq) ([]marketTypes.MinerDeal, error)            ` GET:"/deal/piece/unassigned"`
+		MarketAddBalance            func(ctx context.Context, req *MarketAddBalanceReq) (abi.TokenAmount, error)                        ` PUT:"/market/addbalance"`
+		MarketCollateralPlan        func(ctx context.Context, mAddr Address) (*MarketCollateralPlanResp, error)                         ` GET:"/market/collateralplan/:Address"`
+		MinedBlockList              func(ctx context.Context, req MinedBlockListReq) (MinedBlockListResp, error)                        ` GET:"/minedblock/list"`
+		MinerConfirmBeneficiary     func(ctx context.Context, req *MinerConfirmBeneficiaryReq) (confirmor address.Address, err error)   ` PUT:"/miner/confirmbeneficiary"`
+		MinerConfirmOwner           func(ctx context.Context, p *MinerSetOwnerReq) (oldOwner address.Address, err error)                ` PUT:"/miner/confirmowner"`
+		MinerConfirmWorker          func(ctx context.Context, req *MinerSetWorkerReq) error                                             ` PUT:"/miner/confirmworker"`
+		MinerCreate                 func(ctx context.Context, params *MinerCreateReq) (address.Address, error)                          ` POST:"/miner/create"`
+		MinerGetDeadlines           func(ctx context.Context, mAddr address.Address) (*dline.Info, error)                               ` GET:"/miner/deadline"`
+		MinerGetRetrievalAsk        func(ctx context.Context, mAddr address.Address) (*retrievalmarket.Ask, error)                      ` GET:"/miner/retrievalask"`
+		MinerGetStorageAsk          func(ctx context.Context, mAddr address.Address) (*storagemarket.StorageAsk, error)                 ` GET:"/miner/storageask"`
+		MinerInfo                   func(ctx context.Context, mAddr Address) (*MinerInfoResp, error)                                    ` GET:"/miner/info/:Address"`
+		MinerList                   func(ctx context.Context) ([]address.Address, error)                                                ` GET:"/miner/list"`
+		MinerSetBeneficiary         func(ctx context.Context, req *MinerSetBeneficiaryReq) (*types.PendingBeneficiaryChange, error)     ` PUT:"/miner/beneficiary"`
+		MinerSetControllers         func(ctx context.Context, req *MinerSetControllersReq) (oldController []address.Address, err error) ` PUT:"/miner/controllers"`
+		MinerSetOwner               func(ctx context.Context, p *MinerSetOwnerReq) error                                                ` PUT:"/miner/owner"`
+		MinerSetRetrievalAsk        func(ctx context.Context, p *MinerSetRetrievalAskReq) error                                         ` PUT:"/miner/retrievalask"`
+		MinerSetStorageAsk          func(ctx context.Context, p *MinerSetAskReq) error                                                  ` PUT:"/miner/storageask"`
+		MinerSetWorker              func(ctx context.Context, req *MinerSetWorkerReq) (WorkerChangeEpoch abi.ChainEpoch, err error)     ` PUT:"/miner/worker"`
+		MinerWinCount               func(ctx context.Context, req *MinerWinCountReq) (MinerWinCountResp, error)                         ` GET:"/miner/wincount"`
+		MinerWithdrawFromMarket     func(ctx context.Context, req *MinerWithdrawBalanceReq) (abi.TokenAmount, error)                    ` PUT:"/miner/withdrawmarket"`
+		MinerWithdrawToBeneficiary  func(ctx context.Context, req *MinerWithdrawBalanceReq) (abi.TokenAmount, error)                    ` PUT:"/miner/withdrawbeneficiary"`
+		Msg                         func(ctx context.Context, id MsgID) (*MsgResp, error)                                               ` GET:"/msg/:ID"`
+		MsgBuild                    func(ctx context.Context, req *MsgBuildReq) (*MsgBuildResp, error)                                  ` POST:"/msg/build"`
+		MsgDecodeParam2Json         func(ctx context.Context, req *MsgDecodeParamReq) ([]byte, error)                                   ` POST:"/msg/decodeparam"`
+		MsgGetMethodName            func(ctx context.Context, req *MsgGetMethodNameReq) (string, error)                                 ` GET:"/msg/getmethodname"`
+		MsgMarkBad                  func(ctx context.Context, req *MsgID) error                                                         ` POST:"/msg/markbad/:ID"`
+		MsgParamsSchema             func(ctx context.Context, req *MsgParamsSchemaReq) (*MsgParamsSchemaResp, error)                    ` GET:"/msg/paramsschema"`
+		MsgQuery                    func(ctx context.Context, params *MsgQueryReq) ([]*MsgResp, error)                                  ` GET:"/msg/query"`
+		MsgReplace                  func(ctx context.Context, params *MsgReplaceReq) (cid.Cid, error)                                   ` POST:"/msg/replace"`
+		MsgSend                     func(ctx context.Context, params *MsgSendReq) (string, error)                                       ` POST:"/msg/send"`
+		MsgSubmitSigned             func(ctx context.Context, req *MsgSubmitSignedReq) (cid.Cid, error)                                 ` POST:"/msg/submitsigned"`
+		MsigAddSigner               func(ctx context.Context, req *MultisigChangeSignerReq) (*types.ProposeReturn, error)               ` POST:"/msig/signer/ass"`
+		MsigApprove                 func(ctx context.Context, req *MultisigApproveReq) (*types.ApproveReturn, error)                    ` POST:"/msig/approve"`
+		MsigAvailable               func(ctx context.Context, msig address.Address) (abi.TokenAmount, error)                            ` GET:"/msig/available"`
+		MsigCancel                  func(ctx context.Context, req *MultisigCancelReq) error                                             ` POST:"/msig/cancel"`
+		MsigChangeThreshold         func(ctx context.Context, req *MultisigChangeThresholdReq) (*types.ProposeReturn, error)            ` POST:"/msig/threshold"`
+		MsigCreate                  func(ctx context.Context, req *MultisigCreateReq) (*MultisigCreateResp, error)                      ` POST:"/msig/create"`
+		MsigHistory                 func(ctx context.Context, req *MsigHistoryReq) (*MsigHistoryResp, error)                            ` GET:"/msig/history"`
+		MsigInbox                   func(ctx context.Context, req *MsigInboxReq) ([]*MsigInboxItem, error)                              ` GET:"/msig/inbox"`
+		MsigInfo                    func(ctx context.Context, msig address.Address) (*types.MsigInfo, error)                            ` GET:"/msig/info"`
+		MsigListPropose             func(ctx context.Context, msig address.Address) ([]*MsigTransaction, error)                         ` GET:"/msig/proposes"`
+		MsigLockBalance             func(ctx context.Context, req *MultisigLockBalanceReq) (*types.ProposeReturn, error)                ` POST:"/msig/lock"`
+		MsigPropose                 func(ctx context.Context, req *MultisigProposeReq) (*types.ProposeReturn, error)                    ` POST:"/msig/propose"`
+		MsigProposeCall             func(ctx context.Context, req *MsigProposeCallReq) (*MsigProposeCallResp, error)                    ` POST:"/msig/propose/call"`
+		MsigRemoveSigner            func(ctx context.Context, req *MultisigChangeSignerReq) (*types.ProposeReturn, error)               ` POST:"/msig/signer/remove"`
+		MsigSwapSigner              func(ctx context.Context, req *MultisigSwapSignerReq) (*types.ProposeReturn, error)                 ` POST:"/msig/signer/swap"`
+		MsigUnwatch                 func(ctx context.Context, req *MsigWatchReq) error                                                  ` POST:"/msig/watch/remove"`
+		MsigVested                  func(ctx context.Context, req *MsigVestedReq) (abi.TokenAmount, error)                              ` GET:"/msig/vested"`
+		MsigVesting                 func(ctx context.Context, msig address.Address) (*MsigVestingResp, error)                           ` GET:"/msig/vesting"`
+		MsigWatch                   func(ctx context.Context, req *MsigWatchReq) error                                                  ` PUT:"/msig/watch"`
+		MsigWatchList               func(ctx context.Context) ([]address.Address, error)                                                ` GET:"/msig/watch/list"`
+		PolicyApprovalList          func(ctx context.Context, req *PolicyApprovalListReq) ([]*PolicyApproval, error)                    ` GET:"/policy/approval/list"`
+		PolicyApprove               func(ctx context.Context, req *PolicyApproveReq) (string, error)                                    ` POST:"/policy/approval/approve"`
+		PolicyList                  func(ctx context.Context) ([]config.AddressPolicy, error)                                           ` GET:"/policy/list"`
+		PolicyReject                func(ctx context.Context, req *PolicyApproveReq) error                                              ` POST:"/policy/approval/reject"`
+		PolicyRemove                func(ctx context.Context, req *Address) error                                                       ` POST:"/policy/remove"`
+		PolicySet                   func(ctx context.Context, req *config.AddressPolicy) error                                          ` PUT:"/policy/set"`
+		RetrievalDealList           func(ctx context.Context, req *RetrievalDealListReq) (*RetrievalDealListResp, error)                ` GET:"/deal/retrieval"`
+		RetrievalDealStats          func(ctx context.Context, req *RetrievalDealListReq) ([]RetrievalDealStats, error)                  ` GET:"/deal/retrieval/stats"`
+		ScheduleAdd                 func(ctx context.Context, req *ScheduleAddReq) (*ScheduleTask, error)                               ` POST:"/schedule/add"`
+		ScheduleCancel              func(ctx context.Context, req *ScheduleTaskID) error                                                ` POST:"/schedule/cancel/:ID"`
+		ScheduleList                func(ctx context.Context, req *ScheduleListReq) ([]*ScheduleTask, error)                            ` GET:"/schedule/list"`
+		Search                      func(ctx context.Context, req SearchReq) (*SearchResp, error)                                       ` GET:"/search/:Key"`
+		SectorExtend                func(ctx context.Context, req SectorExtendReq) error                                                ` PUT:"/sector/extend"`
+		SectorGet                   func(ctx context.Context, req SectorGetReq) ([]*SectorResp, error)                                  ` GET:"/sector/get"`
+		SectorList                  func(ctx context.Context, req SectorListReq) ([]*SectorListItem, error)                             ` GET:"/sector/list"`
+		SectorSum                   func(ctx context.Context, miner Address) (uint64, error)                                            ` GET:"/sector/sum"`
+		StorageDeal                 func(ctx context.Context, proposalCid Cid) (*marketTypes.MinerDeal, error)                          ` GET:"/deal/storage/info/:Cid"`
+		StorageDealBulkUpdate       func(ctx context.Context, req *StorageDealBulkUpdateReq) (*StorageDealBulkUpdateResp, error)        ` POST:"/deal/storage/bulkupdate"`
+		StorageDealList             func(ctx context.Context, req *StorageDealListReq) (*StorageDealListResp, error)                    ` GET:"/deal/storage/list"`
+		StorageDealUpdateState      func(ctx context.Context, req StorageDealUpdateStateReq) error                                      ` PUT:"/deal/storage/state"`
+		ThreadBulkControl           func(ctx context.Context, req *ThreadBulkControlReq) (*ThreadBulkControlResp, error)                ` POST:"/thread/bulk"`
+		ThreadList                  func(ctx context.Context) (*ThreadListResp, error)                                                  ` GET:"/thread/list"`
+		ThreadStart                 func(ctx context.Context, req *ThreadStartReq) error                                                ` PUT:"/thread/start"`
+		ThreadStop                  func(ctx context.Context, req *ThreadStopReq) error                                                 ` PUT:"/thread/stop"`
+		VerifregAllocationList      func(ctx context.Context, req *VerifregAllocationListReq) ([]VerifregAllocation, error)             ` GET:"/verifreg/allocation/list"`
+		VerifregClaimList           func(ctx context.Context, mAddr Address) ([]VerifregClaim, error)                                   ` GET:"/verifreg/claim/list/:Address"`
+		VerifregExtendClaimTerms    func(ctx context.Context, req *VerifregExtendClaimTermsReq) (*VerifregClaimsResp, error)            ` POST:"/verifreg/claim/extend"`
+		VerifregRemoveExpiredClaims func(ctx context.Context, req *VerifregRemoveExpiredClaimsReq) (*VerifregClaimsResp, error)         ` POST:"/verifreg/claim/removeexpired"`
+		WalletList                  func(ctx context.Context) ([]address.Address, error)                                                ` GET:"/wallet/list"`
+		WalletSignRecordQuery       func(ctx context.Context, req *WalletSignRecordQueryReq) ([]WalletSignRecordResp, error)            ` GET:"/wallet/signrecord"`
 	}
 }
 
@@ -85,6 +127,21 @@ func (s *IServiceStruct) AddrList(p0 context.Context) ([]*AddrsResp, error) {
 func (s *IServiceStruct) AddrOperate(p0 context.Context, p1 *AddrsOperateReq) error {
 	return s.Internal.AddrOperate(p0, p1)
 }
+func (s *IServiceStruct) AskDrift(p0 context.Context) ([]AskDiff, error) {
+	return s.Internal.AskDrift(p0)
+}
+func (s *IServiceStruct) AskTemplateApply(p0 context.Context, p1 *AskTemplateApplyReq) ([]AskDiff, error) {
+	return s.Internal.AskTemplateApply(p0, p1)
+}
+func (s *IServiceStruct) AskTemplateList(p0 context.Context) ([]config.AskTemplate, error) {
+	return s.Internal.AskTemplateList(p0)
+}
+func (s *IServiceStruct) AskTemplateRemove(p0 context.Context, p1 *AskTemplateRemoveReq) error {
+	return s.Internal.AskTemplateRemove(p0, p1)
+}
+func (s *IServiceStruct) AskTemplateSet(p0 context.Context, p1 *config.AskTemplate) error {
+	return s.Internal.AskTemplateSet(p0, p1)
+}
 func (s *IServiceStruct) ChainGetActor(p0 context.Context, p1 address.Address) (*types.Actor, error) {
 	return s.Internal.ChainGetActor(p0, p1)
 }
@@ -94,6 +151,27 @@ func (s *IServiceStruct) ChainGetHead(p0 context.Context) (*types.TipSet, error)
 func (s *IServiceStruct) ChainGetNetworkName(p0 context.Context) (types.NetworkName, error) {
 	return s.Internal.ChainGetNetworkName(p0)
 }
+func (s *IServiceStruct) DealAssignPiece(p0 context.Context, p1 *DealAssignPieceReq) error {
+	return s.Internal.DealAssignPiece(p0, p1)
+}
+func (s *IServiceStruct) DealImport(p0 context.Context, p1 *DealImportReq) ([]DealImportResult, error) {
+	return s.Internal.DealImport(p0, p1)
+}
+func (s *IServiceStruct) DealReconcile(p0 context.Context, p1 *DealReconcileReq) (*DealReconcileResp, error) {
+	return s.Internal.DealReconcile(p0, p1)
+}
+func (s *IServiceStruct) DealStats(p0 context.Context, p1 *DealStatsReq) (*DealStatsResp, error) {
+	return s.Internal.DealStats(p0, p1)
+}
+func (s *IServiceStruct) DealUnassignedPieces(p0 context.Context, p1 *DealUnassignedPiecesReq) ([]marketTypes.MinerDeal, error) {
+	return s.Internal.DealUnassignedPieces(p0, p1)
+}
+func (s *IServiceStruct) MarketAddBalance(p0 context.Context, p1 *MarketAddBalanceReq) (abi.TokenAmount, error) {
+	return s.Internal.MarketAddBalance(p0, p1)
+}
+func (s *IServiceStruct) MarketCollateralPlan(p0 context.Context, p1 Address) (*MarketCollateralPlanResp, error) {
+	return s.Internal.MarketCollateralPlan(p0, p1)
+}
 func (s *IServiceStruct) MinedBlockList(p0 context.Context, p1 MinedBlockListReq) (MinedBlockListResp, error) {
 	return s.Internal.MinedBlockList(p0, p1)
 }
@@ -154,6 +232,9 @@ func (s *IServiceStruct) MinerWithdrawToBeneficiary(p0 context.Context, p1 *Mine
 func (s *IServiceStruct) Msg(p0 context.Context, p1 MsgID) (*MsgResp, error) {
 	return s.Internal.Msg(p0, p1)
 }
+func (s *IServiceStruct) MsgBuild(p0 context.Context, p1 *MsgBuildReq) (*MsgBuildResp, error) {
+	return s.Internal.MsgBuild(p0, p1)
+}
 func (s *IServiceStruct) MsgDecodeParam2Json(p0 context.Context, p1 *MsgDecodeParamReq) ([]byte, error) {
 	return s.Internal.MsgDecodeParam2Json(p0, p1)
 }
@@ -163,6 +244,9 @@ func (s *IServiceStruct) MsgGetMethodName(p0 context.Context, p1 *MsgGetMethodNa
 func (s *IServiceStruct) MsgMarkBad(p0 context.Context, p1 *MsgID) error {
 	return s.Internal.MsgMarkBad(p0, p1)
 }
+func (s *IServiceStruct) MsgParamsSchema(p0 context.Context, p1 *MsgParamsSchemaReq) (*MsgParamsSchemaResp, error) {
+	return s.Internal.MsgParamsSchema(p0, p1)
+}
 func (s *IServiceStruct) MsgQuery(p0 context.Context, p1 *MsgQueryReq) ([]*MsgResp, error) {
 	return s.Internal.MsgQuery(p0, p1)
 }
@@ -172,35 +256,101 @@ func (s *IServiceStruct) MsgReplace(p0 context.Context, p1 *MsgReplaceReq) (cid.
 func (s *IServiceStruct) MsgSend(p0 context.Context, p1 *MsgSendReq) (string, error) {
 	return s.Internal.MsgSend(p0, p1)
 }
+func (s *IServiceStruct) MsgSubmitSigned(p0 context.Context, p1 *MsgSubmitSignedReq) (cid.Cid, error) {
+	return s.Internal.MsgSubmitSigned(p0, p1)
+}
 func (s *IServiceStruct) MsigAddSigner(p0 context.Context, p1 *MultisigChangeSignerReq) (*types.ProposeReturn, error) {
 	return s.Internal.MsigAddSigner(p0, p1)
 }
 func (s *IServiceStruct) MsigApprove(p0 context.Context, p1 *MultisigApproveReq) (*types.ApproveReturn, error) {
 	return s.Internal.MsigApprove(p0, p1)
 }
+func (s *IServiceStruct) MsigAvailable(p0 context.Context, p1 address.Address) (abi.TokenAmount, error) {
+	return s.Internal.MsigAvailable(p0, p1)
+}
 func (s *IServiceStruct) MsigCancel(p0 context.Context, p1 *MultisigCancelReq) error {
 	return s.Internal.MsigCancel(p0, p1)
 }
-func (s *IServiceStruct) MsigCreate(p0 context.Context, p1 *MultisigCreateReq) (address.Address, error) {
+func (s *IServiceStruct) MsigChangeThreshold(p0 context.Context, p1 *MultisigChangeThresholdReq) (*types.ProposeReturn, error) {
+	return s.Internal.MsigChangeThreshold(p0, p1)
+}
+func (s *IServiceStruct) MsigCreate(p0 context.Context, p1 *MultisigCreateReq) (*MultisigCreateResp, error) {
 	return s.Internal.MsigCreate(p0, p1)
 }
+func (s *IServiceStruct) MsigHistory(p0 context.Context, p1 *MsigHistoryReq) (*MsigHistoryResp, error) {
+	return s.Internal.MsigHistory(p0, p1)
+}
+func (s *IServiceStruct) MsigInbox(p0 context.Context, p1 *MsigInboxReq) ([]*MsigInboxItem, error) {
+	return s.Internal.MsigInbox(p0, p1)
+}
 func (s *IServiceStruct) MsigInfo(p0 context.Context, p1 address.Address) (*types.MsigInfo, error) {
 	return s.Internal.MsigInfo(p0, p1)
 }
-func (s *IServiceStruct) MsigListPropose(p0 context.Context, p1 address.Address) ([]*types.MsigTransaction, error) {
+func (s *IServiceStruct) MsigListPropose(p0 context.Context, p1 address.Address) ([]*MsigTransaction, error) {
 	return s.Internal.MsigListPropose(p0, p1)
 }
+func (s *IServiceStruct) MsigLockBalance(p0 context.Context, p1 *MultisigLockBalanceReq) (*types.ProposeReturn, error) {
+	return s.Internal.MsigLockBalance(p0, p1)
+}
 func (s *IServiceStruct) MsigPropose(p0 context.Context, p1 *MultisigProposeReq) (*types.ProposeReturn, error) {
 	return s.Internal.MsigPropose(p0, p1)
 }
+func (s *IServiceStruct) MsigProposeCall(p0 context.Context, p1 *MsigProposeCallReq) (*MsigProposeCallResp, error) {
+	return s.Internal.MsigProposeCall(p0, p1)
+}
 func (s *IServiceStruct) MsigRemoveSigner(p0 context.Context, p1 *MultisigChangeSignerReq) (*types.ProposeReturn, error) {
 	return s.Internal.MsigRemoveSigner(p0, p1)
 }
 func (s *IServiceStruct) MsigSwapSigner(p0 context.Context, p1 *MultisigSwapSignerReq) (*types.ProposeReturn, error) {
 	return s.Internal.MsigSwapSigner(p0, p1)
 }
-func (s *IServiceStruct) RetrievalDealList(p0 context.Context) ([]marketTypes.ProviderDealState, error) {
-	return s.Internal.RetrievalDealList(p0)
+func (s *IServiceStruct) MsigUnwatch(p0 context.Context, p1 *MsigWatchReq) error {
+	return s.Internal.MsigUnwatch(p0, p1)
+}
+func (s *IServiceStruct) MsigVested(p0 context.Context, p1 *MsigVestedReq) (abi.TokenAmount, error) {
+	return s.Internal.MsigVested(p0, p1)
+}
+func (s *IServiceStruct) MsigVesting(p0 context.Context, p1 address.Address) (*MsigVestingResp, error) {
+	return s.Internal.MsigVesting(p0, p1)
+}
+func (s *IServiceStruct) MsigWatch(p0 context.Context, p1 *MsigWatchReq) error {
+	return s.Internal.MsigWatch(p0, p1)
+}
+func (s *IServiceStruct) MsigWatchList(p0 context.Context) ([]address.Address, error) {
+	return s.Internal.MsigWatchList(p0)
+}
+func (s *IServiceStruct) PolicyApprovalList(p0 context.Context, p1 *PolicyApprovalListReq) ([]*PolicyApproval, error) {
+	return s.Internal.PolicyApprovalList(p0, p1)
+}
+func (s *IServiceStruct) PolicyApprove(p0 context.Context, p1 *PolicyApproveReq) (string, error) {
+	return s.Internal.PolicyApprove(p0, p1)
+}
+func (s *IServiceStruct) PolicyList(p0 context.Context) ([]config.AddressPolicy, error) {
+	return s.Internal.PolicyList(p0)
+}
+func (s *IServiceStruct) PolicyReject(p0 context.Context, p1 *PolicyApproveReq) error {
+	return s.Internal.PolicyReject(p0, p1)
+}
+func (s *IServiceStruct) PolicyRemove(p0 context.Context, p1 *Address) error {
+	return s.Internal.PolicyRemove(p0, p1)
+}
+func (s *IServiceStruct) PolicySet(p0 context.Context, p1 *config.AddressPolicy) error {
+	return s.Internal.PolicySet(p0, p1)
+}
+func (s *IServiceStruct) RetrievalDealList(p0 context.Context, p1 *RetrievalDealListReq) (*RetrievalDealListResp, error) {
+	return s.Internal.RetrievalDealList(p0, p1)
+}
+func (s *IServiceStruct) RetrievalDealStats(p0 context.Context, p1 *RetrievalDealListReq) ([]RetrievalDealStats, error) {
+	return s.Internal.RetrievalDealStats(p0, p1)
+}
+func (s *IServiceStruct) ScheduleAdd(p0 context.Context, p1 *ScheduleAddReq) (*ScheduleTask, error) {
+	return s.Internal.ScheduleAdd(p0, p1)
+}
+func (s *IServiceStruct) ScheduleCancel(p0 context.Context, p1 *ScheduleTaskID) error {
+	return s.Internal.ScheduleCancel(p0, p1)
+}
+func (s *IServiceStruct) ScheduleList(p0 context.Context, p1 *ScheduleListReq) ([]*ScheduleTask, error) {
+	return s.Internal.ScheduleList(p0, p1)
 }
 func (s *IServiceStruct) Search(p0 context.Context, p1 SearchReq) (*SearchResp, error) {
 	return s.Internal.Search(p0, p1)
@@ -211,7 +361,7 @@ func (s *IServiceStruct) SectorExtend(p0 context.Context, p1 SectorExtendReq) er
 func (s *IServiceStruct) SectorGet(p0 context.Context, p1 SectorGetReq) ([]*SectorResp, error) {
 	return s.Internal.SectorGet(p0, p1)
 }
-func (s *IServiceStruct) SectorList(p0 context.Context, p1 SectorListReq) ([]*types.SectorOnChainInfo, error) {
+func (s *IServiceStruct) SectorList(p0 context.Context, p1 SectorListReq) ([]*SectorListItem, error) {
 	return s.Internal.SectorList(p0, p1)
 }
 func (s *IServiceStruct) SectorSum(p0 context.Context, p1 Address) (uint64, error) {
@@ -220,13 +370,19 @@ func (s *IServiceStruct) SectorSum(p0 context.Context, p1 Address) (uint64, erro
 func (s *IServiceStruct) StorageDeal(p0 context.Context, p1 Cid) (*marketTypes.MinerDeal, error) {
 	return s.Internal.StorageDeal(p0, p1)
 }
-func (s *IServiceStruct) StorageDealList(p0 context.Context, p1 Address) ([]marketTypes.MinerDeal, error) {
+func (s *IServiceStruct) StorageDealBulkUpdate(p0 context.Context, p1 *StorageDealBulkUpdateReq) (*StorageDealBulkUpdateResp, error) {
+	return s.Internal.StorageDealBulkUpdate(p0, p1)
+}
+func (s *IServiceStruct) StorageDealList(p0 context.Context, p1 *StorageDealListReq) (*StorageDealListResp, error) {
 	return s.Internal.StorageDealList(p0, p1)
 }
 func (s *IServiceStruct) StorageDealUpdateState(p0 context.Context, p1 StorageDealUpdateStateReq) error {
 	return s.Internal.StorageDealUpdateState(p0, p1)
 }
-func (s *IServiceStruct) ThreadList(p0 context.Context) ([]*dep.ThreadInfo, error) {
+func (s *IServiceStruct) ThreadBulkControl(p0 context.Context, p1 *ThreadBulkControlReq) (*ThreadBulkControlResp, error) {
+	return s.Internal.ThreadBulkControl(p0, p1)
+}
+func (s *IServiceStruct) ThreadList(p0 context.Context) (*ThreadListResp, error) {
 	return s.Internal.ThreadList(p0)
 }
 func (s *IServiceStruct) ThreadStart(p0 context.Context, p1 *ThreadStartReq) error {
@@ -235,6 +391,18 @@ func (s *IServiceStruct) ThreadStart(p0 context.Context, p1 *ThreadStartReq) err
 func (s *IServiceStruct) ThreadStop(p0 context.Context, p1 *ThreadStopReq) error {
 	return s.Internal.ThreadStop(p0, p1)
 }
+func (s *IServiceStruct) VerifregAllocationList(p0 context.Context, p1 *VerifregAllocationListReq) ([]VerifregAllocation, error) {
+	return s.Internal.VerifregAllocationList(p0, p1)
+}
+func (s *IServiceStruct) VerifregClaimList(p0 context.Context, p1 Address) ([]VerifregClaim, error) {
+	return s.Internal.VerifregClaimList(p0, p1)
+}
+func (s *IServiceStruct) VerifregExtendClaimTerms(p0 context.Context, p1 *VerifregExtendClaimTermsReq) (*VerifregClaimsResp, error) {
+	return s.Internal.VerifregExtendClaimTerms(p0, p1)
+}
+func (s *IServiceStruct) VerifregRemoveExpiredClaims(p0 context.Context, p1 *VerifregRemoveExpiredClaimsReq) (*VerifregClaimsResp, error) {
+	return s.Internal.VerifregRemoveExpiredClaims(p0, p1)
+}
 func (s *IServiceStruct) WalletList(p0 context.Context) ([]address.Address, error) {
 	return s.Internal.WalletList(p0)
 }
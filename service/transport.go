@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/filecoin-project/go-address"
@@ -13,6 +14,7 @@ import (
 	"github.com/filecoin-project/go-fil-markets/storagemarket"
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/go-state-types/dline"
+	"github.com/filecoin-project/go-state-types/exitcode"
 
 	power "github.com/filecoin-project/go-state-types/builtin/v11/power"
 	"github.com/filecoin-project/venus/venus-shared/actors/builtin/miner"
@@ -22,6 +24,7 @@ import (
 	marketTypes "github.com/filecoin-project/venus/venus-shared/types/market"
 	msgTypes "github.com/filecoin-project/venus/venus-shared/types/messager"
 	minerTypes "github.com/ipfs-force-community/sophon-miner/types"
+	"github.com/ipfs-force-community/venus-tool/dep"
 	"github.com/ipfs-force-community/venus-tool/utils"
 	"github.com/ipfs/go-cid"
 	cbg "github.com/whyrusleeping/cbor-gen"
@@ -56,6 +59,48 @@ type MsgQueryReq struct {
 	BlockedTime time.Duration
 	ID          string
 	Nonce       uint64
+	// Cid is the signed or unsigned cid of message, messages not sent through messager are looked up on chain
+	Cid *cid.Cid
+	// IsSigned lists messages submitted by MsgSubmitSigned, which are not tracked by messager
+	IsSigned bool
+	// OnChain lists messages executed on chain instead of messages in messager,
+	// FromEpoch and one of To and From are required
+	OnChain bool
+
+	// the filters below are not supported by messager, messages are fetched page by page and filtered,
+	// then Offset and Limit are applied to the messages matched
+	To         address.Address
+	MethodName string
+	ExitCode   *exitcode.ExitCode
+	// FromEpoch and ToEpoch limits the height of message executed, zero means no limit
+	FromEpoch abi.ChainEpoch
+	ToEpoch   abi.ChainEpoch
+}
+
+// postFiltered reports whether req has filters not supported by messager
+func (req *MsgQueryReq) postFiltered() bool {
+	return req.To != address.Undef || req.MethodName != "" || req.ExitCode != nil || req.FromEpoch > 0 || req.ToEpoch > 0
+}
+
+// match checks the filters except MethodName, which needs the actor of message to decode
+func (req *MsgQueryReq) match(msg *msgTypes.Message) bool {
+	if req.To != address.Undef && msg.To != req.To {
+		return false
+	}
+	if req.ExitCode != nil && (msg.Receipt == nil || msg.Receipt.ExitCode != *req.ExitCode) {
+		return false
+	}
+	if req.FromEpoch > 0 && (msg.Receipt == nil || abi.ChainEpoch(msg.Height) < req.FromEpoch) {
+		return false
+	}
+	if req.ToEpoch > 0 && (msg.Receipt == nil || abi.ChainEpoch(msg.Height) > req.ToEpoch) {
+		return false
+	}
+	return true
+}
+
+func (req *MsgQueryReq) matchMethod(msg *MsgResp) bool {
+	return req.MethodName == "" || strings.EqualFold(msg.MethodName, req.MethodName)
 }
 
 type MsgSendReq struct {
@@ -63,11 +108,31 @@ type MsgSendReq struct {
 	To     address.Address
 	Value  abi.TokenAmount
 	Method abi.MethodNum
-	Params *EncodedParams
+	// MethodName takes precedence over Method if set, eg: ChangeWorkerAddress
+	MethodName string
+	Params     *EncodedParams
 
 	msgTypes.SendSpec
 }
 
+// MsgBuildReq describes an unsigned message to build for offline signing,
+// the SendSpec is used to estimate gas
+type MsgBuildReq = MsgSendReq
+
+type MsgBuildResp struct {
+	Message types.Message
+	// Cid is the cid of the unsigned message, which is the payload to be signed
+	Cid  cid.Cid
+	Cbor []byte
+	Hex  string
+}
+
+// MsgSubmitSignedReq accepts a signed message either in json or in hex encoded cbor
+type MsgSubmitSignedReq struct {
+	SignedMessage *types.SignedMessage
+	Hex           string
+}
+
 type EncodingType string
 
 type EncodedParams struct {
@@ -110,6 +175,22 @@ type MsgGetMethodNameReq struct {
 	Method abi.MethodNum
 }
 
+type MsgParamsSchemaReq struct {
+	To     address.Address
+	Method abi.MethodNum
+	// MethodName takes precedence over Method if set
+	MethodName string
+}
+
+type MsgParamsSchemaResp struct {
+	Method     abi.MethodNum
+	MethodName string
+	// Schema is nil if the method takes no params
+	Schema *utils.JSONSchema
+	// Template is a json value in the shape of params, which can be filled and used as params-json
+	Template json.RawMessage
+}
+
 type MsgDecodeParamReq struct {
 	To     address.Address
 	Method abi.MethodNum
@@ -154,6 +235,33 @@ type MinerSetRetrievalAskReq struct {
 	Miner address.Address
 }
 
+type AskTemplateRemoveReq struct {
+	Name string
+}
+
+type AskTemplateApplyReq struct {
+	Name string
+	// apply to all miners if Miner is empty
+	Miner []address.Address
+	// DryRun only previews the difference without applying
+	DryRun bool
+}
+
+type AskFieldDiff struct {
+	Field    string
+	Current  string
+	Template string
+}
+
+type AskDiff struct {
+	Miner    address.Address
+	Template string
+	// Fields lists the fields of the current ask differing from the template
+	Fields  []AskFieldDiff
+	Applied bool
+	Error   string
+}
+
 type MinerSetBeneficiaryReq struct {
 	Miner address.Address
 	types.ChangeBeneficiaryParams
@@ -165,12 +273,315 @@ type MinerConfirmBeneficiaryReq struct {
 	ByNominee      bool
 }
 
+type StorageDealListReq struct {
+	// list deals of all miners if Miner is empty
+	Miner    []address.Address
+	State    *storagemarket.StorageDealStatus
+	PieceCID string
+	Client   address.Address
+	Verified *bool
+	// empty PieceStatus means no limit
+	PieceStatus marketTypes.PieceStatus
+	// IncludeInactive includes failing, slashed, expired and error deals
+	IncludeInactive bool
+
+	// the time and epoch ranges are inclusive, zero means no limit
+	CreatedAfter   time.Time
+	CreatedBefore  time.Time
+	StartEpochFrom abi.ChainEpoch
+	StartEpochTo   abi.ChainEpoch
+	EndEpochFrom   abi.ChainEpoch
+	EndEpochTo     abi.ChainEpoch
+
+	// order by creation time, default is Desc
+	Asc bool
+	// Limit is zero means no limit
+	Offset int
+	Limit  int
+}
+
+func (req *StorageDealListReq) match(deal *marketTypes.MinerDeal) bool {
+	proposal := &deal.ClientDealProposal.Proposal
+	if req.Verified != nil && proposal.VerifiedDeal != *req.Verified {
+		return false
+	}
+	if req.PieceStatus != "" && deal.PieceStatus != req.PieceStatus {
+		return false
+	}
+	created := deal.CreationTime.Time()
+	if !req.CreatedAfter.IsZero() && created.Before(req.CreatedAfter) {
+		return false
+	}
+	if !req.CreatedBefore.IsZero() && created.After(req.CreatedBefore) {
+		return false
+	}
+	inRange := func(epoch, from, to abi.ChainEpoch) bool {
+		return (from == 0 || epoch >= from) && (to == 0 || epoch <= to)
+	}
+	return inRange(proposal.StartEpoch, req.StartEpochFrom, req.StartEpochTo) &&
+		inRange(proposal.EndEpoch, req.EndEpochFrom, req.EndEpochTo)
+}
+
+type StorageDealListResp struct {
+	// Total is the count of deals matched, regardless of Offset and Limit
+	Total int
+	Deals []marketTypes.MinerDeal
+}
+
+type DealStatsReq struct {
+	// stat deals of all miners if Miner is empty
+	Miner []address.Address
+	// Days is the number of days of the trend, default is 7
+	Days int
+}
+
+// DealAgeStats counts deals by how long they stay in the current state
+type DealAgeStats struct {
+	LessThan1Day     int
+	OneToThreeDays   int
+	ThreeToSevenDays int
+	MoreThan7Days    int
+}
+
+func (s *DealAgeStats) add(age time.Duration) {
+	switch {
+	case age < 24*time.Hour:
+		s.LessThan1Day++
+	case age < 3*24*time.Hour:
+		s.OneToThreeDays++
+	case age < 7*24*time.Hour:
+		s.ThreeToSevenDays++
+	default:
+		s.MoreThan7Days++
+	}
+}
+
+type DealBrief struct {
+	ProposalCid  cid.Cid
+	DealID       abi.DealID
+	Miner        address.Address
+	CreationTime time.Time
+	UpdatedAt    time.Time
+}
+
+type DealStateStats struct {
+	State     storagemarket.StorageDealStatus
+	StateName string
+	Count     int
+	PieceSize abi.PaddedPieceSize
+	Age       DealAgeStats
+	// Oldest is the deal staying in the state for the longest time
+	Oldest DealBrief
+}
+
+type DealPieceStatusStats struct {
+	PieceStatus marketTypes.PieceStatus
+	Count       int
+	PieceSize   abi.PaddedPieceSize
+	Age         DealAgeStats
+	Oldest      DealBrief
+}
+
+// DealDailyStats is the count of deals accepted, sealed and failed in a day,
+// sealed and failed are counted by the last update time of the deal
+type DealDailyStats struct {
+	Date     string
+	Accepted int
+	Sealed   int
+	Failed   int
+}
+
+type DealStatsResp struct {
+	Total         int
+	PieceSize     abi.PaddedPieceSize
+	States        []DealStateStats
+	PieceStatuses []DealPieceStatusStats
+	// Trend is ordered by date asc
+	Trend []DealDailyStats
+}
+
 type StorageDealUpdateStateReq struct {
 	ProposalCid cid.Cid
 	State       storagemarket.StorageDealStatus
 	PieceStatus marketTypes.PieceStatus
 }
 
+type StorageDealBulkUpdateReq struct {
+	// Filter selects the deals to update, Offset and Limit are ignored
+	Filter StorageDealListReq
+	// UpdatedBefore selects deals not updated since then, zero means no limit
+	UpdatedBefore time.Time
+
+	// StorageDealUnknown and empty PieceStatus mean no change
+	State       storagemarket.StorageDealStatus
+	PieceStatus marketTypes.PieceStatus
+
+	// DryRun only previews the transitions without applying them,
+	// otherwise the request must carry a token identifying the operator
+	DryRun bool
+}
+
+type StorageDealUpdateResult struct {
+	ProposalCid     cid.Cid
+	Miner           address.Address
+	FromState       storagemarket.StorageDealStatus
+	ToState         storagemarket.StorageDealStatus
+	FromPieceStatus marketTypes.PieceStatus
+	ToPieceStatus   marketTypes.PieceStatus
+	// Error is the reason of an illegal transition or the failure of update
+	Error   string
+	Applied bool
+}
+
+type StorageDealBulkUpdateResp struct {
+	Matched int
+	Applied int
+	Failed  int
+	DryRun  bool
+	Results []StorageDealUpdateResult
+}
+
+type DealMismatchKind string
+
+const (
+	// DealMissingLocally means the deal is published on chain but not found in droplet
+	DealMissingLocally DealMismatchKind = "MissingLocally"
+	// DealActiveOnChain means the deal is active on chain but not active in droplet
+	DealActiveOnChain DealMismatchKind = "ActiveOnChain"
+	// DealSlashed means the deal is slashed on chain but not in droplet
+	DealSlashed DealMismatchKind = "Slashed"
+	// DealNotActivated means the deal was not activated before its start epoch
+	DealNotActivated DealMismatchKind = "NotActivated"
+	// DealSectorMismatch means the sector of the deal on chain differs from droplet
+	DealSectorMismatch DealMismatchKind = "SectorMismatch"
+)
+
+type DealReconcileReq struct {
+	// Miner is the miners to check, all miners if empty
+	Miner []address.Address
+	// Apply updates the state of deals in droplet to match the chain, only mismatches are reported if not set,
+	// the request must carry a token identifying the operator to apply
+	Apply bool
+}
+
+type DealMismatch struct {
+	Miner  address.Address
+	DealID abi.DealID
+	Kind   DealMismatchKind
+	// ProposalCid is nil if the deal is missing locally
+	ProposalCid *cid.Cid
+	LocalState  storagemarket.StorageDealStatus
+	LocalSector abi.SectorNumber
+	// ChainSector is 0 if the deal is not in any sector on chain
+	ChainSector      abi.SectorNumber
+	StartEpoch       abi.ChainEpoch
+	SectorStartEpoch abi.ChainEpoch
+	SlashEpoch       abi.ChainEpoch
+	// Action is the state suggested to update the deal to, StorageDealUnknown means nothing to do
+	Action  storagemarket.StorageDealStatus
+	Applied bool
+	Error   string
+}
+
+type DealReconcileResp struct {
+	Height     abi.ChainEpoch
+	Checked    int
+	Applied    int
+	Failed     int
+	DryRun     bool
+	Mismatches []DealMismatch
+}
+
+type DealImportRef struct {
+	ProposalCid cid.Cid
+	// File is the path of the car file, it should be accessible by both venus-tool and droplet
+	File string
+}
+
+type DealImportReq struct {
+	Refs []DealImportRef
+	// SkipCommP skips checking the commP of the car file against the piece cid of the proposal,
+	// the check is not repeated by market either, so only use it for data verified elsewhere
+	SkipCommP bool
+}
+
+type DealImportResult struct {
+	ProposalCid cid.Cid
+	File        string
+	Error       string
+}
+
+type DealUnassignedPiecesReq struct {
+	// list pieces of all miners if Miner is empty
+	Miner []address.Address
+}
+
+type DealAssignPieceReq struct {
+	Miner  address.Address
+	DealID abi.DealID
+	Sector abi.SectorNumber
+	Offset abi.PaddedPieceSize
+}
+
+type RetrievalDealListReq struct {
+	// list deals of all miners if Miner is empty
+	Miner []address.Address
+	// Receiver is the peer id of the client
+	Receiver   string
+	PayloadCID string
+	Status     *retrievalmarket.DealStatus
+	// the time range is inclusive, zero means no limit
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+
+	// Limit is zero means no limit
+	Offset int
+	Limit  int
+}
+
+func (req *RetrievalDealListReq) match(deal *RetrievalDeal) bool {
+	if len(req.Miner) > 0 {
+		found := false
+		for _, m := range req.Miner {
+			found = found || m == deal.Miner
+		}
+		if !found {
+			return false
+		}
+	}
+	created := time.Unix(int64(deal.CreatedAt), 0)
+	if !req.CreatedAfter.IsZero() && created.Before(req.CreatedAfter) {
+		return false
+	}
+	if !req.CreatedBefore.IsZero() && created.After(req.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+type RetrievalDeal struct {
+	marketTypes.ProviderDealState
+	// Miner is the provider of the storage deal the piece retrieved from, undefined if unknown
+	Miner address.Address
+}
+
+type RetrievalDealListResp struct {
+	// Total is the count of deals matched, regardless of Offset and Limit
+	Total int
+	Deals []RetrievalDeal
+}
+
+type RetrievalDealStats struct {
+	Miner         address.Address
+	Deals         int
+	Completed     int
+	Failed        int
+	BytesSent     uint64
+	FundsReceived abi.TokenAmount
+	// FailureReasons counts failed deals by the error message
+	FailureReasons map[string]int
+}
+
 type MinerCreateReq struct {
 	power.CreateMinerParams
 	From       address.Address
@@ -207,6 +618,30 @@ type MinerWithdrawBalanceReq struct {
 	Amount abi.TokenAmount
 }
 
+type MarketAddBalanceReq struct {
+	Miner address.Address
+	// From is the wallet to pay, default is the worker of miner
+	From   address.Address
+	Amount abi.TokenAmount
+}
+
+type MarketCollateralPlanResp struct {
+	Miner    address.Address
+	Escrow   abi.TokenAmount
+	Locked   abi.TokenAmount
+	Reserved abi.TokenAmount
+	// Available is escrow minus locked minus reserved
+	Available abi.TokenAmount
+
+	// PendingDeals is the count of deals whose collateral is not reserved yet
+	PendingDeals int
+	// PendingCollateral is the provider collateral needed by the pending deals, collateral of later deals is in Reserved or Locked
+	PendingCollateral abi.TokenAmount
+	// TopUp is the recommended amount to add, zero if available balance is enough
+	TopUp   abi.TokenAmount
+	Warning string
+}
+
 type MinerWinCountReq struct {
 	Miners []address.Address
 	From   abi.ChainEpoch
@@ -229,6 +664,19 @@ type SectorGetReq struct {
 type SectorResp struct {
 	types.SectorOnChainInfo
 	SectorLocation miner.SectorLocation
+	SectorClaimInfo
+}
+
+type SectorListItem struct {
+	types.SectorOnChainInfo
+	SectorClaimInfo
+}
+
+// SectorClaimInfo shows when the verified claims of a sector expire
+type SectorClaimInfo struct {
+	// ClaimExpiration is the earliest epoch the claims of the sector can be removed, zero if no claim
+	ClaimExpiration   abi.ChainEpoch
+	ClaimExpiringSoon bool
 }
 
 type SectorListReq struct {
@@ -237,12 +685,79 @@ type SectorListReq struct {
 	PageSize  int
 }
 
+type VerifregClaim struct {
+	ID types.ClaimId
+	types.Claim
+	// Expiration is the epoch after which the claim can be removed, TermStart + TermMax
+	Expiration   abi.ChainEpoch
+	ExpiringSoon bool
+}
+
+type VerifregAllocationListReq struct {
+	// list allocations to all miners if Miner is empty
+	Miner []address.Address
+	// Client is the clients to list allocations of, default is the clients of verified deals of miners
+	Client []address.Address
+}
+
+type VerifregAllocation struct {
+	ID types.AllocationId
+	types.Allocation
+	ClientAddr   address.Address
+	ProviderAddr address.Address
+	// Expired means the allocation can not be claimed any more
+	Expired bool
+}
+
+type VerifregExtendClaimTermsReq struct {
+	// From should be the client of the claims
+	From     address.Address
+	Miner    address.Address
+	ClaimIDs []types.ClaimId
+	TermMax  abi.ChainEpoch
+}
+
+type VerifregRemoveExpiredClaimsReq struct {
+	From  address.Address
+	Miner address.Address
+	// ClaimIDs is the claims to remove, all expired claims of the miner if empty
+	ClaimIDs []types.ClaimId
+}
+
+// VerifregClaimsResp is the result of a message processing claims in batch,
+// the message succeeds even if some or all of the claims fail
+type VerifregClaimsResp struct {
+	MsgID string
+	// Succeeded is the count of claims processed
+	Succeeded uint64
+	Failed    []VerifregClaimFailure
+}
+
+type VerifregClaimFailure struct {
+	ID   types.ClaimId
+	Code exitcode.ExitCode
+}
+
 type MultisigCreateReq struct {
 	From               address.Address
 	Signers            []address.Address
 	ApprovalsThreshold uint64
-	LockedDuration     abi.ChainEpoch
-	Value              abi.TokenAmount
+	// StartEpoch is the epoch the locked value starts to unlock
+	StartEpoch     abi.ChainEpoch
+	LockedDuration abi.ChainEpoch
+	Value          abi.TokenAmount
+	// DryRun previews the create message without sending it
+	DryRun bool
+}
+
+type MultisigCreateResp struct {
+	DryRun  bool
+	Message types.Message
+	// Params is decoded from the create message, which the multisig actor is constructed with
+	Params types.MultisigConstructorParams
+	// IDAddress and RobustAddress are the addresses of the new multisig, empty in dry run
+	IDAddress     address.Address
+	RobustAddress address.Address
 }
 
 type MultisigProposeReq struct {
@@ -254,6 +769,143 @@ type MultisigProposeReq struct {
 	Params EncodedParams
 }
 
+type MsigTransaction struct {
+	types.MsigTransaction
+	// ActorType is the type of the actor the transaction is sent to, empty if the actor is not found
+	ActorType    string
+	MethodName   string
+	ParamsInJson json.RawMessage
+	Proposer     address.Address
+	Threshold    uint64
+	// Hash is the proposal hash which can be checked when approving or canceling the transaction
+	Hash []byte
+	// DecodeError is the reason why the method or params cannot be decoded
+	DecodeError string
+}
+
+type MsigVestingPeriod struct {
+	Start     abi.ChainEpoch
+	End       abi.ChainEpoch
+	StartTime time.Time
+	EndTime   time.Time
+	Unlocked  abi.TokenAmount
+	// Locked is the locked balance at the end of the period
+	Locked abi.TokenAmount
+}
+
+type MsigVestingResp struct {
+	types.MsigVesting
+	Height    abi.ChainEpoch
+	Balance   abi.TokenAmount
+	Available abi.TokenAmount
+	Locked    abi.TokenAmount
+	// Timeline is the amount unlocked every 30 days from the current height until fully vested
+	Timeline []MsigVestingPeriod
+}
+
+type MsigVestedReq struct {
+	Msig address.Address
+	From abi.ChainEpoch
+	// To is the current height if not set
+	To abi.ChainEpoch
+}
+
+// MsigProposeCallReq proposes a miner operation through the multisig, exactly one of the calls should be set,
+// and the multisig must be the address required by the miner actor to send the call, eg: owner to set worker
+type MsigProposeCallReq struct {
+	Msig address.Address
+	// From is the signer to propose
+	From address.Address
+
+	SetOwner           *MinerSetOwnerReq
+	ConfirmOwner       *MinerSetOwnerReq
+	SetWorker          *MinerSetWorkerReq
+	ConfirmWorker      *MinerSetWorkerReq
+	SetControllers     *MinerSetControllersReq
+	SetBeneficiary     *MinerSetBeneficiaryReq
+	ConfirmBeneficiary *MinerConfirmBeneficiaryReq
+	WithdrawBalance    *MinerWithdrawBalanceReq
+}
+
+type MsigProposeCallResp struct {
+	types.ProposeReturn
+	// the inner call proposed, which can be checked when approving
+	To     address.Address
+	Value  abi.TokenAmount
+	Method abi.MethodNum
+	Params []byte
+}
+
+type MsigWatchReq struct {
+	Msig address.Address
+}
+
+type MsigInboxReq struct {
+	// Signer lists the signers to check, all addresses of the wallet if empty
+	Signer []address.Address
+}
+
+type MsigInboxItem struct {
+	Msig address.Address
+	MsigTransaction
+	// Signers are the signers in request which have not approved the transaction
+	Signers []address.Address
+	// FirstSeen is the time the transaction is first seen in the inbox, the chain does not record when it is proposed
+	FirstSeen time.Time
+	Age       time.Duration
+}
+
+type MsigHistoryReq struct {
+	Msig address.Address
+	// From is the height to scan from, 30 days before the current height if not set
+	From abi.ChainEpoch
+}
+
+type MsigTxnState string
+
+const (
+	MsigTxnPending   MsigTxnState = "Pending"
+	MsigTxnExecuted  MsigTxnState = "Executed"
+	MsigTxnCancelled MsigTxnState = "Cancelled"
+	// MsigTxnUnknown is the state of transactions proposed before the scanned range and no longer pending
+	MsigTxnUnknown MsigTxnState = "Unknown"
+)
+
+// MsigHistoryEvent is a message sent to the multisig
+type MsigHistoryEvent struct {
+	Height   abi.ChainEpoch
+	MsgCid   cid.Cid
+	From     address.Address
+	Value    abi.TokenAmount
+	Method   string
+	ExitCode exitcode.ExitCode
+	// TxnID is the transaction proposed, approved or cancelled by the message, -1 for other methods
+	TxnID int64
+}
+
+type MsigHistoryTxn struct {
+	MsigTransaction
+	// ToID is the id address of To, undefined if the transaction is proposed before the scanned range
+	ToID  address.Address
+	State MsigTxnState
+	// ExitCode and Return are the result of the inner call once the transaction is executed
+	ExitCode   exitcode.ExitCode
+	Return     []byte
+	ProposedAt abi.ChainEpoch
+	// ClosedAt is the height the transaction is executed or cancelled
+	ClosedAt abi.ChainEpoch
+	// Messages are the cids of messages proposing, approving or cancelling the transaction
+	Messages []cid.Cid
+}
+
+type MsigHistoryResp struct {
+	Msig         address.Address
+	From         abi.ChainEpoch
+	To           abi.ChainEpoch
+	Transactions []*MsigHistoryTxn
+	Events       []*MsigHistoryEvent
+}
+
 type MultisigChangeSignerReq struct {
 	NewSigner      address.Address
 	Proposer       address.Address
@@ -265,6 +917,17 @@ type MultisigTransactionReq struct {
 	Msig     address.Address
 	Proposer address.Address
 	TxID     uint64
+	// Expect is the proposal the sender expects, the request fails if the pending transaction differs
+	Expect *MultisigProposal
+	// Hash is the expected proposal hash, listed by MsigListPropose
+	Hash []byte
+}
+
+type MultisigProposal struct {
+	To     address.Address
+	Value  abi.TokenAmount
+	Method abi.MethodNum
+	Params []byte
 }
 
 type MultisigApproveReq = MultisigTransactionReq
@@ -277,6 +940,20 @@ type MultisigSwapSignerReq struct {
 	NewSigner address.Address
 }
 
+type MultisigChangeThresholdReq struct {
+	Msig         address.Address
+	Proposer     address.Address
+	NewThreshold uint64
+}
+
+type MultisigLockBalanceReq struct {
+	Msig           address.Address
+	Proposer       address.Address
+	StartEpoch     abi.ChainEpoch
+	UnlockDuration abi.ChainEpoch
+	Amount         abi.TokenAmount
+}
+
 type WalletSignRecordQueryReq types.QuerySignRecordParams
 
 type WalletSignRecordResp struct {
@@ -284,6 +961,21 @@ type WalletSignRecordResp struct {
 	Detail json.RawMessage
 }
 
+type WorkerThreads struct {
+	Info     dep.WorkerInfo
+	LastPing int64
+	Threads  int
+	// Error is the reason the threads of the worker cannot be listed
+	Error string
+}
+
+type ThreadListResp struct {
+	Threads []*dep.ThreadInfo
+	Workers []*WorkerThreads
+	// UpdatedAt is the time the workers are queried, the result is cached for a few seconds
+	UpdatedAt time.Time
+}
+
 type ThreadStopReq struct {
 	WorkerName string
 	Index      uint64
@@ -295,6 +987,58 @@ type ThreadStartReq struct {
 	State      *string
 }
 
+type ThreadAction string
+
+const (
+	ThreadPause  ThreadAction = "pause"
+	ThreadResume ThreadAction = "resume"
+)
+
+// ThreadSelector selects sealing threads, conditions are combined with AND and values of a condition with OR,
+// empty conditions match all threads
+type ThreadSelector struct {
+	Worker []string
+	Plan   []string
+	// Stage is the stage of the job in the thread
+	Stage []string
+	// State is the state of the job in the thread
+	State []string
+}
+
+type ThreadBulkControlReq struct {
+	ThreadSelector
+	Action ThreadAction
+	// SetState is the state to resume the threads to, eg: Aborted, nil keeps the current state
+	SetState *string
+	// All must be set to control all threads in the cluster with an empty selector
+	All bool
+
+	// DryRun only previews the affected threads, otherwise the request must carry a token identifying the operator
+	DryRun bool
+}
+
+type ThreadControlResult struct {
+	WorkerName  string
+	Index       uint64
+	Plan        string
+	JobID       string
+	JobState    string
+	JobStage    string
+	ThreadState string
+	Error       string
+	Applied     bool
+}
+
+type ThreadBulkControlResp struct {
+	Matched int
+	Applied int
+	Failed  int
+	DryRun  bool
+	Results []ThreadControlResult
+	// FailedWorkers are the workers whose threads cannot be listed, threads on them are not controlled
+	FailedWorkers []*WorkerThreads
+}
+
 // The param which bind with Query or Uri in gin must be a struct
 type Address struct {
 	Address address.Address
@@ -312,6 +1056,8 @@ const (
 	Miner   DataType = "miner"
 	Message DataType = "message"
 	Deal    DataType = "deal"
+	// Piece is a piece cid or payload cid of deals
+	Piece DataType = "piece"
 )
 
 type SearchReq struct {
@@ -323,6 +1069,30 @@ type SearchResp struct {
 	Data json.RawMessage
 }
 
+type PieceSearchResp struct {
+	// PayloadCID is set if the key searched is a payload cid
+	PayloadCID *cid.Cid
+	Pieces     []PieceSearchItem
+}
+
+type PieceSearchItem struct {
+	PieceCID cid.Cid
+	Deals    []marketTypes.MinerDeal
+	Sectors  []PieceSector
+	// Shard is the dagstore shard of the piece, nil if not found
+	Shard *marketTypes.DagstoreShardInfo
+	// Retrievable means the shard of the piece is available for retrieval
+	Retrievable bool
+}
+
+type PieceSector struct {
+	Miner  address.Address
+	DealID abi.DealID
+	Sector abi.SectorNumber
+	// SectorInfo is nil if the sector is not on chain
+	SectorInfo *types.SectorOnChainInfo
+}
+
 type MinedBlockListReq struct {
 	Miner  []address.Address
 	Limit  int
@@ -330,3 +1100,135 @@ type MinedBlockListReq struct {
 }
 
 type MinedBlockListResp []minerTypes.MinedBlock
+
+type ScheduleTriggerType string
+
+const (
+	// ScheduleAtEpoch fires once the chain head reaches the epoch
+	ScheduleAtEpoch ScheduleTriggerType = "epoch"
+	// ScheduleAtTime fires at the first head change after the time
+	ScheduleAtTime ScheduleTriggerType = "time"
+	// ScheduleBalanceBelow fires when the balance of the address drops below the threshold
+	ScheduleBalanceBelow ScheduleTriggerType = "balance-below"
+	// ScheduleBalanceAbove fires when the balance of the address rises above the threshold
+	ScheduleBalanceAbove ScheduleTriggerType = "balance-above"
+)
+
+type ScheduleTrigger struct {
+	Type ScheduleTriggerType
+
+	Epoch abi.ChainEpoch
+	Time  time.Time
+
+	// Address and Balance are used by the balance predicates
+	Address address.Address
+	Balance abi.TokenAmount
+}
+
+func (t *ScheduleTrigger) Validate() error {
+	switch t.Type {
+	case ScheduleAtEpoch:
+		if t.Epoch <= 0 {
+			return fmt.Errorf("epoch must be positive")
+		}
+	case ScheduleAtTime:
+		if t.Time.IsZero() {
+			return fmt.Errorf("time is required")
+		}
+	case ScheduleBalanceBelow, ScheduleBalanceAbove:
+		if t.Address == address.Undef {
+			return fmt.Errorf("address is required")
+		}
+		if t.Balance.Nil() {
+			return fmt.Errorf("balance is required")
+		}
+	default:
+		return fmt.Errorf("unknown trigger type: %s", t.Type)
+	}
+	return nil
+}
+
+func (t ScheduleTrigger) String() string {
+	switch t.Type {
+	case ScheduleAtEpoch:
+		return fmt.Sprintf("at epoch %d", t.Epoch)
+	case ScheduleAtTime:
+		return fmt.Sprintf("at %s", t.Time.Format(time.RFC3339))
+	case ScheduleBalanceBelow:
+		return fmt.Sprintf("balance of %s < %s", t.Address, types.FIL(t.Balance))
+	case ScheduleBalanceAbove:
+		return fmt.Sprintf("balance of %s > %s", t.Address, types.FIL(t.Balance))
+	}
+	return string(t.Type)
+}
+
+type ScheduleTaskState string
+
+// tasks move from pending to firing before the message is sent, then to sent or failed,
+// only pending tasks can be canceled
+const (
+	ScheduleTaskPending  ScheduleTaskState = "pending"
+	ScheduleTaskFiring   ScheduleTaskState = "firing"
+	ScheduleTaskSent     ScheduleTaskState = "sent"
+	ScheduleTaskFailed   ScheduleTaskState = "failed"
+	ScheduleTaskCanceled ScheduleTaskState = "canceled"
+)
+
+type ScheduleTask struct {
+	ID      uint64
+	Msg     MsgSendReq
+	Trigger ScheduleTrigger
+	State   ScheduleTaskState
+	// MsgID is the id returned by messager once the task fired
+	MsgID     string
+	Error     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ScheduleAddReq struct {
+	Msg     MsgSendReq
+	Trigger ScheduleTrigger
+}
+
+type ScheduleListReq struct {
+	// list all tasks if State is empty
+	State ScheduleTaskState
+}
+
+type ScheduleTaskID struct {
+	ID uint64
+}
+
+type PolicyApprovalState string
+
+const (
+	PolicyApprovalPending  PolicyApprovalState = "pending"
+	PolicyApprovalApproved PolicyApprovalState = "approved"
+	PolicyApprovalRejected PolicyApprovalState = "rejected"
+)
+
+// PolicyApproval is a message exceeding the spending limits, which waits for another user to approve,
+// the requester and the approver are the users of their tokens verified by sophon-auth
+type PolicyApproval struct {
+	ID        uint64
+	Msg       MsgSendReq
+	Reason    string
+	State     PolicyApprovalState
+	Requester string
+	Approver  string
+	// MsgID is the id returned by messager once approved
+	MsgID     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type PolicyApprovalListReq struct {
+	// list all approvals if State is empty
+	State PolicyApprovalState
+}
+
+// PolicyApproveReq handles the approval request as the user of the request token, who must not be the requester
+type PolicyApproveReq struct {
+	ID uint64
+}
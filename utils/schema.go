@@ -0,0 +1,283 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/filecoin-project/go-address"
+	"github.com/filecoin-project/go-bitfield"
+	"github.com/filecoin-project/go-state-types/big"
+	"github.com/ipfs/go-cid"
+)
+
+// JSONSchema is a subset of json schema, which is enough to describe the params of actor methods
+type JSONSchema struct {
+	Type        string                 `json:"type,omitempty"`
+	Format      string                 `json:"format,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Items       *JSONSchema            `json:"items,omitempty"`
+	// Order keeps the declared order of properties, which is lost in the map
+	Order []string `json:"x-order,omitempty"`
+}
+
+const (
+	FormatAddress = "address"
+	FormatBigInt  = "bigint"
+	FormatCid     = "cid"
+	FormatBytes   = "base64"
+)
+
+var (
+	addressType  = reflect.TypeOf(address.Address{})
+	bigIntType   = reflect.TypeOf(big.Int{})
+	cidType      = reflect.TypeOf(cid.Cid{})
+	bitFieldType = reflect.TypeOf(bitfield.BitField{})
+	marshalType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// maxSchemaDepth guards against recursive types
+const maxSchemaDepth = 16
+
+// GenJSONSchema derives the json schema of t, according to how t will be encoded by encoding/json
+func GenJSONSchema(t reflect.Type) *JSONSchema {
+	return genJSONSchema(t, 0)
+}
+
+func genJSONSchema(t reflect.Type, depth int) *JSONSchema {
+	if depth > maxSchemaDepth {
+		return &JSONSchema{Description: "nested too deep"}
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t {
+	case addressType:
+		return &JSONSchema{Type: "string", Format: FormatAddress}
+	case bigIntType:
+		return &JSONSchema{Type: "string", Format: FormatBigInt}
+	case cidType:
+		return &JSONSchema{
+			Type:       "object",
+			Format:     FormatCid,
+			Properties: map[string]*JSONSchema{"/": {Type: "string"}},
+			Required:   []string{"/"},
+			Order:      []string{"/"},
+		}
+	case bitFieldType:
+		return &JSONSchema{
+			Type:        "array",
+			Items:       &JSONSchema{Type: "integer"},
+			Description: "run length encoded bitfield, eg: [0, 2, 1, 3] means 2, 6, 7, 8",
+		}
+	}
+	if t.Implements(marshalType) || reflect.PtrTo(t).Implements(marshalType) {
+		return &JSONSchema{Description: fmt.Sprintf("%s with custom json encoding", t)}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &JSONSchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &JSONSchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &JSONSchema{Type: "number"}
+	case reflect.String:
+		return &JSONSchema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &JSONSchema{Type: "string", Format: FormatBytes}
+		}
+		return &JSONSchema{Type: "array", Items: genJSONSchema(t.Elem(), depth+1)}
+	case reflect.Map:
+		return &JSONSchema{Type: "object", Description: fmt.Sprintf("map of %s", t.Elem())}
+	case reflect.Struct:
+		s := &JSONSchema{Type: "object", Properties: map[string]*JSONSchema{}}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			name := field.Name
+			omitEmpty := false
+			tag, tagged := field.Tag.Lookup("json")
+			if field.Anonymous && !tagged {
+				// fields of embedded struct are promoted by encoding/json
+				embedded := genJSONSchema(field.Type, depth+1)
+				if embedded.Type == "object" && embedded.Properties != nil {
+					for _, n := range embedded.Order {
+						s.Properties[n] = embedded.Properties[n]
+					}
+					s.Order = append(s.Order, embedded.Order...)
+					s.Required = append(s.Required, embedded.Required...)
+					continue
+				}
+			}
+			if tagged {
+				if tag == "-" {
+					continue
+				}
+				parts := strings.Split(tag, ",")
+				if parts[0] != "" {
+					name = parts[0]
+				}
+				for _, opt := range parts[1:] {
+					if opt == "omitempty" {
+						omitEmpty = true
+					}
+				}
+			}
+			s.Properties[name] = genJSONSchema(field.Type, depth+1)
+			s.Order = append(s.Order, name)
+			switch field.Type.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Map:
+				// nil is acceptable
+			default:
+				if !omitEmpty {
+					s.Required = append(s.Required, name)
+				}
+			}
+		}
+		return s
+	}
+	return &JSONSchema{Description: t.String()}
+}
+
+// Template returns a json value which has the shape of the schema, can be used as a start point to fill the params
+func (s *JSONSchema) Template() interface{} {
+	switch s.Type {
+	case "boolean":
+		return false
+	case "integer", "number":
+		return 0
+	case "string":
+		switch s.Format {
+		case FormatAddress:
+			return "<address>"
+		case FormatBigInt:
+			return "0"
+		}
+		return ""
+	case "array":
+		return []interface{}{}
+	case "object":
+		obj := make(map[string]interface{}, len(s.Properties))
+		for name, prop := range s.Properties {
+			obj[name] = prop.Template()
+		}
+		return obj
+	}
+	return nil
+}
+
+// Validate checks the json data against the schema
+func (s *JSONSchema) Validate(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return fmt.Errorf("invalid json: %s", err)
+	}
+	return s.validate(v, "$")
+}
+
+func (s *JSONSchema) validate(v interface{}, path string) error {
+	if s.Type == "" || v == nil {
+		// unknown type or null value, leave it to the decoder
+		return nil
+	}
+	switch s.Type {
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: expect boolean, got %v", path, v)
+		}
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s: expect integer, got %v", path, v)
+		}
+		if _, err := strconv.ParseInt(n.String(), 10, 64); err != nil {
+			if _, err := strconv.ParseUint(n.String(), 10, 64); err != nil {
+				return fmt.Errorf("%s: expect integer, got %s", path, n)
+			}
+		}
+	case "number":
+		if _, ok := v.(json.Number); !ok {
+			return fmt.Errorf("%s: expect number, got %v", path, v)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: expect string, got %v", path, v)
+		}
+		switch s.Format {
+		case FormatAddress:
+			if _, err := address.NewFromString(str); err != nil {
+				return fmt.Errorf("%s: invalid address %q: %s", path, str, err)
+			}
+		case FormatBigInt:
+			if _, err := big.FromString(str); err != nil {
+				return fmt.Errorf("%s: invalid big int %q: %s", path, str, err)
+			}
+		}
+	case "array":
+		arr, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expect array, got %v", path, v)
+		}
+		if s.Items != nil {
+			for i := range arr {
+				if err := s.Items.validate(arr[i], fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: expect object, got %v", path, v)
+		}
+		if s.Properties == nil {
+			return nil
+		}
+		// encoding/json matches field names case-insensitively
+		fields := make(map[string]interface{}, len(obj))
+		for k, val := range obj {
+			fields[strings.ToLower(k)] = val
+		}
+		for _, name := range s.Required {
+			if _, ok := fields[strings.ToLower(name)]; !ok {
+				return fmt.Errorf("%s: missing field %s", path, name)
+			}
+		}
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			var prop *JSONSchema
+			for name := range s.Properties {
+				if strings.EqualFold(name, k) {
+					prop = s.Properties[name]
+					break
+				}
+			}
+			if prop == nil {
+				return fmt.Errorf("%s: unknown field %s", path, k)
+			}
+			if err := prop.validate(obj[k], path+"."+k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/filecoin-project/go-state-types/builtin/v11/miner"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONSchema(t *testing.T) {
+	schema := GenJSONSchema(reflect.TypeOf(&miner.ChangeWorkerAddressParams{}))
+	assert.Equal(t, "object", schema.Type)
+	assert.Equal(t, []string{"NewWorker", "NewControlAddrs"}, schema.Order)
+	assert.Equal(t, []string{"NewWorker"}, schema.Required)
+	assert.Equal(t, FormatAddress, schema.Properties["NewWorker"].Format)
+	assert.Equal(t, "array", schema.Properties["NewControlAddrs"].Type)
+
+	testCases := []struct {
+		name    string
+		data    string
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			data: `{"NewWorker": "f01234", "NewControlAddrs": ["f01235"]}`,
+		},
+		{
+			name: "case insensitive",
+			data: `{"newworker": "f01234"}`,
+		},
+		{
+			name:    "missing field",
+			data:    `{"NewControlAddrs": []}`,
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			data:    `{"NewWorker": "f01234", "Worker": "f01234"}`,
+			wantErr: true,
+		},
+		{
+			name:    "bad address",
+			data:    `{"NewWorker": "f01234", "NewControlAddrs": ["x"]}`,
+			wantErr: true,
+		},
+		{
+			name:    "wrong type",
+			data:    `{"NewWorker": 1234}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := schema.Validate([]byte(tt.data))
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
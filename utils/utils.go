@@ -2,7 +2,10 @@ package utils
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/filecoin-project/go-state-types/abi"
 	"github.com/filecoin-project/venus/venus-shared/types"
@@ -30,3 +33,41 @@ func GetMethodMeta(actorCode cid.Cid, method abi.MethodNum) (utils.MethodMeta, e
 	}
 	return methodMeta, nil
 }
+
+// GetMethodByName looks up the method of actor by name, case insensitive
+func GetMethodByName(actorCode cid.Cid, name string) (abi.MethodNum, utils.MethodMeta, error) {
+	for num, meta := range utils.MethodsMap[actorCode] {
+		if strings.EqualFold(meta.Name, name) {
+			return num, meta, nil
+		}
+	}
+	return 0, utils.MethodMeta{}, fmt.Errorf("method %s not found on actor %s", name, actorCode)
+}
+
+// LoadJSON reads the json file into v, returns false if the file does not exist
+func LoadJSON(path string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return false, fmt.Errorf("parse %s failed: %s", path, err)
+	}
+	return true, nil
+}
+
+// SaveJSON writes v into the json file, through a temp file to avoid corrupting it
+func SaveJSON(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}